@@ -0,0 +1,246 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// archiveFormat returns the format --archive-to writes, from the extension
+// of the archive: tar, tar.gz or zip
+func archiveFormat(name string) (string, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz", nil
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar", nil
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip", nil
+	}
+	return "", fmt.Errorf("unknown archive format: %s (expected .tar, .tar.gz, .tgz or .zip)", name)
+}
+
+// archiveWriter adds files to an archive
+type archiveWriter interface {
+	add(name string, info fs.FileInfo, path string) error
+	Close() error
+}
+
+// ArchiveMatches packs the matched files into an archive at their paths
+// relative to the directory they were found in, as --copy-to would lay
+// them out, keeping their mtimes and permissions. Files are streamed into
+// the archive one at a time, and symbolic links are stored as links. With
+// dryRun set the names are only printed. It returns the exit code.
+func ArchiveMatches(matches <-chan search.Match, archive string, dryRun bool, w io.Writer) int {
+	format, err := archiveFormat(archive)
+	if err != nil {
+		logger.Error("cannot archive", "err", err)
+		return exitFailure
+	}
+	self, err := filepath.Abs(archive)
+	if err != nil {
+		logger.Error("cannot archive", "err", err)
+		return exitFailure
+	}
+
+	// The archive may be written inside a searched directory, so every
+	// match is found before it is created
+	var files []search.Match
+	for match := range matches {
+		if match.IsDir() {
+			logger.Info("skipping", "path", match.Path, "reason", "a directory")
+			continue
+		}
+		if abs, err := filepath.Abs(match.Path); err == nil && abs == self {
+			continue
+		}
+		files = append(files, match)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	code := exitMatch
+	names := make(map[string]string, len(files))
+	kept := files[:0]
+	for _, file := range files {
+		name, err := archiveName(file)
+		if err != nil {
+			logger.Error("cannot archive", "path", file.Path, "err", err)
+			code = exitFailure
+			continue
+		}
+		names[file.Path] = name
+		kept = append(kept, file)
+	}
+	files = kept
+	if dryRun {
+		for _, file := range files {
+			fmt.Fprintf(w, "Would archive: %s -> %s\n", file.Path, names[file.Path])
+		}
+		return code
+	}
+
+	out, err := os.Create(archive)
+	if err != nil {
+		logger.Error("cannot archive", "err", err)
+		return exitFailure
+	}
+	var aw archiveWriter
+	switch format {
+	case "zip":
+		aw = &zipArchive{zw: zip.NewWriter(out)}
+	case "tar.gz":
+		gz := gzip.NewWriter(out)
+		aw = &tarArchive{tw: tar.NewWriter(gz), gz: gz}
+	default:
+		aw = &tarArchive{tw: tar.NewWriter(out)}
+	}
+
+	for _, file := range files {
+		info, err := os.Lstat(file.Path)
+		if err == nil {
+			err = aw.add(names[file.Path], info, file.Path)
+		}
+		if err != nil {
+			logger.Error("cannot archive", "path", file.Path, "err", err)
+			code = exitFailure
+			// A file cut short leaves the archive unusable
+			if errors.Is(err, errArchiveBroken) {
+				break
+			}
+		}
+	}
+	if err := errors.Join(aw.Close(), out.Close()); err != nil {
+		logger.Error("cannot archive", "err", err)
+		return exitFailure
+	}
+	if code == exitMatch {
+		fmt.Fprintf(w, "Archived %d files to %s\n", len(files), archive)
+	}
+	return code
+}
+
+// archiveName returns the name of a match inside the archive: its path
+// below the destination, slash separated. Names extracting outside the
+// current directory are an error, whichever separator the tool extracting
+// them splits on.
+func archiveName(match search.Match) (string, error) {
+	rel, err := transferPath(match)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.ToSlash(rel)
+	elems := strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' })
+	if strings.ContainsAny(name[:1], `/\`) || slices.Contains(elems, "..") {
+		return "", fmt.Errorf("%s has no local name in the archive", match.Path)
+	}
+	return name, nil
+}
+
+// errArchiveBroken marks errors that happened while a file was written to
+// the archive, after which nothing more can be added
+var errArchiveBroken = errors.New("the archive is incomplete")
+
+// tarArchive writes a tar archive, gzipped when gz is set
+type tarArchive struct {
+	tw *tar.Writer
+	gz *gzip.Writer
+}
+
+func (a *tarArchive) add(name string, info fs.FileInfo, path string) error {
+	link := ""
+	if info.Mode()&fs.ModeSymlink != 0 {
+		var err error
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if !info.Mode().IsRegular() {
+		return a.tw.WriteHeader(header)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := a.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	// The header promised the size seen by Lstat, a file that changed
+	// since can't be written
+	n, err := io.Copy(a.tw, file)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errArchiveBroken, err)
+	}
+	if n != header.Size {
+		return fmt.Errorf("%w: %s shrank while it was read", errArchiveBroken, path)
+	}
+	return nil
+}
+
+func (a *tarArchive) Close() error {
+	err := a.tw.Close()
+	if a.gz != nil {
+		err = errors.Join(err, a.gz.Close())
+	}
+	return err
+}
+
+// zipArchive writes a zip archive, deflating the files
+type zipArchive struct {
+	zw *zip.Writer
+}
+
+func (a *zipArchive) add(name string, info fs.FileInfo, path string) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	var src io.Reader
+	if info.Mode()&fs.ModeSymlink != 0 {
+		// Zip stores the target of a link as its content
+		link, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		src = strings.NewReader(link)
+	} else if info.Mode().IsRegular() {
+		header.Method = zip.Deflate
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		src = file
+	} else {
+		return fmt.Errorf("cannot store a %s in a zip archive", info.Mode().Type())
+	}
+	dst, err := a.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("%w: %v", errArchiveBroken, err)
+	}
+	return nil
+}
+
+func (a *zipArchive) Close() error {
+	return a.zw.Close()
+}
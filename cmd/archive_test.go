@@ -0,0 +1,71 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sean1832/go-search/search"
+)
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		root, path string
+		want       string // empty for an error
+	}{
+		{"src", "src/a/b.txt", "a/b.txt"},
+		{"", "/etc/passwd", "etc/passwd"},
+		{"", "../../x", "x"},
+		{"", "..", ""},
+		{"", ".", ""},
+	}
+	if runtime.GOOS != "windows" {
+		// Backslashes are part of names here, but separators elsewhere
+		tests = append(tests,
+			struct{ root, path, want string }{"", `a\b`, `a\b`},
+			struct{ root, path, want string }{"", `\x`, "x"},
+			struct{ root, path, want string }{"src", `src/\x`, ""},
+			struct{ root, path, want string }{"", `a\..\..\x`, ""},
+			struct{ root, path, want string }{"src", `src/..\x`, ""},
+		)
+	}
+	for _, tt := range tests {
+		match := search.Match{Path: filepath.FromSlash(tt.path), Root: filepath.FromSlash(tt.root)}
+		got, err := archiveName(match)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("archiveName(%q under %q) = %q, want an error", tt.path, tt.root, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("archiveName(%q under %q): %v", tt.path, tt.root, err)
+		} else if got != tt.want {
+			t.Errorf("archiveName(%q under %q) = %q, want %q", tt.path, tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestTarArchiveShrunkFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Truncated between the Lstat and the copy, as a rotated log would be
+	if err := os.Truncate(path, 4); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	a := &tarArchive{tw: tar.NewWriter(&buf)}
+	if err := a.add("log.txt", info, path); !errors.Is(err, errArchiveBroken) {
+		t.Errorf("add of a shrunk file = %v, want errArchiveBroken", err)
+	}
+}
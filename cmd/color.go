@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Default colors used when LS_COLORS doesn't define a type
+var defaultColors = map[string]string{
+	"di": "01;34", // directory
+	"ln": "01;36", // symbolic link
+	"ex": "01;32", // executable file
+}
+
+// Color of the highlighted portion of a matched name
+const highlightColor = "01;31"
+
+// colorizer renders paths with ANSI colors following LS_COLORS
+type colorizer struct {
+	types   map[string]string // keyed by LS_COLORS type code
+	exts    map[string]string // keyed by lowercase extension, including the dot
+	spanner search.Spanner
+}
+
+// newColorizer returns a colorizer for the --color mode, or nil when output
+// should not be colored
+func newColorizer(mode string, spanner search.Spanner) (*colorizer, error) {
+	switch mode {
+	case "", "auto":
+		if os.Getenv("NO_COLOR") != "" || !isTerminal(os.Stdout) {
+			return nil, nil
+		}
+	case "always":
+	case "never":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid color mode: %s", mode)
+	}
+
+	c := &colorizer{types: make(map[string]string), exts: make(map[string]string), spanner: spanner}
+	for code, color := range defaultColors {
+		c.types[code] = color
+	}
+	c.parseLSColors(os.Getenv("LS_COLORS"))
+	return c, nil
+}
+
+// isTerminal reports whether the file is a character device such as a TTY
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// parseLSColors reads entries like "di=01;34:*.tar=01;31"
+func (c *colorizer) parseLSColors(value string) {
+	for _, entry := range strings.Split(value, ":") {
+		key, color, ok := strings.Cut(entry, "=")
+		if !ok || color == "" {
+			continue
+		}
+		if strings.HasPrefix(key, "*.") {
+			c.exts[strings.ToLower(key[1:])] = color
+		} else {
+			c.types[key] = color
+		}
+	}
+}
+
+// colorFor picks the color for a match's base name
+func (c *colorizer) colorFor(match search.Match) string {
+	switch {
+	case match.IsDir():
+		return c.types["di"]
+	case match.Type == search.TypeSymlink:
+		return c.types["ln"]
+	case match.Mode&os.ModeNamedPipe != 0:
+		return c.types["pi"]
+	case match.Mode&os.ModeSocket != 0:
+		return c.types["so"]
+	case match.IsExecutable():
+		return c.types["ex"]
+	}
+	if color, ok := c.exts[strings.ToLower(filepath.Ext(match.Path))]; ok {
+		return color
+	}
+	return c.types["fi"]
+}
+
+// Path renders the match path, coloring the parent directories like a
+// directory and the base name by its type, with matched text highlighted
+func (c *colorizer) Path(match search.Match) string {
+	dir, name := filepath.Split(match.Path)
+	return c.Dir(dir) + c.Name(match, name)
+}
+
+// Dir renders directory names that weren't matched themselves
+func (c *colorizer) Dir(text string) string {
+	return paint(text, c.types["di"])
+}
+
+// Name renders the base name of a match colored by its type, with matched
+// text highlighted
+func (c *colorizer) Name(match search.Match, name string) string {
+	var buf strings.Builder
+	color := c.colorFor(match)
+	var spans [][2]int
+	if c.spanner != nil {
+		spans = c.spanner.Spans(name)
+	}
+	last := 0
+	for _, span := range spans {
+		if span[0] < last || span[1] <= span[0] {
+			continue
+		}
+		buf.WriteString(paint(name[last:span[0]], color))
+		buf.WriteString(paint(name[span[0]:span[1]], highlightColor))
+		last = span[1]
+	}
+	buf.WriteString(paint(name[last:], color))
+	return buf.String()
+}
+
+// paint wraps text in an ANSI color sequence
+func paint(text, color string) string {
+	if text == "" || color == "" {
+		return text
+	}
+	return "\x1b[" + color + "m" + text + "\x1b[0m"
+}
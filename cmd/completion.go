@@ -0,0 +1,351 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Kinds of values a flag takes, deciding how its argument is completed
+const (
+	completeNone  = iota // a boolean flag
+	completeValue        // a free form value
+	completeWords        // one of a fixed list of words
+	completeDir          // a directory
+	completeFile         // a file
+)
+
+// completionFlag describes a flag of the search command for completion
+type completionFlag struct {
+	short string
+	long  string
+	kind  int
+	words []string
+	help  string
+}
+
+// Subcommands offered as the first argument
+var completionSubcommands = []string{"index", "serve", "daemon", "dupes", "diff", "save", "run", "config", "completion"}
+
+// completionFlags lists every flag of the search command
+var completionFlags = []completionFlag{
+	{"f", "file", completeNone, nil, "Only return files"},
+	{"d", "dir", completeNone, nil, "Only return directories"},
+	{"t", "type", completeWords, []string{"f", "d", "l", "s", "p", "x", "e", "b"}, "Only return the given types"},
+	{"", "empty", completeNone, nil, "Only return empty files and directories"},
+	{"", "broken", completeNone, nil, "Only return broken symlinks"},
+	{"c", "casesensitive", completeNone, nil, "Make the search case-sensitive"},
+	{"S", "smart-case", completeNone, nil, "Case-sensitive only if the pattern contains uppercase letters"},
+	{"", "no-smart-case", completeNone, nil, "Turn off smart case set in the config file"},
+	{"", "normalize", completeWords, []string{"nfc", "nfd"}, "Unicode form patterns and names are compared in"},
+	{"", "no-normalize", completeNone, nil, "Compare names byte for byte"},
+	{"e", "regex", completeNone, nil, "Interpret the pattern as a regular expression"},
+	{"F", "fixed", completeNone, nil, "Match names containing the pattern as a plain string"},
+	{"p", "full-path", completeNone, nil, "Match the pattern against the relative path"},
+	{"", "pattern", completeValue, nil, "Match this pattern, repeatable"},
+	{"", "all", completeNone, nil, "Only return entries matching every pattern"},
+	{"v", "invert", completeNone, nil, "Return the entries not matching the pattern"},
+	{"", "queries", completeFile, nil, "Match every pattern of the file, tagging matches with them"},
+	{"", "query", completeValue, nil, "Only return entries matching the expression"},
+	{"", "content", completeValue, nil, "Search the contents of matching files"},
+	{"j", "jobs", completeValue, nil, "Number of parallel workers"},
+	{"x", "exclude", completeValue, nil, "Leave names matching the glob out of the results"},
+	{"", "prune", completeValue, nil, "Don't descend into directories matching the glob"},
+	{"", "format", completeWords, []string{"text", "json", "jsonl", "csv", "tsv"}, "Output format"},
+	{"0", "print0", completeNone, nil, "Separate results with NUL bytes"},
+	{"", "exec", completeValue, nil, "Run a command for each match"},
+	{"", "exec-batch", completeValue, nil, "Run a command once with all matches as arguments"},
+	{"", "size", completeValue, nil, "Only return files of the given size"},
+	{"", "no-ignore", completeNone, nil, "Don't respect .gitignore, .ignore, .searchignore and global git excludes"},
+	{"", "ignore", completeNone, nil, "Respect ignore files when the config file sets no_ignore"},
+	{"", "ignore-file", completeFile, nil, "Also skip paths matching the rules in the gitignore-style file"},
+	{"H", "hidden", completeNone, nil, "Include hidden files and directories"},
+	{"", "no-hidden", completeNone, nil, "Skip hidden files and directories when the config file includes them"},
+	{"", "max-depth", completeValue, nil, "Descend at most N directory levels below the root"},
+	{"", "min-depth", completeValue, nil, "Only return entries at least N levels below the root"},
+	{"", "max-dir-entries", completeValue, nil, "Don't descend into directories holding more than N entries"},
+	{"", "newer-than", completeValue, nil, "Only return entries modified after the time"},
+	{"", "older-than", completeValue, nil, "Only return entries modified before the time"},
+	{"", "changed-within", completeValue, nil, "Only return entries whose status changed after the time"},
+	{"", "changed-before", completeValue, nil, "Only return entries whose status last changed before the time"},
+	{"", "accessed-within", completeValue, nil, "Only return entries accessed after the time"},
+	{"", "accessed-before", completeValue, nil, "Only return entries last accessed before the time"},
+	{"", "perm", completeValue, nil, "Only return entries with the mode bits"},
+	{"", "owner", completeValue, nil, "Only return entries owned by the user"},
+	{"", "group", completeValue, nil, "Only return entries owned by the group"},
+	{"", "mime", completeValue, nil, "Only return files whose content is of the type"},
+	{"", "preset", completeWords, search.BuiltinPresets(), "Only return files of a kind"},
+	{"", "lang", completeWords, search.Languages(), "Only return source files in the language"},
+	{"", "xattr", completeValue, nil, "Only return entries with the extended attribute"},
+	{"", "with-xattrs", completeNone, nil, "Include extended attributes in the json, jsonl and template formats"},
+	{"", "with-acl", completeNone, nil, "Include the POSIX ACL of matches in the output"},
+	{"", "with-secontext", completeNone, nil, "Include the SELinux context of matches in the output"},
+	{"", "with-errors", completeNone, nil, "Include skipped paths in the json and jsonl output"},
+	{"", "git-rev", completeValue, nil, "Match the paths of a git revision instead of the disk"},
+	{"", "git-tracked", completeNone, nil, "Only return files tracked by git"},
+	{"", "git-modified", completeNone, nil, "Only return files changed in the git working tree or index"},
+	{"", "git-untracked", completeNone, nil, "Only return files untracked by git and not ignored"},
+	{"", "filter-cmd", completeValue, nil, "Only return entries the command accepts"},
+	{"", "filter-plugin", completeFile, nil, "Only return entries the Filter of the Go plugin accepts"},
+	{"", "all-drives", completeNone, nil, "Search every local drive (Windows only)"},
+	{"X", "one-file-system", completeNone, nil, "Don't descend into directories on other filesystems"},
+	{"", "unique-inodes", completeNone, nil, "Report files with several hard links only once"},
+	{"", "no-dedupe", completeNone, nil, "Print a path found several times each time"},
+	{"L", "follow", completeNone, nil, "Follow symbolic links"},
+	{"", "no-follow", completeNone, nil, "Don't follow symbolic links when the config file does"},
+	{"", "strategy", completeWords, []string{"dfs", "bfs"}, "Walk depth first or breadth first"},
+	{"", "max-symlink-depth", completeValue, nil, "Follow at most N nested symbolic links"},
+	{"", "fuzzy", completeNone, nil, "Fuzzy match names against the pattern"},
+	{"", "fuzzy-threshold", completeValue, nil, "Minimum fuzzy score a name must reach"},
+	{"", "approx", completeValue, nil, "Match names within N typos of the pattern"},
+	{"", "sort", completeWords, []string{"name", "size", "mtime", "depth", "score"}, "Sort results by key"},
+	{"", "reverse", completeNone, nil, "Reverse the sort order"},
+	{"", "max-memory", completeValue, nil, "Sort at most this much in memory"},
+	{"", "color", completeWords, []string{"auto", "always", "never"}, "Color output"},
+	{"", "use-index", completeNone, nil, "Answer from the index built by the index subcommand"},
+	{"", "db", completeFile, nil, "Answer from an mlocate database written by updatedb"},
+	{"", "backend", completeWords, []string{"walk", "mft"}, "Read NTFS volumes from their Master File Table"},
+	{"", "no-daemon", completeNone, nil, "Search without a running daemon"},
+	{"", "max-results", completeValue, nil, "Stop after N matches"},
+	{"", "throttle", completeValue, nil, "Match at most N entries a second, or read at most N[kMG] bytes a second"},
+	{"", "io-nice", completeNone, nil, "Run with the lowest CPU and I/O priority"},
+	{"", "timeout", completeValue, nil, "Stop searching after the duration"},
+	{"1", "", completeNone, nil, "Stop after the first match"},
+	{"", "files-from", completeFile, nil, "Match the paths listed in the file"},
+	{"", "absolute", completeNone, nil, "Print absolute, cleaned paths"},
+	{"", "relative-to", completeDir, nil, "Print paths relative to the directory"},
+	{"", "raw-paths", completeNone, nil, "Print extended-length Windows paths as is"},
+	{"l", "long", completeNone, nil, "Print permissions, owner, group, size and mtime"},
+	{"", "human", completeNone, nil, "Print sizes with units in the long or du listing"},
+	{"", "du", completeNone, nil, "Print the total size of the files below each matched directory"},
+	{"", "tree", completeNone, nil, "Print the matches as a tree below each directory"},
+	{"", "group-by", completeWords, []string{"dir"}, "Print each directory once, followed by its matches indented"},
+	{"", "report", completeWords, []string{"age-histogram", "size-histogram"}, "Print a summary of the matched files instead of them"},
+	{"", "template", completeValue, nil, "Print each match with a Go text/template"},
+	{"", "hash", completeWords, []string{"sha256", "md5", "xxh64"}, "Print the checksum of each matched file"},
+	{"", "interactive", completeNone, nil, "Narrow the results by typing"},
+	{"", "open", completeNone, nil, "Open the matches in $VISUAL or $EDITOR"},
+	{"", "open-with", completeValue, nil, "Open the matches with this command"},
+	{"", "rename", completeValue, nil, "Rename matches by a regex substitution on their names"},
+	{"", "rename-to", completeValue, nil, "Rename matches to a template"},
+	{"", "delete", completeNone, nil, "Delete the matched files, asking before each one"},
+	{"", "delete-empty-dirs", completeNone, nil, "Delete the matched directories that are empty"},
+	{"", "force", completeNone, nil, "Delete without asking"},
+	{"", "copy-to", completeDir, nil, "Copy the matched files below the directory"},
+	{"", "move-to", completeDir, nil, "Move the matched files below the directory"},
+	{"", "archive-to", completeFile, nil, "Pack the matched files into a .tar, .tar.gz or .zip archive"},
+	{"", "on-conflict", completeWords, []string{"skip", "overwrite", "rename"}, "What to do with existing files when copying or moving"},
+	{"", "replace", completeValue, nil, "Replace the content matches in the files"},
+	{"", "backup-suffix", completeValue, nil, "Keep the original of each file changed by --replace"},
+	{"", "dry-run", completeNone, nil, "Print what --rename, --delete or --replace would do without doing it"},
+	{"", "count", completeNone, nil, "Only print the number of matches"},
+	{"q", "quiet", completeNone, nil, "Print nothing, only set the exit status"},
+	{"", "verbose", completeNone, nil, "Report skipped paths on stderr"},
+	{"", "log-level", completeWords, []string{"debug", "info", "warn", "error"}, "Log diagnostics on stderr from this level on"},
+	{"", "log-format", completeWords, []string{"text", "json"}, "Format of the diagnostics"},
+	{"", "stats", completeNone, nil, "Print a summary of the work done when finished"},
+	{"", "no-progress", completeNone, nil, "Don't show a progress line during long searches"},
+	{"", "no-cache", completeNone, nil, "Don't reuse or save cached directory listings"},
+	{"", "config", completeFile, nil, "Read defaults from this config file"},
+	{"", "no-config", completeNone, nil, "Don't read the config file"},
+	{"h", "help", completeNone, nil, "Display the help message"},
+}
+
+// names returns the flag spelled as on the command line, short form first
+func (f completionFlag) names() []string {
+	var names []string
+	if f.short != "" {
+		names = append(names, "-"+f.short)
+	}
+	if f.long != "" {
+		names = append(names, "--"+f.long)
+	}
+	return names
+}
+
+// runCompletion implements the completion subcommand
+func runCompletion(program string, args []string) int {
+	if len(args) != 1 || args[0] == "-h" || args[0] == "--help" {
+		displayCompletionHelp(program)
+		if len(args) == 1 {
+			return exitMatch
+		}
+		return exitUsage
+	}
+
+	name := strings.TrimSuffix(filepath.Base(program), ".exe")
+	var script string
+	switch args[0] {
+	case "bash":
+		script = bashCompletion(name)
+	case "zsh":
+		script = zshCompletion(name)
+	case "fish":
+		script = fishCompletion(name)
+	case "powershell":
+		script = powershellCompletion(name)
+	default:
+		fmt.Fprintln(os.Stderr, "Error: unknown shell:", args[0])
+		displayCompletionHelp(program)
+		return exitUsage
+	}
+	os.Stdout.WriteString(script)
+	return exitMatch
+}
+
+// bashCompletion returns a completion function for bash
+func bashCompletion(name string) string {
+	var b strings.Builder
+	var all []string
+	fn := "_" + strings.ReplaceAll(name, "-", "_")
+	fmt.Fprintf(&b, "# bash completion for %s, load with: source <(%s completion bash)\n", name, name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("    case \"$prev\" in\n")
+	for _, f := range completionFlags {
+		all = append(all, f.names()...)
+		pattern := strings.Join(f.names(), "|")
+		switch f.kind {
+		case completeWords:
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")); return ;;\n", pattern, strings.Join(f.words, " "))
+		case completeDir:
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -d -- \"$cur\")); return ;;\n", pattern)
+		case completeFile:
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", pattern)
+		case completeValue:
+			fmt.Fprintf(&b, "        %s) return ;;\n", pattern)
+		}
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(all, " "))
+	b.WriteString("    elif [[ $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\") $(compgen -d -- \"$cur\"))\n", strings.Join(completionSubcommands, " "))
+	b.WriteString("    else\n")
+	b.WriteString("        COMPREPLY=($(compgen -d -- \"$cur\"))\n")
+	b.WriteString("    fi\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -o filenames -F %s %s\n", fn, name)
+	return b.String()
+}
+
+// zshCompletion returns a completion function for zsh
+func zshCompletion(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n", name)
+	fmt.Fprintf(&b, "# zsh completion for %s, load with: source <(%s completion zsh)\n", name, name)
+	fmt.Fprintf(&b, "_%s() {\n", name)
+	b.WriteString("    _arguments -s \\\n")
+	escape := strings.NewReplacer("[", "\\[", "]", "\\]", ":", "\\:", "'", "'\\''")
+	for _, f := range completionFlags {
+		var action string
+		switch f.kind {
+		case completeWords:
+			action = fmt.Sprintf(":%s:(%s)", f.long, strings.Join(f.words, " "))
+		case completeDir:
+			action = fmt.Sprintf(":%s:_files -/", f.long)
+		case completeFile:
+			action = fmt.Sprintf(":%s:_files", f.long)
+		case completeValue:
+			action = fmt.Sprintf(":%s: ", f.long)
+		}
+		for _, flag := range f.names() {
+			fmt.Fprintf(&b, "        '%s[%s]%s' \\\n", flag, escape.Replace(f.help), action)
+		}
+	}
+	fmt.Fprintf(&b, "        '1: :_alternative \"subcommands:subcommand:(%s)\" \"directories:directory:_files -/\"' \\\n",
+		strings.Join(completionSubcommands, " "))
+	b.WriteString("        '*:directory:_files -/'\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "compdef _%s %s\n", name, name)
+	return b.String()
+}
+
+// fishCompletion returns completions for fish
+func fishCompletion(name string) string {
+	var b strings.Builder
+	quote := strings.NewReplacer("\\", "\\\\", "'", "\\'")
+	fmt.Fprintf(&b, "# fish completion for %s, load with: %s completion fish | source\n", name, name)
+	fmt.Fprintf(&b, "complete -c %s -f -a '(__fish_complete_directories)'\n", name)
+	fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a '%s'\n", name, strings.Join(completionSubcommands, " "))
+	for _, f := range completionFlags {
+		fmt.Fprintf(&b, "complete -c %s", name)
+		if f.short != "" {
+			fmt.Fprintf(&b, " -s %s", f.short)
+		}
+		if f.long != "" {
+			fmt.Fprintf(&b, " -l %s", f.long)
+		}
+		switch f.kind {
+		case completeWords:
+			fmt.Fprintf(&b, " -x -a '%s'", strings.Join(f.words, " "))
+		case completeDir:
+			b.WriteString(" -x -a '(__fish_complete_directories)'")
+		case completeFile:
+			b.WriteString(" -r -F")
+		case completeValue:
+			b.WriteString(" -x")
+		}
+		fmt.Fprintf(&b, " -d '%s'\n", quote.Replace(f.help))
+	}
+	return b.String()
+}
+
+// powershellCompletion returns an argument completer for PowerShell
+func powershellCompletion(name string) string {
+	var b strings.Builder
+	quote := strings.NewReplacer("'", "''")
+	fmt.Fprintf(&b, "# PowerShell completion for %s, load with: %s completion powershell | Out-String | Invoke-Expression\n", name, name)
+	fmt.Fprintf(&b, "Register-ArgumentCompleter -Native -CommandName '%s', '%s.exe' -ScriptBlock {\n", name, name)
+	b.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	b.WriteString("    $flags = @(\n")
+	for _, f := range completionFlags {
+		for _, flag := range f.names() {
+			fmt.Fprintf(&b, "        @('%s', '%s')\n", flag, quote.Replace(f.help))
+		}
+	}
+	b.WriteString("    )\n")
+	b.WriteString("    $values = @{\n")
+	for _, f := range completionFlags {
+		if f.kind != completeWords {
+			continue
+		}
+		for _, flag := range f.names() {
+			fmt.Fprintf(&b, "        '%s' = @('%s')\n", flag, strings.Join(f.words, "', '"))
+		}
+	}
+	b.WriteString("    }\n")
+	b.WriteString("    $elements = $commandAst.CommandElements | ForEach-Object { $_.ToString() }\n")
+	b.WriteString("    $prev = if ($wordToComplete) { $elements[-2] } else { $elements[-1] }\n")
+	b.WriteString("    if ($values.ContainsKey($prev)) {\n")
+	b.WriteString("        $values[$prev] | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	b.WriteString("            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	b.WriteString("        }\n")
+	b.WriteString("    } elseif ($wordToComplete -like '-*') {\n")
+	b.WriteString("        $flags | Where-Object { $_[0] -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	b.WriteString("            [System.Management.Automation.CompletionResult]::new($_[0], $_[0], 'ParameterName', $_[1])\n")
+	b.WriteString("        }\n")
+	b.WriteString("    } else {\n")
+	b.WriteString("        if ($elements.Count -le 2) {\n")
+	fmt.Fprintf(&b, "            @('%s') | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n", strings.Join(completionSubcommands, "', '"))
+	b.WriteString("                [System.Management.Automation.CompletionResult]::new($_, $_, 'Command', $_)\n")
+	b.WriteString("            }\n")
+	b.WriteString("        }\n")
+	b.WriteString("        Get-ChildItem -Directory -Path \"$wordToComplete*\" -ErrorAction SilentlyContinue | ForEach-Object {\n")
+	b.WriteString("            $path = Resolve-Path -Relative $_.FullName\n")
+	b.WriteString("            [System.Management.Automation.CompletionResult]::new($path, $path, 'ProviderContainer', $path)\n")
+	b.WriteString("        }\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// displayCompletionHelp prints usage instructions for the completion subcommand
+func displayCompletionHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s completion <bash|zsh|fish|powershell>\n", program)
+	fmt.Fprintln(os.Stderr, "Prints a completion script for the shell, covering every flag, the")
+	fmt.Fprintln(os.Stderr, "--type, --format, --sort and --color values and directory arguments.")
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// config holds defaults loaded from the config file. Flags given on the
+// command line take precedence.
+type config struct {
+	excludes    []string
+	prunes      []string
+	color       string
+	jobs        int
+	noIgnore    bool
+	showHidden  bool
+	smartCase   bool
+	follow      bool
+	openConfirm int
+	presets     map[string]search.Preset // preset.<name> keys
+}
+
+// configTemplate is written by config init
+const configTemplate = `# go-search configuration. Flags given on the command line override
+# these values; run with --no-config to ignore this file.
+
+# Names to leave out of every search, as with --exclude
+# exclude = ["*.tmp"]
+
+# Directories never to descend into, as with --prune
+# prune = [".git", "node_modules"]
+
+# Color output: "auto", "always" or "never"
+# color = "auto"
+
+# Number of parallel workers, the number of CPUs by default
+# jobs = 8
+
+# Don't respect .gitignore, .ignore, .searchignore and global git excludes
+# no_ignore = false
+
+# Include hidden files and directories
+# hidden = false
+
+# Case-sensitive only if the pattern contains uppercase letters
+# smart_case = false
+
+# Follow symbolic links
+# follow = false
+
+# Ask before --open opens more than this many files
+# open_confirm = 10
+
+# Presets for --preset, of extensions, globs or MIME types; a built-in
+# preset (code, images, video, audio, docs) of the same name is replaced
+# preset.images = ["jpg", "png", "*.raw.*", "image/*"]
+`
+
+// defaultConfigPath returns where the config file is looked for when
+// --config isn't given
+func defaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-search", "config.toml"), nil
+}
+
+// loadConfig reads the config file at path, or at the default location
+// when path is empty, in which case a missing file is not an error
+func loadConfig(path string) (*config, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = defaultConfigPath(); err != nil {
+			return &config{}, nil
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return &config{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	cfg, err := parseConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// configFromArgs loads the config file selected by --config, relative to
+// dir when it is set, or the default one, returning nil with --no-config
+func configFromArgs(dir string, args []string) (*config, error) {
+	path := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--no-config":
+			return nil, nil
+		case "--config":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			path = pathIn(dir, value)
+		}
+	}
+	return loadConfig(path)
+}
+
+// parseConfig reads the subset of TOML used by the config file: top level
+// keys holding strings, integers, booleans or arrays of strings
+func parseConfig(r io.Reader) (*config, error) {
+	cfg := &config{}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		// Arrays may span several lines
+		for strings.HasPrefix(value, "[") && !strings.HasSuffix(value, "]") && scanner.Scan() {
+			lineNo++
+			value += " " + strings.TrimSpace(stripComment(scanner.Text()))
+		}
+		if err := cfg.set(key, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	return cfg, scanner.Err()
+}
+
+// set assigns a single key from its TOML value
+func (cfg *config) set(key, value string) error {
+	var err error
+	switch key {
+	case "exclude":
+		cfg.excludes, err = parseStringArray(value)
+	case "prune":
+		cfg.prunes, err = parseStringArray(value)
+	case "color":
+		cfg.color, err = parseString(value)
+	case "jobs":
+		cfg.jobs, err = strconv.Atoi(value)
+		if err == nil && cfg.jobs < 1 {
+			err = fmt.Errorf("jobs must be at least 1")
+		}
+	case "no_ignore":
+		cfg.noIgnore, err = strconv.ParseBool(value)
+	case "hidden":
+		cfg.showHidden, err = strconv.ParseBool(value)
+	case "smart_case":
+		cfg.smartCase, err = strconv.ParseBool(value)
+	case "follow":
+		cfg.follow, err = strconv.ParseBool(value)
+	case "open_confirm":
+		cfg.openConfirm, err = strconv.Atoi(value)
+		if err == nil && cfg.openConfirm < 1 {
+			err = fmt.Errorf("open_confirm must be at least 1")
+		}
+	default:
+		name, ok := strings.CutPrefix(key, "preset.")
+		if !ok || name == "" {
+			return fmt.Errorf("unknown key: %s", key)
+		}
+		entries, err := parseStringArray(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+		preset, err := search.ParsePreset(name, entries)
+		if err != nil {
+			return err
+		}
+		if cfg.presets == nil {
+			cfg.presets = make(map[string]search.Preset)
+		}
+		cfg.presets[name] = preset
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %s", key, value)
+	}
+	return nil
+}
+
+// apply sets the config values as defaults of the options
+func (cfg *config) apply(opts *Options) {
+	opts.excludes = append(opts.excludes, cfg.excludes...)
+	opts.prunes = append(opts.prunes, cfg.prunes...)
+	opts.color = cfg.color
+	if cfg.jobs > 0 {
+		opts.jobs = cfg.jobs
+	}
+	opts.noIgnore = cfg.noIgnore
+	opts.showHidden = cfg.showHidden
+	opts.isSmartCase = cfg.smartCase
+	opts.follow = cfg.follow
+	if cfg.openConfirm > 0 {
+		opts.openConfirm = cfg.openConfirm
+	}
+	opts.customPresets = cfg.presets
+}
+
+// stripComment removes a # comment that isn't inside a string
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true // only basic strings have escapes
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseString parses a basic "..." or literal '...' TOML string
+func parseString(value string) (string, error) {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1], nil
+	}
+	return strconv.Unquote(value)
+}
+
+// parseStringArray parses a TOML array of strings
+func parseStringArray(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, errors.New("expected an array")
+	}
+	var values []string
+	var quote rune
+	escaped := false
+	start := 1
+	add := func(item string) error {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil // trailing comma
+		}
+		s, err := parseString(item)
+		if err == nil {
+			values = append(values, s)
+		}
+		return err
+	}
+	for i, r := range value[1 : len(value)-1] {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			if err := add(value[start : i+1]); err != nil {
+				return nil, err
+			}
+			start = i + 2
+		}
+	}
+	if err := add(value[start : len(value)-1]); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// runConfig implements the config subcommand
+func runConfig(program string, args []string) int {
+	if len(args) == 0 || args[0] != "init" {
+		displayConfigHelp(program)
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			return exitMatch
+		}
+		return exitUsage
+	}
+
+	path := ""
+	force := false
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--config":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+			path = value
+		case "--force":
+			force = true
+		default:
+			fmt.Fprintln(os.Stderr, "Error: unknown argument:", args[i])
+			displayConfigHelp(program)
+			return exitUsage
+		}
+	}
+	if path == "" {
+		var err error
+		if path, err = defaultConfigPath(); err != nil {
+			logger.Error("cannot find the config directory", "err", err)
+			return exitFailure
+		}
+	}
+
+	if _, err := os.Stat(path); err == nil && !force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists, use --force to overwrite it\n", path)
+		return exitFailure
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		logger.Error("cannot write the config", "path", path, "err", err)
+		return exitFailure
+	}
+	if err := os.WriteFile(path, []byte(configTemplate), 0o644); err != nil {
+		logger.Error("cannot write the config", "path", path, "err", err)
+		return exitFailure
+	}
+	fmt.Println("Wrote", path)
+	return exitMatch
+}
+
+// displayConfigHelp prints usage instructions for the config subcommand
+func displayConfigHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s config init [--config <path>] [--force]\n", program)
+	fmt.Fprintln(os.Stderr, "Writes a commented config file template, by default to the user config")
+	fmt.Fprintln(os.Stderr, "directory (go-search/config.toml).")
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStripComment(t *testing.T) {
+	tests := []struct {
+		line, want string
+	}{
+		{"jobs = 4", "jobs = 4"},
+		{"jobs = 4 # workers", "jobs = 4 "},
+		{"# only a comment", ""},
+		{`color = "#fff"`, `color = "#fff"`},
+		{`color = '#fff' # hex`, `color = '#fff' `},
+		{`x = "a \" # b" # c`, `x = "a \" # b" `},
+		{`x = "a \\" # b`, `x = "a \\" `},
+		{`x = 'a \' # b`, `x = 'a \' `},
+		{`x = ["a#", 'b#'] # c`, `x = ["a#", 'b#'] `},
+	}
+	for _, tt := range tests {
+		if got := stripComment(tt.line); got != tt.want {
+			t.Errorf("stripComment(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseString(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{`"plain"`, "plain", false},
+		{`"tab\there"`, "tab\there", false},
+		{`"quote \" inside"`, `quote " inside`, false},
+		{`"back\\slash"`, `back\slash`, false},
+		{`"caf\u00e9"`, "café", false},
+		{`'C:\Users\me'`, `C:\Users\me`, false},
+		{`'say "hi"'`, `say "hi"`, false},
+		{`''`, "", false},
+		{`""`, "", false},
+		{`plain`, "", true},
+		{`"unterminated`, "", true},
+		{`"bad \q escape"`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseString(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseString(%s) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseString(%s) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseStringArray(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{`[]`, nil, false},
+		{`["a"]`, []string{"a"}, false},
+		{`["a", 'b',]`, []string{"a", "b"}, false},
+		{`[ "a" , "b" ]`, []string{"a", "b"}, false},
+		{`["a,b", 'c,d']`, []string{"a,b", "c,d"}, false},
+		{`["a\",b", "c"]`, []string{`a",b`, "c"}, false},
+		{`["a\\", "b"]`, []string{`a\`, "b"}, false},
+		{`['a\', "b"]`, []string{`a\`, "b"}, false},
+		{`["[x]", "y"]`, []string{"[x]", "y"}, false},
+		{`"a"`, nil, true},
+		{`["a" "b"]`, nil, true},
+		{`[a, b]`, nil, true},
+		{`["a", "b]`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseStringArray(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseStringArray(%s) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parseStringArray(%s) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	text := `# defaults
+exclude = ["*.tmp", "a,b"] # trailing comment
+prune = [
+	".git", # VCS
+	"node_modules",
+]
+color = "never"
+jobs = 3
+no_ignore = true
+hidden = true
+smart_case = true
+follow = false
+open_confirm = 5
+preset.notes = ["md", "txt"]
+`
+	cfg, err := parseConfig(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.excludes, []string{"*.tmp", "a,b"}) || !slices.Equal(cfg.prunes, []string{".git", "node_modules"}) {
+		t.Errorf("excludes %q, prunes %q", cfg.excludes, cfg.prunes)
+	}
+	if cfg.color != "never" || cfg.jobs != 3 || !cfg.noIgnore || !cfg.showHidden || !cfg.smartCase || cfg.follow || cfg.openConfirm != 5 {
+		t.Errorf("parsed %+v", cfg)
+	}
+	if _, ok := cfg.presets["notes"]; !ok {
+		t.Errorf("preset notes missing: %v", cfg.presets)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"jobs", "line 1: expected key = value"},
+		{"\n\nbogus = 1", "line 3: unknown key: bogus"},
+		{"jobs = 0", "invalid value for jobs: 0"},
+		{"jobs = many", "invalid value for jobs"},
+		{"hidden = yes", "invalid value for hidden"},
+		{"color = never", "invalid value for color"},
+		{`exclude = "*.tmp"`, "invalid value for exclude"},
+		{"open_confirm = 0", "invalid value for open_confirm: 0"},
+		{"preset. = [\"x\"]", "unknown key: preset."},
+	}
+	for _, tt := range tests {
+		_, err := parseConfig(strings.NewReader(tt.text))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseConfig(%q) error = %v, want %q", tt.text, err, tt.want)
+		}
+	}
+}
+
+func TestConfigTemplate(t *testing.T) {
+	// The template parses as is, and so does each example it comments out
+	if _, err := parseConfig(strings.NewReader(configTemplate)); err != nil {
+		t.Errorf("template: %v", err)
+	}
+	for _, line := range strings.Split(configTemplate, "\n") {
+		example, ok := strings.CutPrefix(line, "# ")
+		if !ok || !strings.Contains(example, " = ") {
+			continue
+		}
+		if _, err := parseConfig(strings.NewReader(example)); err != nil {
+			t.Errorf("template example %q: %v", example, err)
+		}
+	}
+}
+
+func TestConfigFlagsOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	text := "no_ignore = true\nhidden = true\nsmart_case = true\nfollow = true\n"
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		flag string
+		set  func(*Options) bool // the value the config file turns on
+	}{
+		{"--ignore", func(o *Options) bool { return o.noIgnore }},
+		{"--no-hidden", func(o *Options) bool { return o.showHidden }},
+		{"--no-smart-case", func(o *Options) bool { return o.isSmartCase }},
+		{"--no-follow", func(o *Options) bool { return o.follow }},
+	}
+	for _, tt := range tests {
+		opts, err := ParseFlags([]string{"search", "--config", path, ".", "*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !tt.set(opts) {
+			t.Errorf("the config file doesn't set what %s turns off", tt.flag)
+		}
+		opts, err = ParseFlags([]string{"search", "--config", path, ".", "*", tt.flag})
+		if err != nil {
+			t.Errorf("%s: %v", tt.flag, err)
+			continue
+		}
+		if tt.set(opts) {
+			t.Errorf("%s doesn't turn the config value off", tt.flag)
+		}
+	}
+}
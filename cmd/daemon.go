@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sean1832/go-search/index"
+	"github.com/sean1832/go-search/search"
+)
+
+// How often the daemon refreshes an index it can't get change
+// notifications for, unless --refresh is given
+const defaultDaemonRefresh = 10 * time.Second
+
+// daemonQuery is the body of a query sent to the daemon: the arguments of
+// the search command, and the directory relative roots are resolved in
+type daemonQuery struct {
+	Dir  string   `json:"dir"`
+	Args []string `json:"args"`
+}
+
+// daemonMatch is a match as streamed by the daemon, one JSON object per
+// line, with the fields Match leaves out of its JSON
+type daemonMatch struct {
+	search.Match
+	Mode  fs.FileMode `json:"mode"`
+	Depth int         `json:"depth"`
+	Root  string      `json:"root"`
+}
+
+// daemonSocket returns the Unix socket the daemon listens on, in the user
+// cache dir next to the indexes
+func daemonSocket() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "go-search", "daemon.sock"), nil
+}
+
+// daemon keeps the indexes of its roots in memory, updating them as the
+// trees change
+type daemon struct {
+	program string
+
+	mu      sync.RWMutex
+	indexes []*index.Index
+
+	parseMu sync.Mutex // ParseFlags sets globals, so queries are parsed one at a time
+}
+
+// runDaemon implements the daemon subcommand
+func runDaemon(program string, args []string) int {
+	delay := defaultWatchDelay
+	refresh := defaultDaemonRefresh
+	var roots []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--delay":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+			if delay, err = time.ParseDuration(value); err != nil || delay <= 0 {
+				fmt.Fprintln(os.Stderr, "Error: invalid value for --delay:", value)
+				return exitUsage
+			}
+		case "--refresh":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+			if refresh, err = time.ParseDuration(value); err != nil || refresh <= 0 {
+				fmt.Fprintln(os.Stderr, "Error: invalid value for --refresh:", value)
+				return exitUsage
+			}
+		case "-h", "--help":
+			displayDaemonHelp(program)
+			return 0
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				fmt.Fprintln(os.Stderr, "Error: unknown argument:", args[i])
+				displayDaemonHelp(program)
+				return exitUsage
+			}
+			roots = append(roots, args[i])
+		}
+	}
+	if len(roots) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: daemon requires at least one directory")
+		displayDaemonHelp(program)
+		return exitUsage
+	}
+
+	d := &daemon{program: program}
+	for _, root := range roots {
+		start := time.Now()
+		idx, stats, err := loadOrBuildIndex(root)
+		if err != nil {
+			logger.Error("cannot index", "root", root, "err", err)
+			return exitFailure
+		}
+		d.indexes = append(d.indexes, idx)
+		fmt.Printf("Indexed %d entries under %s in %s\n", stats.Entries, idx.Root, time.Since(start).Round(time.Millisecond))
+	}
+
+	socket, err := daemonSocket()
+	if err != nil {
+		logger.Error("cannot find the daemon socket", "err", err)
+		return exitFailure
+	}
+	listener, err := listenDaemon(socket)
+	if err != nil {
+		logger.Error("cannot listen", "socket", socket, "err", err)
+		return exitFailure
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	for i := range d.indexes {
+		go d.keepUpdated(ctx, i, delay, refresh)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", d.handleQuery)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	fmt.Printf("Listening on %s\n", socket)
+	err = server.Serve(listener)
+	os.Remove(socket)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("daemon failed", "err", err)
+		return exitFailure
+	}
+	return 0
+}
+
+// loadOrBuildIndex starts from the saved index of root if there is one,
+// bringing it up to date, and builds one otherwise
+func loadOrBuildIndex(root string) (*index.Index, index.Stats, error) {
+	if path, err := index.PathFor(root); err == nil {
+		if idx, err := index.Load(path); err == nil {
+			return idx.Update()
+		}
+	}
+	return index.Build(root)
+}
+
+// listenDaemon listens on the socket, replacing it when left behind by a
+// daemon that is no longer running
+func listenDaemon(socket string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(socket), 0o755); err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("a daemon is already listening on %s", socket)
+	}
+	os.Remove(socket)
+	return net.Listen("unix", socket)
+}
+
+// keepUpdated keeps the index of a root up to date from filesystem
+// notifications, re-reading the directories they name. When notifications
+// can't be had, the index is refreshed at each interval instead.
+func (d *daemon) keepUpdated(ctx context.Context, i int, delay, interval time.Duration) {
+	if !index.HasNotifications() {
+		d.refreshEvery(ctx, i, interval)
+		return
+	}
+	d.mu.RLock()
+	idx := d.indexes[i]
+	d.mu.RUnlock()
+	err := idx.Watch(ctx, delay, func(next *index.Index, _ index.Stats) error {
+		d.mu.Lock()
+		d.indexes[i] = next
+		d.mu.Unlock()
+		return nil
+	})
+	if err == nil {
+		return // the daemon is stopping
+	}
+	logger.Warn("cannot watch the index, refreshing it at intervals", "root", idx.Root, "err", err)
+	d.refreshEvery(ctx, i, interval)
+}
+
+// refreshEvery updates the index of a root at each interval, only reading
+// the directories whose mtime changed
+func (d *daemon) refreshEvery(ctx context.Context, i int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		d.mu.RLock()
+		idx := d.indexes[i]
+		d.mu.RUnlock()
+		next, _, err := idx.Update()
+		if err != nil {
+			logger.Error("cannot refresh the index", "root", idx.Root, "err", err)
+			continue
+		}
+		d.mu.Lock()
+		d.indexes[i] = next
+		d.mu.Unlock()
+	}
+}
+
+// entriesUnder returns the entries below an absolute directory from the
+// index of the nearest indexed ancestor
+func (d *daemon) entriesUnder(dir string) ([]index.Entry, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var best *index.Index
+	for _, idx := range d.indexes {
+		rel, err := filepath.Rel(idx.Root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if best == nil || len(idx.Root) > len(best.Root) {
+			best = idx
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	entries, err := best.Under(dir)
+	return entries, err == nil
+}
+
+// handleQuery answers a search from the indexes in memory, streaming the
+// matches as JSON lines. Roots outside the indexed trees get a 404, upon
+// which the client searches on its own.
+func (d *daemon) handleQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "only POST is supported"})
+		return
+	}
+	var query daemonQuery
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid query: " + err.Error()})
+		return
+	}
+	// Files named by the flags are relative to the client, not the daemon
+	d.parseMu.Lock()
+	opts, err := parseFlagsIn(query.Dir, append([]string{d.program}, query.Args...))
+	d.parseMu.Unlock()
+	if errors.Is(err, errHelp) {
+		err = errors.New("--help is not supported by the daemon")
+	} else if err == nil {
+		if flag := daemonRefuses(opts); flag != "" {
+			err = fmt.Errorf("%s is not supported by the daemon", flag)
+		}
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	searcher, err := newSearcher(opts)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	// Every root must be covered before anything is sent
+	roots := search.UniqueRoots(opts.directories)
+	entries := make([][]index.Entry, len(roots))
+	absRoots := make([]string, len(roots))
+	for i, root := range roots {
+		absRoots[i] = filepath.Clean(root)
+		if !filepath.IsAbs(root) {
+			absRoots[i] = filepath.Join(query.Dir, root)
+		}
+		var ok bool
+		if entries[i], ok = d.entriesUnder(absRoots[i]); !ok {
+			writeJSON(w, http.StatusNotFound, errorResponse{Error: root + " is not indexed by the daemon"})
+			return
+		}
+	}
+
+	limit := opts.maxResults
+	if opts.sortKey != "" {
+		limit = 0
+	}
+	w.Header().Set("Content-Type", "application/jsonl")
+	out := bufio.NewWriter(w)
+	enc := json.NewEncoder(out)
+	found := 0
+	for i, root := range roots {
+		// Entries are checked by absolute path, since the filters reading
+		// files would otherwise look in the daemon's working directory
+		abs := absRoots[i]
+		for _, entry := range entries[i] {
+			rel := filepath.FromSlash(entry.Path)
+			match, ok := searcher.Check(abs, filepath.Join(abs, rel), entry.DirEntry())
+			if !ok {
+				continue
+			}
+			match.Path, match.Root = filepath.Join(root, rel), root
+			if err := enc.Encode(daemonMatch{Match: match, Mode: match.Mode, Depth: match.Depth, Root: match.Root}); err != nil {
+				return // the client went away
+			}
+			if found++; found == limit {
+				out.Flush()
+				return
+			}
+		}
+	}
+	out.Flush()
+}
+
+// usesDaemon reports whether a search can be sent to the daemon: with
+// --use-index, or when an index gives the same results as a walk would
+func usesDaemon(opts *Options) bool {
+	if daemonRefuses(opts) != "" {
+		return false
+	}
+	if opts.useIndex {
+		return true
+	}
+	return opts.noIgnore && !opts.follow && !opts.oneFileSystem && !opts.uniqueInodes && opts.maxDirEntries == 0 &&
+		len(opts.ownerFilters)+len(opts.ctimeFilters)+len(opts.atimeFilters) == 0
+}
+
+// daemonRefuses returns a flag of the search the daemon won't answer, or ""
+// when there is none. The daemon doesn't run custom or git filters, nor read
+// ignore files or paths from anywhere but its indexes.
+func daemonRefuses(opts *Options) string {
+	switch {
+	case opts.noDaemon:
+		return "--no-daemon"
+	case opts.filesFrom != "":
+		return "--files-from"
+	case opts.locateDB != "":
+		return "--db"
+	case opts.gitRev != "":
+		return "--git-rev"
+	case len(opts.filterCmds) > 0:
+		return "--filter-cmd"
+	case len(opts.filterPlugins) > 0:
+		return "--filter-plugin"
+	case len(opts.gitStates) > 0:
+		return "--git-tracked, --git-modified or --git-untracked"
+	case len(opts.ignoreFiles) > 0:
+		return "--ignore-file"
+	}
+	return ""
+}
+
+// streamFromDaemon sends the search to a running daemon. It reports false
+// when no daemon is listening or it doesn't index every root, in which
+// case the search should go ahead as usual.
+func streamFromDaemon(ctx context.Context, args []string) (<-chan search.Match, <-chan error, bool) {
+	socket, err := daemonSocket()
+	if err != nil {
+		return nil, nil, false
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, nil, false
+	}
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}}
+	body, err := json.Marshal(daemonQuery{Dir: dir, Args: args})
+	if err != nil {
+		return nil, nil, false
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://daemon/search", strings.NewReader(string(body)))
+	if err != nil {
+		return nil, nil, false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, false
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, nil, false
+	}
+
+	results := make(chan search.Match)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(results)
+		defer resp.Body.Close()
+		dec := json.NewDecoder(resp.Body)
+		for dec.More() {
+			var m daemonMatch
+			if err := dec.Decode(&m); err != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				errc <- err
+				return
+			}
+			match := m.Match
+			match.Mode, match.Depth, match.Root = m.Mode, m.Depth, m.Root
+			select {
+			case results <- match:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return results, errc, true
+}
+
+// displayDaemonHelp prints usage instructions for the daemon subcommand
+func displayDaemonHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s daemon [--delay <duration>] [--refresh <duration>] <directory>...\n", program)
+	fmt.Fprintln(os.Stderr, "Keeps an index of each directory in memory and answers searches over a Unix")
+	fmt.Fprintln(os.Stderr, "socket in the user cache directory. Searches use a running daemon by themselves")
+	fmt.Fprintln(os.Stderr, "when it indexes their directories and the results don't depend on what an index")
+	fmt.Fprintln(os.Stderr, "leaves out: with --use-index, or with --no-ignore and without --ignore-file,")
+	fmt.Fprintln(os.Stderr, "--follow, --one-file-system, --unique-inodes, --max-dir-entries and the owner,")
+	fmt.Fprintln(os.Stderr, "ctime and atime filters.")
+	fmt.Fprintln(os.Stderr, "The indexes are updated as filesystem notifications come in, once changes have")
+	fmt.Fprintf(os.Stderr, "settled for --delay (default: %s), re-reading only the directories named.\n", defaultWatchDelay)
+	fmt.Fprintf(os.Stderr, "Without notifications they are refreshed every --refresh interval (default: %s).\n", defaultDaemonRefresh)
+	fmt.Fprintln(os.Stderr, "--no-daemon searches without the daemon.")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sean1832/go-search/index"
+)
+
+// testDaemon indexes a small tree and returns a daemon serving it
+func testDaemon(t *testing.T) (*daemon, string) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root := t.TempDir()
+	writeFiles(t, root, "a.go", "b.txt", "sub/c.go", "sub/deep/d.go")
+	idx, _, err := index.Build(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &daemon{program: "search", indexes: []*index.Index{idx}}, root
+}
+
+// daemonMatches decodes the JSON lines of a daemon response
+func daemonMatches(t *testing.T, body string) []daemonMatch {
+	t.Helper()
+	var matches []daemonMatch
+	dec := json.NewDecoder(strings.NewReader(body))
+	for dec.More() {
+		var m daemonMatch
+		if err := dec.Decode(&m); err != nil {
+			t.Fatalf("%v in %q", err, body)
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+func TestDaemonQuery(t *testing.T) {
+	d, root := testDaemon(t)
+	query := func(dir string, args ...string) string {
+		body, _ := json.Marshal(daemonQuery{Dir: dir, Args: args})
+		return string(body)
+	}
+	elsewhere := t.TempDir()
+	tests := []struct {
+		name, method, body string
+		status             int
+		err                string // part of the error returned
+	}{
+		{"get", http.MethodGet, "", http.StatusMethodNotAllowed, "only POST"},
+		{"malformed body", http.MethodPost, `{"dir":`, http.StatusBadRequest, "invalid query"},
+		{"wrong types", http.MethodPost, `{"args":"*.go"}`, http.StatusBadRequest, "invalid query"},
+		{"help", http.MethodPost, query(root, "-h"), http.StatusBadRequest, "--help is not supported"},
+		{"long help", http.MethodPost, query(root, ".", "*", "--help"), http.StatusBadRequest, "--help is not supported"},
+		{"bad flag", http.MethodPost, query(root, ".", "--max-depth", "x"), http.StatusBadRequest, "--max-depth"},
+		{"filter command", http.MethodPost, query(root, ".", "*", "--filter-cmd", "true"), http.StatusBadRequest, "--filter-cmd is not supported"},
+		{"ignore file", http.MethodPost, query(root, ".", "*", "--ignore-file", "a.go"), http.StatusBadRequest, "--ignore-file is not supported"},
+		{"git state", http.MethodPost, query(root, ".", "*", "--git-modified"), http.StatusBadRequest, "is not supported"},
+		{"not indexed", http.MethodPost, query(elsewhere, ".", "*"), http.StatusNotFound, "is not indexed"},
+		{"partly indexed", http.MethodPost, query(root, ".", elsewhere, "*"), http.StatusNotFound, "is not indexed"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		d.handleQuery(rec, httptest.NewRequest(tt.method, "/search", strings.NewReader(tt.body)))
+		var resp errorResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != tt.status || !strings.Contains(resp.Error, tt.err) {
+			t.Errorf("%s: %d %q, want %d %q", tt.name, rec.Code, resp.Error, tt.status, tt.err)
+		}
+	}
+
+	// Matches keep the root as given, relative to the client's directory
+	rec := httptest.NewRecorder()
+	d.handleQuery(rec, httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(query(root, "sub", "*.go"))))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("search got %d: %s", rec.Code, rec.Body.String())
+	}
+	var paths []string
+	for _, m := range daemonMatches(t, rec.Body.String()) {
+		if m.Root != "sub" || m.Depth != strings.Count(filepath.ToSlash(m.Path), "/") {
+			t.Errorf("match %s has root %q and depth %d", m.Path, m.Root, m.Depth)
+		}
+		paths = append(paths, filepath.ToSlash(m.Path))
+	}
+	slices.Sort(paths)
+	if want := []string{"sub/c.go", "sub/deep/d.go"}; !slices.Equal(paths, want) {
+		t.Errorf("found %q, want %q", paths, want)
+	}
+
+	rec = httptest.NewRecorder()
+	d.handleQuery(rec, httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(query(root, root, "*", "--max-results", "2"))))
+	if n := len(daemonMatches(t, rec.Body.String())); n != 2 {
+		t.Errorf("--max-results 2 streamed %d matches", n)
+	}
+}
+
+func TestStreamFromDaemon(t *testing.T) {
+	d, root := testDaemon(t)
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	t.Setenv("LocalAppData", cache)
+	t.Chdir(root)
+
+	// Without a daemon listening the client searches on its own
+	if _, _, ok := streamFromDaemon(context.Background(), []string{".", "*.go"}); ok {
+		t.Fatal("streamed from a daemon that isn't running")
+	}
+
+	socket, err := daemonSocket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := listenDaemon(socket)
+	if err != nil {
+		t.Skip("cannot listen on a Unix socket:", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", d.handleQuery)
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	matches, errc, ok := streamFromDaemon(context.Background(), []string{".", "*.go"})
+	if !ok {
+		t.Fatal("daemon not used")
+	}
+	var paths []string
+	for m := range matches {
+		if m.Root != "." || m.Mode.IsDir() {
+			t.Errorf("match %s has root %q and mode %v", m.Path, m.Root, m.Mode)
+		}
+		paths = append(paths, filepath.ToSlash(m.Path))
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(paths)
+	if want := []string{"a.go", "sub/c.go", "sub/deep/d.go"}; !slices.Equal(paths, want) {
+		t.Errorf("found %q, want %q", paths, want)
+	}
+
+	// Searches the daemon refuses fall back to searching on their own
+	for _, args := range [][]string{{"/", "*"}, {".", "*", "--filter-cmd", "true"}, {"-h"}} {
+		if _, _, ok := streamFromDaemon(context.Background(), args); ok {
+			t.Errorf("%q answered by the daemon", args)
+		}
+	}
+	// and the daemon goes on answering
+	matches, errc, ok = streamFromDaemon(context.Background(), []string{".", "a.go"})
+	if !ok {
+		t.Fatal("daemon gone after the refused searches")
+	}
+	for range matches {
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
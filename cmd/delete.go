@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// deleteOptions selects what --delete removes and how
+type deleteOptions struct {
+	files     bool // delete matched files, symlinks and other non-directories
+	emptyDirs bool // delete matched directories that are empty
+	force     bool // don't ask before each deletion
+	dryRun    bool // only print what would be deleted
+}
+
+// DeleteMatches removes the matches selected by the options, asking on
+// stdin before each one unless forced. Files go first, then directories
+// deepest first, so that directories emptied by deleting what was in them
+// can go too; directories that aren't empty are left alone. It returns the
+// exit code.
+func DeleteMatches(matches <-chan search.Match, opts deleteOptions, w io.Writer) int {
+	var files, dirs []string
+	for match := range matches {
+		switch {
+		case match.Depth == 0 && match.Root != "":
+			continue // never the searched directory itself
+		case !match.IsDir():
+			if opts.files {
+				files = append(files, match.Path)
+			}
+		case opts.emptyDirs:
+			dirs = append(dirs, match.Path)
+		default:
+			logger.Warn("skipping", "path", match.Path, "reason", "a directory, see --delete-empty-dirs")
+		}
+	}
+	sort.Strings(files)
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], string(filepath.Separator)) > strings.Count(dirs[j], string(filepath.Separator))
+	})
+
+	confirm := newDeleteConfirm(os.Stdin, os.Stderr)
+	code := exitMatch
+	for _, path := range append(files, dirs...) {
+		if opts.dryRun {
+			fmt.Fprintln(w, "Would delete:", path)
+			continue
+		}
+		if isNonEmptyDir(path) {
+			logger.Warn("skipping", "path", path, "reason", "not empty")
+			continue
+		}
+		if !opts.force {
+			ok, quit := confirm.ask(path)
+			if quit {
+				break
+			}
+			if !ok {
+				continue
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			logger.Error("cannot delete", "err", err)
+			code = exitFailure
+			continue
+		}
+		fmt.Fprintln(w, "Deleted:", path)
+	}
+	return code
+}
+
+// isNonEmptyDir reports whether path is a directory with anything in it
+func isNonEmptyDir(path string) bool {
+	dir, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer dir.Close()
+	names, _ := dir.Readdirnames(1)
+	return len(names) > 0
+}
+
+// deleteConfirm asks before each deletion, remembering an answer of all
+type deleteConfirm struct {
+	r   *bufio.Reader
+	w   io.Writer
+	all bool
+}
+
+func newDeleteConfirm(r io.Reader, w io.Writer) *deleteConfirm {
+	return &deleteConfirm{r: bufio.NewReader(r), w: w}
+}
+
+// ask asks whether to delete path, defaulting to no. Answering a deletes
+// the rest without asking again, and q (or the end of input) stops.
+func (c *deleteConfirm) ask(path string) (ok, quit bool) {
+	if c.all {
+		return true, false
+	}
+	fmt.Fprintf(c.w, "Delete %s? [y/N/a/q] ", path)
+	answer, err := c.r.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(c.w)
+		return false, true
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, false
+	case "a", "all":
+		c.all = true
+		return true, false
+	case "q", "quit":
+		return false, true
+	}
+	return false, false
+}
+
+// matchesEverything reports whether a pattern selects every entry, which
+// --delete refuses to do at a filesystem root
+func matchesEverything(pattern string, isRegex, isFixed bool) bool {
+	switch {
+	case isFixed:
+		return pattern == ""
+	case isRegex:
+		switch pattern {
+		case "", ".", ".*", ".+", "^", "^.*", "^.*$", "^.+$":
+			return true
+		}
+		return false
+	}
+	switch pattern {
+	case "*", "**", "**/*", "*/**":
+		return true
+	}
+	return false
+}
+
+// isFilesystemRoot reports whether dir is the root of a filesystem, such
+// as / or C:\
+func isFilesystemRoot(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return filepath.Dir(abs) == abs
+}
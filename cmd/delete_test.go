@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sean1832/go-search/search"
+)
+
+func TestMatchesEverything(t *testing.T) {
+	tests := []struct {
+		pattern          string
+		isRegex, isFixed bool
+		want             bool
+	}{
+		{"*", false, false, true},
+		{"**/*", false, false, true},
+		{"*.log", false, false, false},
+		{".*", true, false, true},
+		{"^.+$", true, false, true},
+		{`\.log$`, true, false, false},
+		{"", false, true, true},
+		{"*", false, true, false}, // a literal asterisk
+	}
+	for _, tt := range tests {
+		if got := matchesEverything(tt.pattern, tt.isRegex, tt.isFixed); got != tt.want {
+			t.Errorf("matchesEverything(%q, regex %v, fixed %v) = %v", tt.pattern, tt.isRegex, tt.isFixed, got)
+		}
+	}
+}
+
+func TestDeleteFlags(t *testing.T) {
+	root, _ := filepath.Abs(string(filepath.Separator))
+	dir := t.TempDir()
+	tests := []struct {
+		args    []string
+		refused string // part of the error, empty when accepted
+	}{
+		{[]string{root, "*", "--delete", "--force"}, "refusing to delete everything"},
+		{[]string{root, ".*", "--regex", "--delete-empty-dirs", "--force"}, "refusing to delete everything"},
+		{[]string{root, "*.log", "--invert", "--delete", "--force"}, "refusing to delete everything"},
+		{[]string{dir, root, "*", "--delete", "--dry-run"}, "refusing to delete everything"},
+		{[]string{root, "*.log", "--delete", "--force"}, ""},
+		{[]string{dir, "*", "--delete", "--force"}, ""},
+		{[]string{dir, "*.log", "--force"}, "--force requires --delete"},
+		{[]string{dir, "*.log", "--delete", "--count"}, "--delete only combines"},
+		{[]string{dir, "*.log", "--delete", "--exec", "echo"}, "--delete only combines"},
+	}
+	for _, tt := range tests {
+		err := checkFlags(append([]string{"search"}, tt.args...))
+		if tt.refused == "" && err != nil {
+			t.Errorf("%q refused: %v", tt.args, err)
+		} else if tt.refused != "" && (err == nil || !strings.Contains(err.Error(), tt.refused)) {
+			t.Errorf("%q = %v, want %q", tt.args, err, tt.refused)
+		}
+	}
+
+	// Without a terminal to confirm on, deletions must be forced
+	if !isTerminal(os.Stdin) {
+		if _, err := ParseFlags([]string{"search", dir, "*.log", "--delete"}); err == nil {
+			t.Error("--delete accepted without a terminal or --force")
+		}
+	}
+}
+
+func TestDeleteMatches(t *testing.T) {
+	quietLogger(t)
+	root := t.TempDir()
+	writeFiles(t, root, "a.log", "keep.txt", "d/b.log", "full/x")
+	if err := os.Mkdir(filepath.Join(root, "d", "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	in := func(name string) string { return filepath.Join(root, filepath.FromSlash(name)) }
+	matches := func() <-chan search.Match {
+		list := []search.Match{
+			{Path: root, Type: search.TypeDir, Root: root}, // the searched directory
+			{Path: in("a.log"), Type: search.TypeFile, Depth: 1, Root: root},
+			{Path: in("d"), Type: search.TypeDir, Depth: 1, Root: root},
+			{Path: in("d/b.log"), Type: search.TypeFile, Depth: 2, Root: root},
+			{Path: in("d/empty"), Type: search.TypeDir, Depth: 2, Root: root},
+			{Path: in("full"), Type: search.TypeDir, Depth: 1, Root: root},
+		}
+		c := make(chan search.Match, len(list))
+		for _, m := range list {
+			c <- m
+		}
+		close(c)
+		return c
+	}
+	exists := func(name string) bool {
+		_, err := os.Lstat(in(name))
+		return err == nil
+	}
+
+	var out bytes.Buffer
+	if code := DeleteMatches(matches(), deleteOptions{files: true, emptyDirs: true, dryRun: true}, &out); code != exitMatch {
+		t.Fatalf("dry run exited with %d", code)
+	}
+	if n := strings.Count(out.String(), "Would delete:"); n != 5 || !exists("a.log") || !exists("d/empty") {
+		t.Errorf("dry run printed %d deletions: %q", n, out.String())
+	}
+
+	// Directories are left to --delete-empty-dirs
+	out.Reset()
+	if code := DeleteMatches(matches(), deleteOptions{files: true, force: true}, &out); code != exitMatch {
+		t.Fatalf("delete exited with %d", code)
+	}
+	if exists("a.log") || exists("d/b.log") || !exists("d/empty") || !exists("keep.txt") {
+		t.Errorf("after deleting the files: %q", out.String())
+	}
+
+	// Emptied directories go too, deepest first, but full ones stay
+	out.Reset()
+	if code := DeleteMatches(matches(), deleteOptions{emptyDirs: true, force: true}, &out); code != exitMatch {
+		t.Fatalf("delete exited with %d", code)
+	}
+	if exists("d") || !exists("full/x") || !exists(".") {
+		t.Errorf("after deleting the directories: %q", out.String())
+	}
+}
+
+func TestDeleteConfirm(t *testing.T) {
+	var prompts bytes.Buffer
+	c := newDeleteConfirm(strings.NewReader("y\nNO\n\nYes\nq\n"), &prompts)
+	want := []struct{ ok, quit bool }{{true, false}, {false, false}, {false, false}, {true, false}, {false, true}}
+	for i, w := range want {
+		if ok, quit := c.ask("p"); ok != w.ok || quit != w.quit {
+			t.Errorf("answer %d = %v, %v, want %v, %v", i, ok, quit, w.ok, w.quit)
+		}
+	}
+	if n := strings.Count(prompts.String(), "Delete p?"); n != len(want) {
+		t.Errorf("asked %d times, want %d", n, len(want))
+	}
+
+	// All answers the rest without asking, the end of input quits
+	c = newDeleteConfirm(strings.NewReader("a\n"), &prompts)
+	for range 3 {
+		if ok, quit := c.ask("p"); !ok || quit {
+			t.Errorf("after all = %v, %v", ok, quit)
+		}
+	}
+	c = newDeleteConfirm(strings.NewReader("y"), &prompts)
+	if ok, _ := c.ask("p"); !ok {
+		t.Error("an answer without a newline is ignored")
+	}
+	if ok, quit := c.ask("p"); ok || !quit {
+		t.Errorf("at the end of input = %v, %v", ok, quit)
+	}
+}
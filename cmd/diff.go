@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Prefixes of the differences printed by the diff subcommand
+var diffMarks = map[string]string{
+	search.DiffAdded:   "+",
+	search.DiffRemoved: "-",
+	search.DiffChanged: "~",
+}
+
+// runDiff implements the diff subcommand, listing the paths added, removed
+// or changed from one directory to another
+func runDiff(program string, args []string) int {
+	var roots []string
+	var newHash func() hash.Hash
+	workers := runtime.NumCPU()
+	showHidden := false
+	noIgnore := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--hash":
+			value, err := flagValue(args, &i)
+			if err == nil {
+				newHash, err = search.HashFunc(value)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+		case "-j", "--jobs":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+			if workers, err = strconv.Atoi(value); err != nil || workers < 1 {
+				fmt.Fprintln(os.Stderr, "Error: invalid value for --jobs:", value)
+				return exitUsage
+			}
+		case "-H", "--hidden":
+			showHidden = true
+		case "--no-ignore":
+			noIgnore = true
+		case "--verbose":
+			logger = slog.New(newLogHandler(os.Stderr, "text", slog.LevelInfo))
+		case "-h", "--help":
+			displayDiffHelp(program)
+			return exitMatch
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				fmt.Fprintln(os.Stderr, "Error: unknown argument:", args[i])
+				displayDiffHelp(program)
+				return exitUsage
+			}
+			roots = append(roots, args[i])
+		}
+	}
+	if len(roots) != 2 {
+		fmt.Fprintln(os.Stderr, "Error: diff requires two directories")
+		displayDiffHelp(program)
+		return exitUsage
+	}
+	for _, root := range roots {
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			fmt.Fprintln(os.Stderr, "Error: not a directory:", root)
+			return exitUsage
+		}
+	}
+
+	// Both trees are walked at once, by a searcher each
+	var trees [2][]search.Match
+	errs := make(chan error, 2)
+	for i, root := range roots {
+		searcher, err := search.New("*",
+			search.WithHidden(showHidden),
+			search.WithIgnoreFiles(!noIgnore),
+			search.WithJobs(workers),
+			search.WithInfo(true),
+			search.WithErrorHandler(reportSkipped),
+		)
+		if err != nil {
+			logger.Error("cannot search", "err", err)
+			return exitFailure
+		}
+		go func() {
+			var err error
+			trees[i], err = searcher.Search(context.Background(), root)
+			errs <- err
+		}()
+	}
+	for range roots {
+		if err := <-errs; err != nil {
+			logger.Error("search failed", "err", err)
+			return exitFailure
+		}
+	}
+
+	diffs := search.DiffTrees(roots[0], trees[0], roots[1], trees[1], newHash, workers, reportSkipped)
+	for _, d := range diffs {
+		path := filepath.FromSlash(d.Path)
+		if d.Kind != search.DiffChanged && (d.Old.IsDir() || d.New.IsDir()) {
+			path += string(filepath.Separator)
+		}
+		fmt.Println(diffMarks[d.Kind], path)
+	}
+
+	switch {
+	case skipped.Load():
+		return exitFailure
+	case len(diffs) > 0:
+		return exitNoMatch
+	}
+	return exitMatch
+}
+
+// displayDiffHelp prints usage instructions for the diff subcommand
+func displayDiffHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s diff [--hash <algorithm>] <old directory> <new directory>\n", program)
+	fmt.Fprintln(os.Stderr, "Lists the paths added (+), removed (-) or changed (~) from the old directory to the")
+	fmt.Fprintln(os.Stderr, "new one. Paths are changed when their type or size differs, or with --hash their")
+	fmt.Fprintln(os.Stderr, "content; a directory on one side only is listed without its contents.")
+	fmt.Fprintln(os.Stderr, "      --hash <algorithm> Also compare files of the same size by sha256, md5 or xxh64 checksum")
+	fmt.Fprintln(os.Stderr, "  -j, --jobs <N>         Number of parallel workers (default: number of CPUs)")
+	fmt.Fprintln(os.Stderr, "  -H, --hidden           Include hidden files and directories")
+	fmt.Fprintln(os.Stderr, "      --no-ignore        Don't respect .gitignore, .ignore, .searchignore and global git excludes")
+	fmt.Fprintln(os.Stderr, "      --verbose          Report paths skipped because they couldn't be read on stderr")
+	fmt.Fprintln(os.Stderr, "Exit status: 0 if the directories are the same, 1 if they differ, 3 if some paths")
+	fmt.Fprintln(os.Stderr, "couldn't be read.")
+}
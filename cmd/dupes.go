@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// runDupes implements the dupes subcommand, listing groups of files with
+// identical contents under the given directories
+func runDupes(program string, args []string) int {
+	var roots []string
+	interactive := false
+	showHidden := false
+	noIgnore := false
+	for _, arg := range args {
+		switch arg {
+		case "--delete-interactive":
+			interactive = true
+		case "-H", "--hidden":
+			showHidden = true
+		case "--no-ignore":
+			noIgnore = true
+		case "--verbose":
+			logger = slog.New(newLogHandler(os.Stderr, "text", slog.LevelInfo))
+		case "-h", "--help":
+			displayDupesHelp(program)
+			return exitMatch
+		default:
+			if strings.HasPrefix(arg, "-") {
+				fmt.Fprintln(os.Stderr, "Error: unknown argument:", arg)
+				displayDupesHelp(program)
+				return exitUsage
+			}
+			roots = append(roots, arg)
+		}
+	}
+	if len(roots) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: dupes requires at least one directory")
+		displayDupesHelp(program)
+		return exitUsage
+	}
+
+	searcher, err := search.New("*",
+		search.WithTypes(search.TypeFileKind),
+		search.WithInfo(true),
+		search.WithHidden(showHidden),
+		search.WithIgnoreFiles(!noIgnore),
+		search.WithErrorHandler(reportSkipped),
+	)
+	if err != nil {
+		logger.Error("cannot search", "err", err)
+		return exitFailure
+	}
+	files, err := searcher.Search(context.Background(), roots...)
+	if err != nil {
+		logger.Error("search failed", "err", err)
+		return exitFailure
+	}
+
+	groups := search.FindDuplicates(files, runtime.NumCPU(), reportSkipped)
+	if len(groups) == 0 {
+		fmt.Println("No duplicate files found")
+	}
+	stdin := bufio.NewReader(os.Stdin)
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%d identical files of %d bytes:\n", len(group), group[0].Size)
+		for j, file := range group {
+			if interactive {
+				fmt.Printf("  [%d] %s\n", j+1, file.Path)
+			} else {
+				fmt.Printf("  %s\n", file.Path)
+			}
+		}
+		if interactive {
+			deleteDuplicates(stdin, group)
+		}
+	}
+
+	switch {
+	case skipped.Load():
+		return exitFailure
+	case len(groups) == 0:
+		return exitNoMatch
+	}
+	return exitMatch
+}
+
+// deleteDuplicates asks which file of a group to keep and deletes the rest.
+// An empty answer keeps every file.
+func deleteDuplicates(stdin *bufio.Reader, group []search.Match) {
+	for {
+		fmt.Printf("Keep which file? [1-%d, Enter to keep all]: ", len(group))
+		answer, err := stdin.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			if err != nil {
+				fmt.Println()
+			}
+			return
+		}
+		keep, convErr := strconv.Atoi(answer)
+		if convErr != nil || keep < 1 || keep > len(group) {
+			fmt.Println("Invalid choice:", answer)
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		for j, file := range group {
+			if j == keep-1 {
+				continue
+			}
+			if err := os.Remove(file.Path); err != nil {
+				logger.Error("cannot delete", "path", file.Path, "err", err)
+				skipped.Store(true)
+				continue
+			}
+			fmt.Println("Deleted:", file.Path)
+		}
+		return
+	}
+}
+
+// displayDupesHelp prints usage instructions for the dupes subcommand
+func displayDupesHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s dupes [--delete-interactive] <directory>...\n", program)
+	fmt.Fprintln(os.Stderr, "Lists groups of files with identical contents, found by size and SHA-256.")
+	fmt.Fprintln(os.Stderr, "      --delete-interactive")
+	fmt.Fprintln(os.Stderr, "                         Ask which file of each group to keep and delete the rest")
+	fmt.Fprintln(os.Stderr, "  -H, --hidden           Include hidden files and directories")
+	fmt.Fprintln(os.Stderr, "      --no-ignore        Don't respect .gitignore, .ignore, .searchignore and global git excludes")
+	fmt.Fprintln(os.Stderr, "      --verbose          Report paths skipped because they couldn't be read on stderr")
+}
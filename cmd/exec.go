@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Placeholder replaced by the matched path in --exec arguments
+const execPlaceholder = "{}"
+
+// ExecEach runs the command once per match using a fixed pool of workers.
+// Output of each command is buffered so parallel runs don't interleave.
+// It returns the first nonzero exit code, or 0 if every command succeeded.
+func ExecEach(command []string, matches <-chan search.Match, workers int) int {
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	exitCode := 0
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for match := range matches {
+				var stdout, stderr bytes.Buffer
+				code := runCommand(substituteArgs(command, []string{match.Path}), nil, &stdout, &stderr)
+
+				mu.Lock()
+				os.Stdout.Write(stdout.Bytes())
+				os.Stderr.Write(stderr.Bytes())
+				if code != 0 && exitCode == 0 {
+					exitCode = code
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return exitCode
+}
+
+// ExecBatch runs the command a single time with every match as arguments
+func ExecBatch(command []string, matches <-chan search.Match) int {
+	var paths []string
+	for match := range matches {
+		paths = append(paths, match.Path)
+	}
+	if len(paths) == 0 {
+		return 0
+	}
+	return runCommand(substituteArgs(command, paths), os.Stdin, os.Stdout, os.Stderr)
+}
+
+// substituteArgs replaces the {} placeholder with the given paths. When no
+// argument contains the placeholder, the paths are appended at the end.
+func substituteArgs(command []string, paths []string) []string {
+	var args []string
+	substituted := false
+	for _, arg := range command {
+		switch {
+		case arg == execPlaceholder:
+			args = append(args, paths...)
+			substituted = true
+		case strings.Contains(arg, execPlaceholder):
+			for _, path := range paths {
+				args = append(args, strings.ReplaceAll(arg, execPlaceholder, path))
+			}
+			substituted = true
+		default:
+			args = append(args, arg)
+		}
+	}
+	if !substituted {
+		args = append(args, paths...)
+	}
+	return args
+}
+
+// runCommand runs a command to completion and returns its exit code
+func runCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	// The command could not be started, or was killed by a signal
+	fmt.Fprintf(stderr, "Error running %s: %v\n", args[0], err)
+	return 1
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"plugin"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// loadFilters lists the files in the --git states and starts the
+// --filter-cmd commands and opens the --filter-plugin plugins, returning
+// their filters and a function stopping the commands
+func loadFilters(opts *Options) ([]search.Filter, func(), error) {
+	var filters []search.Filter
+	if len(opts.gitStates) > 0 {
+		// A list of paths is taken to come from the current repository
+		roots := opts.directories
+		if len(roots) == 0 {
+			roots = []string{"."}
+		}
+		filter, err := newGitFilter(roots, opts.gitStates)
+		if err != nil {
+			return nil, nil, err
+		}
+		filters = append(filters, filter)
+	}
+	var commands []*search.CommandFilter
+	stop := func() {
+		for _, command := range commands {
+			command.Close()
+		}
+	}
+	for _, line := range opts.filterCmds {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			stop()
+			return nil, nil, fmt.Errorf("--filter-cmd requires a command")
+		}
+		command, err := search.StartCommandFilter(fields[0], fields[1:]...)
+		if err != nil {
+			stop()
+			return nil, nil, err
+		}
+		commands = append(commands, command)
+		filters = append(filters, command)
+	}
+	for _, path := range opts.filterPlugins {
+		filter, err := loadFilterPlugin(path)
+		if err != nil {
+			stop()
+			return nil, nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, stop, nil
+}
+
+// loadFilterPlugin opens a Go plugin, built with -buildmode=plugin against
+// the same version of the search package, that exports a Filter variable
+// implementing search.Filter
+func loadFilterPlugin(path string) (search.Filter, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := p.Lookup("Filter")
+	if err != nil {
+		return nil, err
+	}
+	switch filter := symbol.(type) {
+	case *search.Filter:
+		if *filter != nil {
+			return *filter, nil
+		}
+	case search.Filter:
+		return filter, nil
+	}
+	return nil, fmt.Errorf("plugin %s: Filter does not implement search.Filter", path)
+}
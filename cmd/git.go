@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Git states a search can be restricted to, given with --git-tracked,
+// --git-modified and --git-untracked
+const (
+	gitTracked   = "tracked"
+	gitModified  = "modified"
+	gitUntracked = "untracked"
+)
+
+// git runs git in dir, returning its output. Its error message, such as
+// "not a git repository", becomes the error.
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				err = errors.New(strings.TrimPrefix(msg, "fatal: "))
+			}
+		}
+		return nil, fmt.Errorf("git in %s: %w", dir, err)
+	}
+	return out, nil
+}
+
+// gitFiles returns the absolute paths of the files below root in any of
+// the git states, as listed by git ls-files
+func gitFiles(root string, states []string) (map[string]bool, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	var lists [][]string
+	for _, state := range states {
+		switch state {
+		case gitTracked:
+			lists = append(lists, []string{"ls-files", "-z"})
+		case gitModified:
+			// Changed in the working tree, or staged
+			lists = append(lists, []string{"ls-files", "-z", "--modified"})
+			lists = append(lists, []string{"diff", "--cached", "--name-only", "--relative", "-z"})
+		case gitUntracked:
+			lists = append(lists, []string{"ls-files", "-z", "--others", "--exclude-standard"})
+		}
+	}
+	files := make(map[string]bool)
+	for _, args := range lists {
+		out, err := git(abs, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range strings.Split(string(out), "\x00") {
+			if name != "" {
+				files[filepath.Join(abs, filepath.FromSlash(name))] = true
+			}
+		}
+	}
+	return files, nil
+}
+
+// newGitFilter returns a filter keeping the files below the roots that are
+// in any of the git states
+func newGitFilter(roots []string, states []string) (search.Filter, error) {
+	files := make(map[string]bool)
+	for _, root := range roots {
+		found, err := gitFiles(root, states)
+		if err != nil {
+			return nil, err
+		}
+		for path := range found {
+			files[path] = true
+		}
+	}
+	return search.FilterFunc(func(path string, d fs.DirEntry) (bool, error) {
+		if d.IsDir() {
+			return false, nil
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return false, err
+		}
+		return files[abs], nil
+	}), nil
+}
+
+// gitRevEntry is an entry of a git tree, dated with the time of the commit
+type gitRevEntry struct {
+	name    string
+	mode    fs.FileMode
+	size    int64
+	modTime time.Time
+}
+
+func (e *gitRevEntry) Name() string               { return e.name }
+func (e *gitRevEntry) IsDir() bool                { return e.mode.IsDir() }
+func (e *gitRevEntry) Type() fs.FileMode          { return e.mode.Type() }
+func (e *gitRevEntry) Info() (fs.FileInfo, error) { return e, nil }
+func (e *gitRevEntry) Size() int64                { return e.size }
+func (e *gitRevEntry) Mode() fs.FileMode          { return e.mode }
+func (e *gitRevEntry) ModTime() time.Time         { return e.modTime }
+func (e *gitRevEntry) Sys() any                   { return nil }
+
+// gitTreeMode maps the mode of a git tree entry to a file mode
+func gitTreeMode(mode uint64) fs.FileMode {
+	switch mode &^ 0o777 {
+	case 0o040000, 0o160000: // trees and submodules
+		return fs.ModeDir | 0o755
+	case 0o120000:
+		return fs.ModeSymlink | 0o777
+	}
+	return fs.FileMode(mode & 0o777)
+}
+
+// gitRevEntries lists the tree of rev below root, as git ls-tree does:
+// "<mode> <type> <object> <size>\t<path>" records, NUL terminated, with
+// paths relative to root
+func gitRevEntries(root, rev string) ([]string, []*gitRevEntry, error) {
+	out, err := git(root, "ls-tree", "-r", "-t", "-z", "--long", rev, "--")
+	if err != nil {
+		return nil, nil, err
+	}
+	// Commits date their files; bare trees leave them undated
+	var modTime time.Time
+	if stamp, err := git(root, "show", "-s", "--format=%ct", rev, "--"); err == nil {
+		if seconds, err := strconv.ParseInt(strings.TrimSpace(string(stamp)), 10, 64); err == nil {
+			modTime = time.Unix(seconds, 0)
+		}
+	}
+	var paths []string
+	var entries []*gitRevEntry
+	for _, record := range strings.Split(string(out), "\x00") {
+		meta, path, ok := strings.Cut(record, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 4 {
+			continue
+		}
+		mode, err := strconv.ParseUint(fields[0], 8, 32)
+		if err != nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[3], 10, 64) // - for trees
+		paths = append(paths, filepath.FromSlash(path))
+		entries = append(entries, &gitRevEntry{name: filepath.Base(path), mode: gitTreeMode(mode), size: size, modTime: modTime})
+	}
+	return paths, entries, nil
+}
+
+// streamGitRev answers a search from the tree of a git revision instead of
+// walking the roots, which need to be in the repository. It stops after
+// limit matches when limit is positive.
+func streamGitRev(ctx context.Context, run *search.Run, rev string, roots []string, limit int) (<-chan search.Match, <-chan error) {
+	results := make(chan search.Match)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(results)
+		found := 0
+		for _, root := range search.UniqueRoots(roots) {
+			paths, entries, err := gitRevEntries(root, rev)
+			if err != nil {
+				errc <- err
+				return
+			}
+			for i, entry := range entries {
+				match, ok := run.Check(root, filepath.Join(root, paths[i]), entry)
+				if !ok {
+					continue
+				}
+				select {
+				case results <- match:
+				case <-ctx.Done():
+					errc <- ctx.Err()
+					return
+				}
+				if found++; found == limit {
+					return
+				}
+			}
+		}
+	}()
+
+	return results, errc
+}
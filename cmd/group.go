@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// matchGroup is a directory and the matches directly inside it
+type matchGroup struct {
+	dir     string
+	matches []search.Match
+}
+
+// groupFormatter prints each directory containing matches once, followed
+// by the names of its matches indented below it. The groups are held until
+// Close.
+type groupFormatter struct {
+	w         io.Writer
+	colors    *colorizer
+	keepOrder bool // keep the order matches arrived in instead of sorting by name
+	groups    []*matchGroup
+	index     map[string]*matchGroup // groups by directory
+}
+
+func newGroupFormatter(w io.Writer, config formatConfig) *groupFormatter {
+	return &groupFormatter{
+		w:         w,
+		colors:    config.colors,
+		keepOrder: config.keepOrder,
+		index:     make(map[string]*matchGroup),
+	}
+}
+
+func (f *groupFormatter) Write(match search.Match) error {
+	dir := filepath.Dir(match.Path)
+	group, ok := f.index[dir]
+	if !ok {
+		group = &matchGroup{dir: dir}
+		f.index[dir] = group
+		f.groups = append(f.groups, group)
+	}
+	group.matches = append(group.matches, match)
+	return nil
+}
+
+func (f *groupFormatter) Close() error {
+	if len(f.groups) == 0 {
+		_, err := fmt.Fprintln(f.w, "No path matches the pattern")
+		return err
+	}
+	if !f.keepOrder {
+		sort.Slice(f.groups, func(i, j int) bool { return f.groups[i].dir < f.groups[j].dir })
+	}
+	var buf strings.Builder
+	for _, group := range f.groups {
+		matches := group.matches
+		if !f.keepOrder {
+			sort.SliceStable(matches, func(i, j int) bool { return matches[i].Path < matches[j].Path })
+		}
+		if f.colors != nil {
+			buf.WriteString(f.colors.Dir(group.dir))
+		} else {
+			buf.WriteString(group.dir)
+		}
+		buf.WriteString("\n")
+		for _, match := range matches {
+			name := filepath.Base(match.Path)
+			if f.colors != nil {
+				name = f.colors.Name(match, name)
+			}
+			buf.WriteString("  " + name + "\n")
+		}
+	}
+	_, err := io.WriteString(f.w, buf.String())
+	return err
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Methods served by the gRPC server, see proto/search.proto
+const (
+	grpcSearchMethod     = "/gosearch.v1.SearchService/Search"
+	grpcSearchPageMethod = "/gosearch.v1.SearchService/SearchPage"
+)
+
+// Largest request message accepted
+const grpcMaxRequestSize = 1 << 20
+
+// gRPC status codes used by the server
+const (
+	grpcOK              = 0
+	grpcInvalidArgument = 3
+	grpcNotFound        = 5
+	grpcInternal        = 13
+	grpcUnimplemented   = 12
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// grpcSearchRequest is the decoded SearchRequest message
+type grpcSearchRequest struct {
+	roots         []string
+	pattern       string
+	types         string
+	regex         bool
+	caseSensitive bool
+	smartCase     bool
+	follow        bool
+	hidden        bool
+	noIgnore      bool
+	maxDepth      *int32
+	minDepth      int32
+	excludes      []string
+	prunes        []string
+	pageSize      int
+	pageToken     string
+}
+
+// handleGRPC serves SearchService.Search, streaming matches as they are
+// found, and SearchService.SearchPage, reading them a page at a time from
+// the paged queries. Protobuf messages are encoded by hand as the service
+// is small.
+func handleGRPC(w http.ResponseWriter, r *http.Request, queries *pagedQueries) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	if r.URL.Path != grpcSearchMethod && r.URL.Path != grpcSearchPageMethod {
+		writeGRPCStatus(w, grpcUnimplemented, "unknown method "+r.URL.Path)
+		return
+	}
+
+	message, err := readGRPCMessage(r.Body)
+	if err != nil {
+		writeGRPCStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+	req, err := decodeSearchRequest(message)
+	if err != nil {
+		writeGRPCStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+	if r.URL.Path == grpcSearchPageMethod && req.pageToken != "" {
+		id, offset, ok := parsePageToken(req.pageToken)
+		q, found := queries.get(id)
+		if !ok || !found {
+			writeGRPCStatus(w, grpcNotFound, "unknown or expired page token: "+req.pageToken)
+			return
+		}
+		writeGRPCPage(w, r, q, offset, req.pageSize)
+		return
+	}
+	if len(req.roots) == 0 || req.pattern == "" {
+		writeGRPCStatus(w, grpcInvalidArgument, "roots and pattern are required")
+		return
+	}
+
+	options, err := queryOptions(req.query())
+	if err != nil {
+		writeGRPCStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+	searcher, err := search.New(req.pattern, options...)
+	if err != nil {
+		writeGRPCStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+
+	if r.URL.Path == grpcSearchPageMethod {
+		q := startPagedQuery(searcher, req.roots, req.pattern)
+		queries.add(q)
+		writeGRPCPage(w, r, q, 0, req.pageSize)
+		return
+	}
+
+	// Headers go out now; the status follows the matches as trailers
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	matches, errc := searcher.Stream(r.Context(), req.roots...)
+	for match := range matches {
+		if _, err := w.Write(grpcFrame(encodeMatch(match))); err != nil {
+			break // the client went away, which cancels the search
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if err := <-errc; err != nil {
+		writeGRPCStatus(w, grpcInternal, err.Error())
+		return
+	}
+	writeGRPCStatus(w, grpcOK, "")
+}
+
+// writeGRPCPage answers SearchPage with the page of a paged query starting
+// at offset, once its matches are found
+func writeGRPCPage(w http.ResponseWriter, r *http.Request, q *pagedQuery, offset, size int) {
+	if size == 0 {
+		size = defaultPageLimit
+	}
+	matches, more, err := q.page(r.Context(), offset, size)
+	if err != nil {
+		writeGRPCStatus(w, grpcInternal, err.Error())
+		return
+	}
+	var b []byte
+	for _, match := range matches {
+		b = appendProtoBytes(b, 1, encodeMatch(match))
+	}
+	if more {
+		b = appendProtoString(b, 2, q.id+":"+strconv.Itoa(offset+len(matches)))
+	}
+	if _, done, truncated := q.total(); done && truncated {
+		b = appendProtoVarint(b, 3, 1)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(grpcFrame(b))
+	writeGRPCStatus(w, grpcOK, "")
+}
+
+// parsePageToken splits a page token into the handle of its query and the
+// offset of the page
+func parsePageToken(token string) (string, int, bool) {
+	id, value, ok := strings.Cut(token, ":")
+	offset, err := strconv.Atoi(value)
+	return id, offset, ok && err == nil && offset >= 0
+}
+
+// writeGRPCStatus ends the response with a status, sent as trailers after
+// any messages
+func writeGRPCStatus(w http.ResponseWriter, code int, message string) {
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(code))
+	if message != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", grpcEscape(message))
+	}
+}
+
+// grpcEscape percent-encodes a status message as gRPC requires
+func grpcEscape(message string) string {
+	var b strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// readGRPCMessage reads the single length-prefixed message of a request
+func readGRPCMessage(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, errors.New("missing request message")
+	}
+	if header[0] != 0 {
+		return nil, errors.New("compressed messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > grpcMaxRequestSize {
+		return nil, errors.New("request message too large")
+	}
+	message := make([]byte, size)
+	if _, err := io.ReadFull(r, message); err != nil {
+		return nil, errors.New("truncated request message")
+	}
+	return message, nil
+}
+
+// grpcFrame prefixes a message with its uncompressed length
+func grpcFrame(message []byte) []byte {
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+// query translates the request into the HTTP server's query parameters,
+// so that both servers accept the same options
+func (req *grpcSearchRequest) query() map[string][]string {
+	query := map[string][]string{
+		"type":          {req.types},
+		"regex":         {strconv.FormatBool(req.regex)},
+		"casesensitive": {strconv.FormatBool(req.caseSensitive)},
+		"smartcase":     {strconv.FormatBool(req.smartCase)},
+		"follow":        {strconv.FormatBool(req.follow)},
+		"hidden":        {strconv.FormatBool(req.hidden)},
+		"noignore":      {strconv.FormatBool(req.noIgnore)},
+		"mindepth":      {strconv.Itoa(int(req.minDepth))},
+		"exclude":       req.excludes,
+		"prune":         req.prunes,
+	}
+	if req.maxDepth != nil {
+		query["maxdepth"] = []string{strconv.Itoa(int(*req.maxDepth))}
+	}
+	return query
+}
+
+// decodeSearchRequest parses a SearchRequest message
+func decodeSearchRequest(b []byte) (*grpcSearchRequest, error) {
+	req := &grpcSearchRequest{}
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("malformed request message")
+		}
+		b = b[n:]
+		field, wire := key>>3, key&7
+
+		var value uint64
+		var data []byte
+		switch wire {
+		case wireVarint:
+			value, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errors.New("malformed request message")
+			}
+			b = b[n:]
+		case wireBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return nil, errors.New("malformed request message")
+			}
+			data = b[n : n+int(size)]
+			b = b[n+int(size):]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if wire == wireFixed32 {
+				size = 4
+			}
+			if len(b) < size {
+				return nil, errors.New("malformed request message")
+			}
+			b = b[size:]
+			continue // no such fields in SearchRequest
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d", wire)
+		}
+
+		switch field {
+		case 1:
+			req.roots = append(req.roots, string(data))
+		case 2:
+			req.pattern = string(data)
+		case 3:
+			req.types = string(data)
+		case 4:
+			req.regex = value != 0
+		case 5:
+			req.caseSensitive = value != 0
+		case 6:
+			req.smartCase = value != 0
+		case 7:
+			req.follow = value != 0
+		case 8:
+			req.hidden = value != 0
+		case 9:
+			req.noIgnore = value != 0
+		case 10:
+			depth := int32(value)
+			req.maxDepth = &depth
+		case 11:
+			req.minDepth = int32(value)
+		case 12:
+			req.excludes = append(req.excludes, string(data))
+		case 13:
+			req.prunes = append(req.prunes, string(data))
+		case 14:
+			if value > maxPagedMatches {
+				return nil, errors.New("page_size is too large")
+			}
+			req.pageSize = int(value)
+		case 15:
+			req.pageToken = string(data)
+		}
+	}
+	return req, nil
+}
+
+// encodeMatch serializes a Match message, leaving out zero values
+func encodeMatch(match search.Match) []byte {
+	var b []byte
+	b = appendProtoString(b, 1, match.Path)
+	b = appendProtoString(b, 2, match.Type)
+	b = appendProtoVarint(b, 3, uint64(match.Size))
+	if !match.ModTime.IsZero() {
+		b = appendProtoVarint(b, 4, uint64(match.ModTime.UnixNano()))
+	}
+	b = appendProtoVarint(b, 5, uint64(int64(match.Score)))
+	return b
+}
+
+// appendProtoVarint appends a varint field unless it is zero
+func appendProtoVarint(b []byte, field int, value uint64) []byte {
+	if value == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireVarint)
+	return binary.AppendUvarint(b, value)
+}
+
+// appendProtoString appends a length-delimited field unless it is empty
+func appendProtoString(b []byte, field int, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+// appendProtoBytes appends a length-delimited field, such as an embedded
+// message, even when empty
+func appendProtoBytes(b []byte, field int, value []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// writeFiles creates empty files under root, along with their directories
+func writeFiles(t *testing.T, root string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// protoMessage holds the fields of a decoded test message
+type protoMessage struct {
+	varints map[int][]uint64
+	bytes   map[int][][]byte
+}
+
+// decodeProto splits a message of varint and length-delimited fields
+func decodeProto(t *testing.T, b []byte) protoMessage {
+	t.Helper()
+	m := protoMessage{varints: map[int][]uint64{}, bytes: map[int][][]byte{}}
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatalf("malformed key in %x", b)
+		}
+		b = b[n:]
+		field := int(key >> 3)
+		value, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatalf("malformed value of field %d", field)
+		}
+		b = b[n:]
+		switch key & 7 {
+		case wireVarint:
+			m.varints[field] = append(m.varints[field], value)
+		case wireBytes:
+			m.bytes[field] = append(m.bytes[field], b[:value])
+			b = b[value:]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+	}
+	return m
+}
+
+func TestDecodeSearchRequest(t *testing.T) {
+	var b []byte
+	b = appendProtoString(b, 1, "/src")
+	b = appendProtoString(b, 1, "/docs")
+	b = appendProtoString(b, 2, "*.go")
+	b = appendProtoString(b, 3, "f")
+	b = appendProtoVarint(b, 4, 1)
+	b = appendProtoVarint(b, 8, 1)
+	b = binary.AppendUvarint(b, 10<<3|wireVarint) // explicitly zero
+	b = binary.AppendUvarint(b, 0)
+	b = appendProtoVarint(b, 11, 2)
+	b = appendProtoString(b, 12, "*_test.go")
+	b = appendProtoString(b, 13, "vendor")
+	b = appendProtoVarint(b, 14, 50)
+	// Fields of later versions are skipped, whatever their wire type
+	b = appendProtoVarint(b, 99, 7)
+	b = appendProtoString(b, 100, "future")
+	b = binary.AppendUvarint(b, 101<<3|wireFixed32)
+	b = append(b, 1, 2, 3, 4)
+	b = binary.AppendUvarint(b, 102<<3|wireFixed64)
+	b = append(b, 1, 2, 3, 4, 5, 6, 7, 8)
+
+	req, err := decodeSearchRequest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(req.roots, []string{"/src", "/docs"}) || req.pattern != "*.go" || req.types != "f" {
+		t.Errorf("decoded %+v", req)
+	}
+	if !req.regex || req.caseSensitive || !req.hidden || req.noIgnore {
+		t.Errorf("decoded flags %+v", req)
+	}
+	if req.maxDepth == nil || *req.maxDepth != 0 || req.minDepth != 2 {
+		t.Errorf("decoded depths %v, %d", req.maxDepth, req.minDepth)
+	}
+	if !slices.Equal(req.excludes, []string{"*_test.go"}) || !slices.Equal(req.prunes, []string{"vendor"}) || req.pageSize != 50 {
+		t.Errorf("decoded %+v", req)
+	}
+	if query := req.query(); query["maxdepth"][0] != "0" || query["mindepth"][0] != "2" || query["regex"][0] != "true" {
+		t.Errorf("query of the request = %v", query)
+	}
+
+	// An empty message is a request without options
+	req, err = decodeSearchRequest(nil)
+	if err != nil || req.maxDepth != nil || len(req.roots) != 0 {
+		t.Errorf("empty message decoded to %+v, %v", req, err)
+	}
+}
+
+func TestDecodeSearchRequestMalformed(t *testing.T) {
+	valid := appendProtoString(nil, 2, "*.go")
+	tests := map[string][]byte{
+		"truncated key":         {0x80},
+		"truncated varint":      {4<<3 | wireVarint, 0x80},
+		"overlong varint":       append([]byte{4<<3 | wireVarint}, bytes.Repeat([]byte{0xff}, 11)...),
+		"length past the end":   {2<<3 | wireBytes, 5, 'a'},
+		"huge length":           append([]byte{2<<3 | wireBytes}, binary.AppendUvarint(nil, 1<<63)...),
+		"truncated fixed32":     {1<<3 | wireFixed32, 1, 2},
+		"truncated fixed64":     append(valid, 2<<3|wireFixed64, 1),
+		"group wire type":       {1<<3 | 3},
+		"end group wire type":   {1<<3 | 4},
+		"unknown wire type":     {1<<3 | 7},
+		"page size beyond kept": appendProtoVarint(nil, 14, maxPagedMatches+1),
+	}
+	for name, message := range tests {
+		if req, err := decodeSearchRequest(message); err == nil {
+			t.Errorf("%s: decoded %+v, want an error", name, req)
+		}
+	}
+}
+
+func TestReadGRPCMessage(t *testing.T) {
+	message := appendProtoString(nil, 2, "*.go")
+	got, err := readGRPCMessage(bytes.NewReader(grpcFrame(message)))
+	if err != nil || !bytes.Equal(got, message) {
+		t.Errorf("read %x, %v, want %x", got, err, message)
+	}
+
+	compressed := grpcFrame(message)
+	compressed[0] = 1
+	tooLarge := []byte{0, 0xff, 0xff, 0xff, 0xff}
+	tests := map[string][]byte{
+		"empty body":        nil,
+		"short header":      {0, 0, 0},
+		"compressed":        compressed,
+		"too large":         tooLarge,
+		"truncated message": grpcFrame(message)[:6],
+	}
+	for name, body := range tests {
+		if _, err := readGRPCMessage(bytes.NewReader(body)); err == nil {
+			t.Errorf("%s: read without an error", name)
+		}
+	}
+}
+
+func TestEncodeMatch(t *testing.T) {
+	mtime := time.Date(2024, 5, 1, 10, 0, 0, 42, time.UTC)
+	m := decodeProto(t, encodeMatch(search.Match{Path: "a/b.go", Type: "file", Size: 1234, ModTime: mtime, Score: -3}))
+	if string(m.bytes[1][0]) != "a/b.go" || string(m.bytes[2][0]) != "file" {
+		t.Errorf("decoded strings %q", m.bytes)
+	}
+	if m.varints[3][0] != 1234 || int64(m.varints[4][0]) != mtime.UnixNano() {
+		t.Errorf("decoded size and mtime %v", m.varints)
+	}
+	// int32 fields carry negative values sign extended to 64 bits
+	if int32(m.varints[5][0]) != -3 {
+		t.Errorf("decoded score %d", int32(m.varints[5][0]))
+	}
+
+	// Zero values are left out
+	m = decodeProto(t, encodeMatch(search.Match{Path: "x"}))
+	if len(m.varints) != 0 || len(m.bytes) != 1 {
+		t.Errorf("zero fields encoded: %+v", m)
+	}
+}
+
+func TestGRPCEscape(t *testing.T) {
+	if got := grpcEscape("bad 100% café\n"); got != "bad 100%25 caf%C3%A9%0A" {
+		t.Errorf("grpcEscape = %q", got)
+	}
+}
+
+// h2cClient talks unencrypted HTTP/2, as the gRPC server does
+func h2cClient() *http.Client {
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	return &http.Client{Transport: transport}
+}
+
+// grpcCall sends a gRPC request and returns the response messages, the
+// grpc-status trailer and the message with it
+func grpcCall(t *testing.T, url, method string, message []byte) ([][]byte, string, string) {
+	t.Helper()
+	resp, err := h2cClient().Post(url+method, "application/grpc", bytes.NewReader(message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var messages [][]byte
+	for {
+		message, err := readGRPCMessage(resp.Body)
+		if err != nil {
+			break
+		}
+		messages = append(messages, message)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return messages, resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+}
+
+func TestGRPCServer(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "a.go", "b.go", "c.txt", "sub/d.go")
+	queries := newPagedQueries()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handleGRPC(w, r, queries)
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	request := appendProtoString(appendProtoString(nil, 1, root), 2, "*.go")
+	messages, status, _ := grpcCall(t, server.URL, grpcSearchMethod, grpcFrame(request))
+	var paths []string
+	for _, message := range messages {
+		paths = append(paths, string(decodeProto(t, message).bytes[1][0]))
+	}
+	slices.Sort(paths)
+	want := []string{filepath.Join(root, "a.go"), filepath.Join(root, "b.go"), filepath.Join(root, "sub", "d.go")}
+	if status != "0" || !slices.Equal(paths, want) {
+		t.Errorf("Search = %q with status %s, want %q", paths, status, want)
+	}
+
+	// SearchPage hands out pages until the token runs out
+	var paged []string
+	token := ""
+	for range 5 {
+		page := appendProtoVarint(request, 14, 2)
+		if token != "" {
+			page = appendProtoString(appendProtoVarint(nil, 14, 2), 15, token)
+		}
+		messages, status, message := grpcCall(t, server.URL, grpcSearchPageMethod, grpcFrame(page))
+		if status != "0" || len(messages) != 1 {
+			t.Fatalf("SearchPage status %s (%s), %d messages", status, message, len(messages))
+		}
+		m := decodeProto(t, messages[0])
+		for _, match := range m.bytes[1] {
+			paged = append(paged, string(decodeProto(t, match).bytes[1][0]))
+		}
+		if len(m.bytes[2]) == 0 {
+			break
+		}
+		token = string(m.bytes[2][0])
+	}
+	slices.Sort(paged)
+	if !slices.Equal(paged, want) {
+		t.Errorf("SearchPage pages = %q, want %q", paged, want)
+	}
+
+	// Bad requests get a status, never a crash
+	tests := []struct {
+		name, method string
+		body         []byte
+		status       string
+	}{
+		{"unknown method", "/gosearch.v1.SearchService/Other", grpcFrame(request), "12"},
+		{"no message", grpcSearchMethod, nil, "3"},
+		{"malformed message", grpcSearchMethod, grpcFrame([]byte{0x80}), "3"},
+		{"no pattern", grpcSearchMethod, grpcFrame(appendProtoString(nil, 1, root)), "3"},
+		{"bad regex", grpcSearchMethod, grpcFrame(appendProtoVarint(appendProtoString(request[:len(request):len(request)], 2, "("), 4, 1)), "3"},
+		{"unknown token", grpcSearchPageMethod, grpcFrame(appendProtoString(nil, 15, "nope:0")), "5"},
+		{"bad token offset", grpcSearchPageMethod, grpcFrame(appendProtoString(nil, 15, "x:-1")), "5"},
+	}
+	for _, tt := range tests {
+		_, status, message := grpcCall(t, server.URL, tt.method, tt.body)
+		if status != tt.status {
+			t.Errorf("%s: status %s (%s), want %s", tt.name, status, message, tt.status)
+		}
+	}
+
+	// Requests other than gRPC are turned away
+	resp, err := h2cClient().Post(server.URL+grpcSearchMethod, "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("plain POST got %s", resp.Status)
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sean1832/go-search/index"
+	"github.com/sean1832/go-search/search"
+)
+
+// How long index watch waits for changes to settle by default
+const defaultWatchDelay = time.Second
+
+// runIndex implements the index subcommand:
+//
+//	index <directory>...          build indexes from scratch
+//	index update [<directory>...] refresh indexes, all of them by default
+//	index watch [<directory>...]  keep indexes up to date as files change
+func runIndex(program string, args []string) int {
+	update, watch := false, false
+	if len(args) > 0 && (args[0] == "update" || args[0] == "watch") {
+		update, watch = args[0] == "update", args[0] == "watch"
+		args = args[1:]
+	}
+	for _, arg := range args {
+		if arg == "-h" || arg == "--help" {
+			displayIndexHelp(program)
+			return 0
+		}
+	}
+	if watch {
+		return watchIndexes(program, args)
+	}
+
+	if !update {
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: index requires at least one directory")
+			displayIndexHelp(program)
+			return exitUsage
+		}
+		return indexRoots(args, func(root string) (*index.Index, index.Stats, error) {
+			return index.Build(root)
+		})
+	}
+
+	// Without directories, refresh every index in the cache
+	if len(args) == 0 {
+		indexes, err := index.All()
+		if err != nil {
+			logger.Error("cannot list the indexes", "err", err)
+			return exitFailure
+		}
+		for _, idx := range indexes {
+			args = append(args, idx.Root)
+		}
+		if len(args) == 0 {
+			fmt.Println("No indexes to update")
+			return 0
+		}
+	}
+	return indexRoots(args, func(root string) (*index.Index, index.Stats, error) {
+		path, err := index.PathFor(root)
+		if err != nil {
+			return nil, index.Stats{}, err
+		}
+		idx, err := index.Load(path)
+		if err != nil {
+			return nil, index.Stats{}, fmt.Errorf("no index for %s, build one with: %s index %s", root, program, root)
+		}
+		return idx.Update()
+	})
+}
+
+// watchIndexes implements index watch, updating the indexes of the roots,
+// or of every index in the cache, as their trees change until interrupted.
+// Roots without an index get one first.
+func watchIndexes(program string, args []string) int {
+	delay := defaultWatchDelay
+	var roots []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--delay":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+			if delay, err = time.ParseDuration(value); err != nil || delay <= 0 {
+				fmt.Fprintln(os.Stderr, "Error: invalid value for --delay:", value)
+				return exitUsage
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				fmt.Fprintln(os.Stderr, "Error: unknown argument:", args[i])
+				displayIndexHelp(program)
+				return exitUsage
+			}
+			roots = append(roots, args[i])
+		}
+	}
+	if len(roots) == 0 {
+		indexes, err := index.All()
+		if err != nil {
+			logger.Error("cannot list the indexes", "err", err)
+			return exitFailure
+		}
+		for _, idx := range indexes {
+			roots = append(roots, idx.Root)
+		}
+		if len(roots) == 0 {
+			fmt.Println("No indexes to watch")
+			return 0
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var mu sync.Mutex // keeps the lines of the roots apart
+	var wg sync.WaitGroup
+	exitCode := 0
+	for _, root := range roots {
+		idx, _, err := loadOrBuildIndex(root)
+		if err != nil {
+			logger.Error("cannot index", "root", root, "err", err)
+			exitCode = exitFailure
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := idx.Watch(ctx, delay, func(next *index.Index, stats index.Stats) error {
+				if err := next.Save(); err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				fmt.Printf("Indexed %d entries under %s (%d directories read, %d reused, %d errors)\n",
+					stats.Entries, next.Root, stats.ScannedDirs, stats.ReusedDirs, stats.Errors)
+				return nil
+			})
+			if err != nil {
+				mu.Lock()
+				logger.Error("cannot watch", "root", idx.Root, "err", err)
+				exitCode = exitFailure
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return exitCode
+}
+
+// indexRoots builds or updates the index of each root and saves it
+func indexRoots(roots []string, build func(root string) (*index.Index, index.Stats, error)) int {
+	exitCode := 0
+	for _, root := range roots {
+		start := time.Now()
+		idx, stats, err := build(root)
+		if err == nil {
+			err = idx.Save()
+		}
+		if err != nil {
+			logger.Error("cannot index", "root", root, "err", err)
+			exitCode = exitFailure
+			continue
+		}
+		fmt.Printf("Indexed %d entries under %s in %s (%d directories read, %d reused, %d errors)\n",
+			stats.Entries, idx.Root, time.Since(start).Round(time.Millisecond),
+			stats.ScannedDirs, stats.ReusedDirs, stats.Errors)
+	}
+	return exitCode
+}
+
+// displayIndexHelp prints usage instructions for the index subcommand
+func displayIndexHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s index <directory>...\n", program)
+	fmt.Fprintf(os.Stderr, "       %s index update [<directory>...]\n", program)
+	fmt.Fprintf(os.Stderr, "       %s index watch [--delay <duration>] [<directory>...]\n", program)
+	fmt.Fprintln(os.Stderr, "Builds an on-disk index of each directory for use with --use-index.")
+	fmt.Fprintln(os.Stderr, "update refreshes existing indexes, only re-reading directories that")
+	fmt.Fprintln(os.Stderr, "changed since the index was built; without directories it updates all.")
+	fmt.Fprintln(os.Stderr, "watch keeps the indexes up to date until interrupted, re-reading the")
+	fmt.Fprintln(os.Stderr, "directories filesystem notifications name once changes have settled for")
+	fmt.Fprintf(os.Stderr, "--delay (default: %s), or for ten times as long at most. Without\n", defaultWatchDelay)
+	fmt.Fprintln(os.Stderr, "notifications (anywhere but Linux and Windows) it updates the indexes every")
+	fmt.Fprintln(os.Stderr, "few seconds instead.")
+}
+
+// streamIndexed answers a search from the indexes covering the roots
+// instead of walking them, stopping after limit matches when limit is
+// positive. Ignore files are not applied.
+func streamIndexed(ctx context.Context, run *search.Run, roots []string, limit int) (<-chan search.Match, <-chan error) {
+	results := make(chan search.Match)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(results)
+		found := 0
+		for _, root := range search.UniqueRoots(roots) {
+			idx, err := index.Find(root)
+			if err != nil {
+				errc <- err
+				return
+			}
+			entries, err := idx.Under(root)
+			if err != nil {
+				errc <- err
+				return
+			}
+			for _, entry := range entries {
+				path := filepath.Join(root, filepath.FromSlash(entry.Path))
+				match, ok := run.Check(root, path, entry.DirEntry())
+				if !ok {
+					continue
+				}
+				select {
+				case results <- match:
+				case <-ctx.Done():
+					errc <- ctx.Err()
+					return
+				}
+				if found++; found == limit {
+					return
+				}
+			}
+		}
+	}()
+
+	return results, errc
+}
+
+// streamLocateDB answers a search from an mlocate database instead of
+// walking the roots, leaving out paths removed since it was written. It
+// stops after limit matches when limit is positive.
+func streamLocateDB(ctx context.Context, run *search.Run, db string, roots []string, limit int) (<-chan search.Match, <-chan error) {
+	results := make(chan search.Match)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(results)
+		found := 0
+		for _, root := range search.UniqueRoots(roots) {
+			abs, err := filepath.Abs(root)
+			if err != nil {
+				errc <- err
+				return
+			}
+			err = index.ReadLocateDB(db, func(path string, d fs.DirEntry) error {
+				rel, err := filepath.Rel(abs, path)
+				if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					return nil
+				}
+				match, ok := run.Check(root, filepath.Join(root, rel), d)
+				if !ok {
+					return nil
+				}
+				// The database may be older than the tree, like locate -e
+				if _, err := match.Info(); errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				select {
+				case results <- match:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				if found++; found == limit {
+					return errEnoughMatches
+				}
+				return nil
+			})
+			if errors.Is(err, errEnoughMatches) {
+				return
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	return results, errc
+}
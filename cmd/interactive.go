@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// How often the result list is redrawn while the walk is still running
+const interactiveRefresh = 100 * time.Millisecond
+
+// Key codes read from a terminal in raw mode
+const (
+	keyCtrlC     = 0x03
+	keyCtrlN     = 0x0e
+	keyCtrlP     = 0x10
+	keyCtrlU     = 0x15
+	keyEnter     = '\r'
+	keyEscape    = 0x1b
+	keyBackspace = 0x7f
+	keyCtrlH     = 0x08
+)
+
+// interactiveUI is the state of the terminal interface: the candidates
+// collected by the walk so far and the ones matching the current query
+type interactiveUI struct {
+	opts  *Options
+	out   *bufio.Writer
+	query []rune
+
+	mu         sync.Mutex
+	candidates []string
+	walking    bool
+
+	results  []string
+	selected int
+	offset   int
+	matched  int  // candidates seen when results were last computed
+	drawnAs  bool // walking state shown by the last draw
+	queryErr error
+}
+
+// runInteractive walks the roots in the background and lets the user
+// narrow the results by typing, like fzf. The interface is drawn on stderr
+// so the selected path, printed to stdout on Enter, can be captured.
+func runInteractive(opts *Options) int {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+		fmt.Fprintln(os.Stderr, "Error: --interactive requires a terminal")
+		return exitUsage
+	}
+
+	// Every entry passing the filters is a candidate; the query does the rest
+	walkOpts := *opts
+	walkOpts.pattern = "*"
+	walkOpts.isRegex = false
+	walkOpts.isFuzzy = false
+	walkOpts.isFixed = false
+	walkOpts.sortKey = ""
+	searcher, err := newSearcher(&walkOpts, search.WithErrorHandler(func(path string, err error) {
+		skipped.Store(true)
+	}))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+
+	ui := &interactiveUI{opts: opts, out: bufio.NewWriter(os.Stderr), query: []rune(opts.pattern), walking: true}
+	selection, err := ui.run(searcher)
+	if err != nil {
+		logger.Error("interactive search failed", "err", err)
+		return exitFailure
+	}
+	if selection == "" {
+		return exitNoMatch
+	}
+	fmt.Println(selection)
+	return exitMatch
+}
+
+// run takes over the terminal until a result is picked, returning it, or
+// the user gives up, returning an empty selection
+func (ui *interactiveUI) run(searcher *search.Searcher) (string, error) {
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	ui.out.WriteString("\x1b[?1049h") // alternate screen
+	defer func() {
+		ui.out.WriteString("\x1b[?1049l")
+		ui.out.Flush()
+		restore()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	matches, errc := searcher.Stream(ctx, ui.opts.directories...)
+	go func() {
+		for match := range matches {
+			ui.mu.Lock()
+			ui.candidates = append(ui.candidates, match.Path)
+			ui.mu.Unlock()
+		}
+		<-errc
+		ui.mu.Lock()
+		ui.walking = false
+		ui.mu.Unlock()
+	}()
+
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	ticker := time.NewTicker(interactiveRefresh)
+	defer ticker.Stop()
+	ui.filter()
+	ui.draw()
+	for {
+		select {
+		case input, ok := <-keys:
+			if !ok {
+				return "", nil
+			}
+			if selection, done := ui.handleKeys(input); done {
+				return selection, nil
+			}
+		case <-ticker.C:
+			ui.mu.Lock()
+			grown := len(ui.candidates) != ui.matched
+			finished := ui.walking != ui.drawnAs
+			ui.mu.Unlock()
+			if !grown && !finished {
+				continue
+			}
+			if grown {
+				ui.filter()
+			}
+		}
+		ui.draw()
+	}
+}
+
+// handleKeys applies a chunk of input, reporting whether the interface is
+// done along with the selected path, if any
+func (ui *interactiveUI) handleKeys(input []byte) (string, bool) {
+	queryChanged := false
+	for len(input) > 0 {
+		switch {
+		case input[0] == keyCtrlC || len(input) == 1 && input[0] == keyEscape:
+			return "", true
+		case input[0] == keyEnter || input[0] == '\n':
+			if len(ui.results) == 0 {
+				return "", true
+			}
+			return ui.results[ui.selected], true
+		case input[0] == keyEscape && len(input) >= 3 && input[1] == '[':
+			switch input[2] {
+			case 'A':
+				ui.move(-1)
+			case 'B':
+				ui.move(1)
+			}
+			input = input[3:]
+			continue
+		case input[0] == keyCtrlP:
+			ui.move(-1)
+		case input[0] == keyCtrlN:
+			ui.move(1)
+		case input[0] == keyBackspace || input[0] == keyCtrlH:
+			if len(ui.query) > 0 {
+				ui.query = ui.query[:len(ui.query)-1]
+				queryChanged = true
+			}
+		case input[0] == keyCtrlU:
+			ui.query = nil
+			queryChanged = true
+		case input[0] >= 0x20:
+			r, size := utf8.DecodeRune(input)
+			ui.query = append(ui.query, r)
+			queryChanged = true
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	if queryChanged {
+		ui.filter()
+	}
+	return "", false
+}
+
+// move changes the selection by delta, staying within the results
+func (ui *interactiveUI) move(delta int) {
+	ui.selected = max(0, min(ui.selected+delta, len(ui.results)-1))
+}
+
+// filter recomputes the results for the current query: fuzzy matches best
+// first, or regex matches in walk order with --regex
+func (ui *interactiveUI) filter() {
+	ui.mu.Lock()
+	candidates := ui.candidates[:len(ui.candidates):len(ui.candidates)]
+	ui.mu.Unlock()
+	ui.matched = len(candidates)
+	ui.queryErr = nil
+
+	query := ui.opts.normalization.Apply(string(ui.query))
+	if query == "" {
+		ui.results = candidates
+		ui.clampSelection()
+		return
+	}
+
+	var matcher search.Matcher
+	var err error
+	if ui.opts.isRegex {
+		matcher, err = search.NewRegexMatcher(query, ui.opts.caseSensitiveFor(query, true))
+	} else if ui.opts.isFixed {
+		matcher, err = search.NewFixedMatcher(query, ui.opts.caseSensitiveFor(query, false))
+	} else {
+		matcher, err = search.NewFuzzyMatcher(query, ui.opts.caseSensitiveFor(query, false), ui.opts.fuzzyThreshold)
+	}
+	if err != nil {
+		// Keep showing the last results while a regex is being typed
+		ui.queryErr = err
+		return
+	}
+
+	type scored struct {
+		path  string
+		score int
+	}
+	var found []scored
+	scorer, _ := matcher.(search.Scorer)
+	for _, path := range candidates {
+		target := ui.opts.normalization.Apply(path)
+		if !matcher.Match(target) {
+			continue
+		}
+		s := scored{path: path}
+		if scorer != nil {
+			s.score, _ = scorer.Score(target)
+		}
+		found = append(found, s)
+	}
+	if scorer != nil {
+		sort.SliceStable(found, func(i, j int) bool {
+			if found[i].score != found[j].score {
+				return found[i].score > found[j].score
+			}
+			return len(found[i].path) < len(found[j].path)
+		})
+	}
+	ui.results = make([]string, len(found))
+	for i, s := range found {
+		ui.results[i] = s.path
+	}
+	ui.clampSelection()
+}
+
+// clampSelection keeps the selection on an existing result
+func (ui *interactiveUI) clampSelection() {
+	ui.selected = max(0, min(ui.selected, len(ui.results)-1))
+}
+
+// draw renders the prompt, a status line and as many results as fit
+func (ui *interactiveUI) draw() {
+	width, height, err := terminalSize(os.Stderr)
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	rows := max(height-2, 1)
+	if ui.selected < ui.offset {
+		ui.offset = ui.selected
+	} else if ui.selected >= ui.offset+rows {
+		ui.offset = ui.selected - rows + 1
+	}
+
+	ui.mu.Lock()
+	total, walking := len(ui.candidates), ui.walking
+	ui.mu.Unlock()
+	ui.drawnAs = walking
+	status := fmt.Sprintf("  %d/%d", len(ui.results), total)
+	if walking {
+		status += " (searching...)"
+	}
+	if ui.queryErr != nil {
+		status += "  " + ui.queryErr.Error()
+	}
+
+	out := ui.out
+	out.WriteString("\x1b[?25l\x1b[H")
+	prompt := "> " + string(ui.query)
+	out.WriteString(truncate(prompt, width) + "\x1b[K\r\n")
+	out.WriteString("\x1b[2m" + truncate(status, width) + "\x1b[0m\x1b[K")
+	for i := ui.offset; i < len(ui.results) && i < ui.offset+rows; i++ {
+		out.WriteString("\r\n")
+		line := truncate("  "+ui.results[i], width)
+		if i == ui.selected {
+			line = "\x1b[7m" + truncate("> "+ui.results[i], width) + "\x1b[0m"
+		}
+		out.WriteString(line + "\x1b[K")
+	}
+	out.WriteString("\x1b[J")
+	fmt.Fprintf(out, "\x1b[1;%dH\x1b[?25h", min(utf8.RuneCountInString(prompt)+1, width))
+	out.Flush()
+}
+
+// truncate cuts s to at most width runes
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
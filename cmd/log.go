@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logger receives the diagnostics, on stderr: paths skipped at the info
+// level, problems at the warn and error levels. By default only the latter
+// are printed; --verbose or --log-level tell otherwise.
+var logger = slog.New(newLogHandler(os.Stderr, "text", slog.LevelWarn))
+
+// newLogHandler returns the handler of a --log-format writing to w. Text
+// records leave out the time, since they are read as they come.
+func newLogHandler(w io.Writer, format string, level slog.Level) slog.Handler {
+	if format == "json" {
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	}
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+// parseLogLevel parses a --log-level: debug, info, warn or error
+func parseLogLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level: %s (expected debug, info, warn or error)", name)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// errEnoughMatches stops a scan once the result limit is reached
+var errEnoughMatches = errors.New("enough matches")
+
+// streamMFT answers a search from the Master File Table of the volumes
+// holding the roots, walking the roots whose table can't be read instead.
+// It stops after limit matches when limit is positive.
+func streamMFT(ctx context.Context, run *search.Run, roots []string, limit int) (<-chan search.Match, <-chan error) {
+	results := make(chan search.Match)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(results)
+		found := 0
+		send := func(match search.Match) error {
+			select {
+			case results <- match:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if found++; found == limit {
+				return errEnoughMatches
+			}
+			return nil
+		}
+		for _, root := range search.UniqueRoots(roots) {
+			err := search.ScanMFT(ctx, root, func(path string, d fs.DirEntry) error {
+				match, ok := run.Check(root, path, d)
+				if !ok {
+					return nil
+				}
+				return send(match)
+			})
+			if errors.Is(err, search.ErrMFTUnavailable) {
+				logger.Info("walking instead", "root", root, "err", err)
+				err = walkRoot(ctx, run, root, send)
+			}
+			if errors.Is(err, errEnoughMatches) {
+				return
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	return results, errc
+}
+
+// walkRoot searches a root by walking it, passing each match to send until
+// it returns an error
+func walkRoot(ctx context.Context, run *search.Run, root string, send func(search.Match) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	matches, errc := run.Stream(ctx, root)
+	for match := range matches {
+		if err := send(match); err != nil {
+			cancel()
+			for range matches {
+			}
+			return err
+		}
+	}
+	return <-errc
+}
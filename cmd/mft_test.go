@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sean1832/go-search/search"
+)
+
+func TestStreamMFT(t *testing.T) {
+	// Wherever the table can't be read, the roots are walked instead
+	root := t.TempDir()
+	writeFiles(t, root, "a.go", "b.txt", "sub/c.go")
+	searcher, err := search.New("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, limit := range []int{0, 1} {
+		matches, errc := streamMFT(context.Background(), searcher.NewRun(), []string{root, root}, limit)
+		var paths []string
+		for m := range matches {
+			rel, _ := filepath.Rel(root, m.Path)
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(paths)
+		if limit == 0 && !slices.Equal(paths, []string{"a.go", "sub/c.go"}) || limit == 1 && len(paths) != 1 {
+			t.Errorf("limit %d: found %q", limit, paths)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	matches, errc := streamMFT(ctx, searcher.NewRun(), []string{root}, 0)
+	for range matches {
+	}
+	if err := <-errc; err == nil {
+		t.Error("cancelled search ended without an error")
+	}
+}
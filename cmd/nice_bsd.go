@@ -0,0 +1,11 @@
+//go:build dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import "syscall"
+
+// lowerPriority gives the process the lowest CPU priority, the BSDs having
+// no I/O priority of their own
+func lowerPriority() error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, 0, 19)
+}
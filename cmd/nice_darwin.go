@@ -0,0 +1,17 @@
+//go:build darwin
+
+package main
+
+import "syscall"
+
+// Background policy of setpriority, from sys/resource.h, which lowers the
+// CPU, I/O and network priority of the whole process
+const (
+	prioDarwinProcess = 4
+	prioDarwinBG      = 0x1000
+)
+
+// lowerPriority moves the process to the background policy
+func lowerPriority() error {
+	return syscall.Setpriority(prioDarwinProcess, 0, prioDarwinBG)
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package main
+
+import "errors"
+
+// lowerPriority is unsupported on this platform
+func lowerPriority() error {
+	return errors.New("--io-nice is not supported on this platform")
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package main
+
+import "syscall"
+
+// Priority class lowering the CPU, I/O and memory priority of the process
+const processModeBackgroundBegin = 0x00100000
+
+var procSetPriorityClass = kernel32.NewProc("SetPriorityClass")
+
+// lowerPriority moves the process to background processing mode
+func lowerPriority() error {
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return err
+	}
+	if r, _, err := procSetPriorityClass.Call(uintptr(process), processModeBackgroundBegin); r == 0 {
+		return err
+	}
+	return nil
+}
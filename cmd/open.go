@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Number of files --open opens without asking first, unless configured
+const defaultOpenConfirm = 10
+
+// editorCommand returns the command line opening files: the --open-with
+// command, $VISUAL or $EDITOR, or a platform default
+func editorCommand(openWith string) []string {
+	for _, command := range []string{openWith, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if fields := strings.Fields(command); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// OpenMatches opens every match with a single run of the command, asking
+// for confirmation on stdin when there are more than confirm of them.
+// It returns the command's exit code, or 1 when the user declines.
+func OpenMatches(command []string, matches <-chan search.Match, confirm int) int {
+	var paths []string
+	for match := range matches {
+		paths = append(paths, match.Path)
+	}
+	if len(paths) == 0 {
+		return 0
+	}
+	if len(paths) > confirm && !confirmOpen(os.Stdin, os.Stderr, len(paths)) {
+		return 1
+	}
+	return runCommand(substituteArgs(command, paths), os.Stdin, os.Stdout, os.Stderr)
+}
+
+// confirmOpen asks whether to open n files, defaulting to no
+func confirmOpen(r io.Reader, w io.Writer, n int) bool {
+	fmt.Fprintf(w, "Open %d files? [y/N] ", n)
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
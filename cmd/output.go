@@ -0,0 +1,485 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Formatter writes matches to an output stream in a specific format
+type Formatter interface {
+	// Write outputs a single match
+	Write(match search.Match) error
+	// Close finishes the output once all matches have been written
+	Close() error
+}
+
+// formatConfig holds settings shared by the formatters
+type formatConfig struct {
+	colors     *colorizer                // colors for the text and long formats, may be nil
+	humanSizes bool                      // print sizes with units in the long format
+	acl        bool                      // matches carry ACLs, which get a long format column
+	secContext bool                      // matches carry SELinux contexts, which get one too
+	roots      []string                  // directories searched, which the tree is drawn from
+	keepOrder  bool                      // results are sorted, so the tree and groups keep their order
+	hashes     bool                      // matches carry checksums, which get a csv column
+	template   *template.Template        // the template format's template
+	errors     func() []search.WalkError // paths skipped, which the json formats list when set
+	report     string                    // the report format's report
+}
+
+// NewFormatter creates the formatter registered under name
+func NewFormatter(name string, w io.Writer, config formatConfig) (Formatter, error) {
+	switch name {
+	case "", "text":
+		return &textFormatter{w: w, colors: config.colors}, nil
+	case "long":
+		return &longFormatter{w: w, colors: config.colors, human: config.humanSizes, acl: config.acl, secContext: config.secContext}, nil
+	case "tree":
+		return newTreeFormatter(w, config), nil
+	case "group":
+		return newGroupFormatter(w, config), nil
+	case "report":
+		newReport, ok := reports[config.report]
+		if !ok {
+			return nil, fmt.Errorf("unknown report: %s", config.report)
+		}
+		return newReport(w), nil
+	case "du":
+		return &duFormatter{w: w, colors: config.colors, human: config.humanSizes}, nil
+	case "json":
+		return &jsonFormatter{w: w, errors: config.errors}, nil
+	case "jsonl":
+		return &jsonlFormatter{enc: json.NewEncoder(w), errors: config.errors}, nil
+	case "csv":
+		return newCSVFormatter(w, ',', config.hashes), nil
+	case "tsv":
+		return newCSVFormatter(w, '\t', config.hashes), nil
+	case "template":
+		return &templateFormatter{w: w, template: config.template}, nil
+	case "print0":
+		return &print0Formatter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format: %s", name)
+	}
+}
+
+// newPathRewriter returns a function turning match paths into absolute
+// paths, or into paths relative to base, or nil to leave them as walked
+func newPathRewriter(absolute bool, base string) (func(string) string, error) {
+	if !absolute && base == "" {
+		return nil, nil
+	}
+	if base == "" {
+		return func(path string) string {
+			if abs, err := filepath.Abs(path); err == nil {
+				return abs
+			}
+			return filepath.Clean(path)
+		}, nil
+	}
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return nil, err
+	}
+	return func(path string) string {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return filepath.Clean(path)
+		}
+		if rel, err := filepath.Rel(absBase, abs); err == nil {
+			return rel
+		}
+		return abs // on another volume than the base
+	}, nil
+}
+
+// rewritePaths passes matches through with their paths rewritten
+func rewritePaths(matches <-chan search.Match, rewrite func(string) string) <-chan search.Match {
+	out := make(chan search.Match)
+	go func() {
+		defer close(out)
+		for match := range matches {
+			match.Path = rewrite(match.Path)
+			out <- match
+		}
+	}()
+	return out
+}
+
+// textFormatter prints one path per line under a heading
+type textFormatter struct {
+	w      io.Writer
+	colors *colorizer
+	count  int
+}
+
+func (f *textFormatter) Write(match search.Match) error {
+	if f.count == 0 {
+		if _, err := fmt.Fprintln(f.w, "Found Paths:"); err != nil {
+			return err
+		}
+	}
+	f.count++
+	path := match.Path
+	if f.colors != nil {
+		path = f.colors.Path(match)
+	}
+	if match.Hash != "" {
+		path = match.Hash + "  " + path
+	}
+	// Tagged matches get a line for each pattern they match
+	for _, pattern := range match.Patterns {
+		if _, err := fmt.Fprintf(f.w, "%s\t%s\n", pattern, path); err != nil {
+			return err
+		}
+	}
+	if len(match.Patterns) > 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(f.w, path)
+	return err
+}
+
+func (f *textFormatter) Close() error {
+	if f.count == 0 {
+		_, err := fmt.Fprintln(f.w, "No path matches the pattern")
+		return err
+	}
+	return nil
+}
+
+// longFormatter prints ls -l style details of every match in aligned
+// columns, which means holding all rows until Close
+type longFormatter struct {
+	w          io.Writer
+	colors     *colorizer
+	human      bool
+	acl        bool
+	secContext bool
+	owners     ownerNames
+	rows       [][]string
+}
+
+func (f *longFormatter) Write(match search.Match) error {
+	owner, group := "-", "-"
+	// Indexed matches carry no ownership, so those are read from disk
+	info, err := match.Info()
+	if err != nil || info.Sys() == nil {
+		info, err = os.Lstat(match.Path)
+	}
+	if err == nil {
+		owner, group = f.owners.lookup(info)
+	}
+	size := strconv.FormatInt(match.Size, 10)
+	if f.human {
+		size = humanSize(match.Size)
+	}
+	path := match.Path
+	if f.colors != nil {
+		path = f.colors.Path(match)
+	}
+	// As with ls, a + after the mode tells of an ACL
+	mode := match.Mode.String()
+	if match.ACL != "" {
+		mode += "+"
+	}
+	row := []string{mode, owner, group, size, match.ModTime.Format("2006-01-02 15:04")}
+	if f.acl {
+		row = append(row, orDash(match.ACL))
+	}
+	if f.secContext {
+		row = append(row, orDash(match.SELinux))
+	}
+	f.rows = append(f.rows, append(row, path))
+	return nil
+}
+
+func (f *longFormatter) Close() error {
+	if len(f.rows) == 0 {
+		_, err := fmt.Fprintln(f.w, "No path matches the pattern")
+		return err
+	}
+
+	// Sizes are right aligned, the other columns left aligned; the path
+	// is last and never padded
+	widths := make([]int, len(f.rows[0])-1)
+	for _, row := range f.rows {
+		for i := range widths {
+			widths[i] = max(widths[i], len(row[i]))
+		}
+	}
+	const sizeColumn = 3
+	for _, row := range f.rows {
+		var line strings.Builder
+		for i, width := range widths {
+			if i == sizeColumn {
+				fmt.Fprintf(&line, "%*s  ", width, row[i])
+			} else {
+				fmt.Fprintf(&line, "%-*s  ", width, row[i])
+			}
+		}
+		line.WriteString(row[len(row)-1])
+		if _, err := fmt.Fprintln(f.w, line.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orDash returns s, or - when empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// humanSize formats a byte count with binary units, as ls -h does
+func humanSize(size int64) string {
+	const units = "KMGTPE"
+	if size < 1024 {
+		return strconv.FormatInt(size, 10)
+	}
+	value := float64(size)
+	unit := -1
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, units[unit])
+	}
+	return fmt.Sprintf("%.0f%c", value, units[unit])
+}
+
+// duFormatter prints the size of each match before its path, as du does
+type duFormatter struct {
+	w      io.Writer
+	colors *colorizer
+	human  bool
+	count  int
+}
+
+func (f *duFormatter) Write(match search.Match) error {
+	f.count++
+	size := strconv.FormatInt(match.Size, 10)
+	if f.human {
+		size = humanSize(match.Size)
+	}
+	path := match.Path
+	if f.colors != nil {
+		path = f.colors.Path(match)
+	}
+	_, err := fmt.Fprintf(f.w, "%s\t%s\n", size, path)
+	return err
+}
+
+func (f *duFormatter) Close() error {
+	if f.count == 0 {
+		_, err := fmt.Fprintln(f.w, "No path matches the pattern")
+		return err
+	}
+	return nil
+}
+
+// print0Formatter prints bare paths terminated by NUL bytes for xargs -0
+type print0Formatter struct {
+	w io.Writer
+}
+
+func (f *print0Formatter) Write(match search.Match) error {
+	_, err := fmt.Fprintf(f.w, "%s\x00", match.Path)
+	return err
+}
+
+func (f *print0Formatter) Close() error {
+	return nil
+}
+
+// parseOutputTemplate parses a --template, where \t, \n and \\ stand for
+// a tab, a newline and a backslash so that they can be given in shell
+// quotes. Besides the builtins, {{human .Size}} formats a size like --human.
+func parseOutputTemplate(text string) (*template.Template, error) {
+	unescape := strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n")
+	tmpl, err := template.New("output").Option("missingkey=zero").Funcs(template.FuncMap{"human": humanSize}).Parse(unescape.Replace(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// templateFormatter prints each match through a text/template, one per line
+type templateFormatter struct {
+	w        io.Writer
+	template *template.Template
+	buf      bytes.Buffer
+}
+
+func (f *templateFormatter) Write(match search.Match) error {
+	f.buf.Reset()
+	if err := f.template.Execute(&f.buf, match); err != nil {
+		return err
+	}
+	f.buf.WriteByte('\n')
+	_, err := f.w.Write(f.buf.Bytes())
+	return err
+}
+
+func (f *templateFormatter) Close() error {
+	return nil
+}
+
+// jsonFormatter prints all matches as a single JSON array. With errors
+// set, the array is the matches field of an object whose errors field
+// holds the paths skipped because of an error.
+type jsonFormatter struct {
+	w      io.Writer
+	errors func() []search.WalkError // may be nil
+	count  int
+}
+
+func (f *jsonFormatter) Write(match search.Match) error {
+	data, err := json.Marshal(match)
+	if err != nil {
+		return err
+	}
+	separator, start := ",\n  ", "[\n  "
+	if f.errors != nil {
+		separator, start = ",\n    ", "{\n  \"matches\": [\n    "
+	}
+	if f.count == 0 {
+		separator = start
+	}
+	f.count++
+	_, err = fmt.Fprintf(f.w, "%s%s", separator, data)
+	return err
+}
+
+func (f *jsonFormatter) Close() error {
+	if f.errors == nil {
+		if f.count == 0 {
+			_, err := fmt.Fprintln(f.w, "[]")
+			return err
+		}
+		_, err := fmt.Fprintln(f.w, "\n]")
+		return err
+	}
+	var buf strings.Builder
+	if f.count == 0 {
+		buf.WriteString("{\n  \"matches\": [],\n")
+	} else {
+		buf.WriteString("\n  ],\n")
+	}
+	errs := f.errors()
+	if len(errs) == 0 {
+		buf.WriteString("  \"errors\": []\n}\n")
+	} else {
+		for i, e := range errs {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			separator := ",\n    "
+			if i == 0 {
+				separator = "  \"errors\": [\n    "
+			}
+			buf.WriteString(separator)
+			buf.Write(data)
+		}
+		buf.WriteString("\n  ]\n}\n")
+	}
+	_, err := io.WriteString(f.w, buf.String())
+	return err
+}
+
+// csvFormatter prints a header row followed by one row per match, quoting
+// fields as needed; with a tab separator it produces TSV
+type csvFormatter struct {
+	w      *csv.Writer
+	header bool
+	hashes bool // add a hash column
+}
+
+// csvHeader names the columns written by csvFormatter
+var csvHeader = []string{"path", "type", "size", "mtime"}
+
+func newCSVFormatter(w io.Writer, separator rune, hashes bool) *csvFormatter {
+	cw := csv.NewWriter(w)
+	cw.Comma = separator
+	return &csvFormatter{w: cw, hashes: hashes}
+}
+
+func (f *csvFormatter) writeHeader() error {
+	if f.header {
+		return nil
+	}
+	f.header = true
+	if f.hashes {
+		return f.w.Write(append(csvHeader[:len(csvHeader):len(csvHeader)], "hash"))
+	}
+	return f.w.Write(csvHeader)
+}
+
+func (f *csvFormatter) Write(match search.Match) error {
+	if err := f.writeHeader(); err != nil {
+		return err
+	}
+	row := []string{
+		match.Path, match.Type, strconv.FormatInt(match.Size, 10),
+		match.ModTime.Format(time.RFC3339),
+	}
+	if f.hashes {
+		row = append(row, match.Hash)
+	}
+	return f.w.Write(row)
+}
+
+func (f *csvFormatter) Close() error {
+	if err := f.writeHeader(); err != nil {
+		return err
+	}
+	f.w.Flush()
+	return f.w.Error()
+}
+
+// jsonlFormatter prints one JSON object per line
+type jsonlFormatter struct {
+	enc    *json.Encoder
+	errors func() []search.WalkError // may be nil
+}
+
+// jsonlError is the record of a skipped path, told apart from the matches
+// by its type
+type jsonlError struct {
+	Type string `json:"type"` // always "error"
+	search.WalkError
+}
+
+func (f *jsonlFormatter) Write(match search.Match) error {
+	return f.enc.Encode(match)
+}
+
+// Close follows the matches with a record for each path skipped because of
+// an error, when errors is set
+func (f *jsonlFormatter) Close() error {
+	if f.errors == nil {
+		return nil
+	}
+	for _, e := range f.errors() {
+		if err := f.enc.Encode(jsonlError{Type: "error", WalkError: e}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sean1832/go-search/search"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	matches := []search.Match{{Path: "a.go"}, {Path: "b.go"}}
+	skipped := []search.WalkError{{Path: "locked", Reason: "permission denied", Errno: 13}}
+	for _, n := range []int{0, 1, 2} {
+		// Without errors the output is the array of matches
+		var buf bytes.Buffer
+		f, err := NewFormatter("json", &buf, formatConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range matches[:n] {
+			if err := f.Write(m); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		var list []map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+			t.Errorf("%d matches: %v in %q", n, err, buf.String())
+		} else if len(list) != n {
+			t.Errorf("%d matches: read back %d", n, len(list))
+		}
+
+		buf.Reset()
+		f, err = NewFormatter("json", &buf, formatConfig{errors: func() []search.WalkError { return skipped }})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range matches[:n] {
+			if err := f.Write(m); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		var object struct {
+			Matches []map[string]any
+			Errors  []search.WalkError
+		}
+		if err := json.Unmarshal(buf.Bytes(), &object); err != nil {
+			t.Errorf("%d matches with errors: %v in %q", n, err, buf.String())
+			continue
+		}
+		if len(object.Matches) != n || len(object.Errors) != 1 || object.Errors[0].Errno != 13 {
+			t.Errorf("%d matches with errors: read back %+v", n, object)
+		}
+	}
+}
+
+func TestJSONLFormatterErrors(t *testing.T) {
+	var buf bytes.Buffer
+	skipped := []search.WalkError{{Path: "locked", Reason: "permission denied", Errno: 13}, {Path: "gone", Reason: "no such file or directory"}}
+	f, err := NewFormatter("jsonl", &buf, formatConfig{errors: func() []search.WalkError { return skipped }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Write(search.Match{Path: "a.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %q", len(lines), buf.String())
+	}
+	want := []string{
+		`{"type":"error","path":"locked","reason":"permission denied","errno":13}`,
+		`{"type":"error","path":"gone","reason":"no such file or directory"}`,
+	}
+	for i, line := range lines[1:] {
+		if line != want[i] {
+			t.Errorf("line %d = %s, want %s", i+2, line, want[i])
+		}
+	}
+}
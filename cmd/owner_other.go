@@ -0,0 +1,14 @@
+//go:build !unix
+
+package main
+
+import "io/fs"
+
+// ownerNames resolves the user and group owning a file, which isn't
+// supported on this platform
+type ownerNames struct{}
+
+// lookup returns placeholders as ownership isn't available
+func (o *ownerNames) lookup(info fs.FileInfo) (owner, group string) {
+	return "-", "-"
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package main
+
+import (
+	"io/fs"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// ownerNames resolves the user and group owning a file, caching lookups
+type ownerNames struct {
+	users  map[uint32]string
+	groups map[uint32]string
+}
+
+// lookup returns the owner and group names of the file, falling back to the
+// numeric ids when they have no name
+func (o *ownerNames) lookup(info fs.FileInfo) (owner, group string) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "-", "-"
+	}
+	if o.users == nil {
+		o.users = make(map[uint32]string)
+		o.groups = make(map[uint32]string)
+	}
+
+	uid, gid := uint32(stat.Uid), uint32(stat.Gid)
+	owner, ok = o.users[uid]
+	if !ok {
+		owner = strconv.FormatUint(uint64(uid), 10)
+		if u, err := user.LookupId(owner); err == nil {
+			owner = u.Username
+		}
+		o.users[uid] = owner
+	}
+	group, ok = o.groups[gid]
+	if !ok {
+		group = strconv.FormatUint(uint64(gid), 10)
+		if g, err := user.LookupGroupId(group); err == nil {
+			group = g.Name
+		}
+		o.groups[gid] = group
+	}
+	return owner, group
+}
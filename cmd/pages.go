@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// How long the results of a paged query are kept after it was last read
+const pagedQueryTTL = 5 * time.Minute
+
+// Most paged queries kept at once; starting another drops the least
+// recently read
+const maxPagedQueries = 32
+
+// Most matches a paged query keeps; its walk stops once it found as many
+const maxPagedMatches = 100_000
+
+// pagedQuery is a search whose results are kept so that clients can read
+// them a page at a time. The walk runs in the background, and pages are
+// served as soon as enough matches have been found.
+type pagedQuery struct {
+	id      string
+	roots   []string
+	pattern string
+	cancel  context.CancelFunc
+
+	mu        sync.Mutex
+	matches   []search.Match
+	done      bool
+	truncated bool // the walk stopped at maxPagedMatches
+	err       error
+	updated   chan struct{} // closed when matches are added or the walk ends
+	lastRead  time.Time
+	expiry    *time.Timer // drops the query once unread for pagedQueryTTL
+}
+
+// startPagedQuery starts the search in the background
+func startPagedQuery(searcher *search.Searcher, roots []string, pattern string) *pagedQuery {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &pagedQuery{
+		id:       newQueryID(),
+		roots:    roots,
+		pattern:  pattern,
+		cancel:   cancel,
+		updated:  make(chan struct{}),
+		lastRead: time.Now(),
+	}
+	matches, errc := searcher.Stream(ctx, roots...)
+	go func() {
+		for match := range matches {
+			q.mu.Lock()
+			if len(q.matches) == maxPagedMatches {
+				q.truncated = true
+				q.mu.Unlock()
+				cancel()
+				break
+			}
+			q.matches = append(q.matches, match)
+			q.notify()
+			q.mu.Unlock()
+		}
+		for range matches {
+			// drained until the walk has stopped
+		}
+		err := <-errc
+		q.mu.Lock()
+		if ctx.Err() == nil {
+			q.err = err
+		}
+		q.done = true
+		q.notify()
+		q.mu.Unlock()
+	}()
+	return q
+}
+
+// notify wakes the page readers waiting for more matches; q.mu is held
+func (q *pagedQuery) notify() {
+	close(q.updated)
+	q.updated = make(chan struct{})
+}
+
+// page waits until the matches from offset to offset+limit have been found,
+// or the walk is over, and returns them along with whether more may follow
+func (q *pagedQuery) page(ctx context.Context, offset, limit int) ([]search.Match, bool, error) {
+	for {
+		q.mu.Lock()
+		if len(q.matches) >= offset+limit || q.done {
+			q.lastRead = time.Now()
+			if q.expiry != nil {
+				q.expiry.Reset(pagedQueryTTL)
+			}
+			start := min(offset, len(q.matches))
+			end := min(offset+limit, len(q.matches))
+			page := append([]search.Match{}, q.matches[start:end]...)
+			more := !q.done || end < len(q.matches)
+			err := q.err
+			q.mu.Unlock()
+			return page, more, err
+		}
+		updated := q.updated
+		q.mu.Unlock()
+
+		select {
+		case <-updated:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+}
+
+// total returns the number of matches once the walk is over, and whether
+// it stopped short of finding them all
+func (q *pagedQuery) total() (int, bool, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.matches), q.done, q.truncated
+}
+
+// idle returns how long ago the query was last read
+func (q *pagedQuery) idle(now time.Time) time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return now.Sub(q.lastRead)
+}
+
+// pagedQueries holds the paged queries of the server by their handle
+type pagedQueries struct {
+	mu      sync.Mutex
+	queries map[string]*pagedQuery
+}
+
+func newPagedQueries() *pagedQueries {
+	return &pagedQueries{queries: make(map[string]*pagedQuery)}
+}
+
+// add keeps a query until it is left unread for pagedQueryTTL, first
+// dropping the least recently read one when there are too many
+func (p *pagedQueries) add(q *pagedQuery) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	var oldest *pagedQuery
+	for _, other := range p.queries {
+		if oldest == nil || other.idle(now) > oldest.idle(now) {
+			oldest = other
+		}
+	}
+	if len(p.queries) >= maxPagedQueries && oldest != nil {
+		p.drop(oldest)
+	}
+	p.queries[q.id] = q
+
+	// Abandoned queries are stopped on time, whether other requests come
+	// or not
+	q.mu.Lock()
+	q.expiry = time.AfterFunc(pagedQueryTTL, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.queries[q.id] == q {
+			p.drop(q)
+		}
+	})
+	q.mu.Unlock()
+}
+
+// drop stops a query and forgets it; p.mu is held
+func (p *pagedQueries) drop(q *pagedQuery) {
+	q.mu.Lock()
+	if q.expiry != nil {
+		q.expiry.Stop()
+	}
+	q.mu.Unlock()
+	q.cancel()
+	delete(p.queries, q.id)
+}
+
+// get returns the query with the handle, unless it expired
+func (p *pagedQueries) get(id string) (*pagedQuery, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	q, ok := p.queries[id]
+	return q, ok
+}
+
+// newQueryID returns a random handle for a paged query
+func newQueryID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
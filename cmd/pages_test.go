@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+func TestPagedQuery(t *testing.T) {
+	root := t.TempDir()
+	for i := range 10 {
+		writeFiles(t, root, fmt.Sprintf("f%d.txt", i))
+	}
+	writeFiles(t, root, "other.go")
+	searcher, err := search.New("*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := startPagedQuery(searcher, []string{root}, "*.txt")
+	defer q.cancel()
+
+	seen := map[string]bool{}
+	for offset := 0; ; offset += 4 {
+		page, more, err := q.page(context.Background(), offset, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range page {
+			if seen[m.Path] {
+				t.Errorf("%s on two pages", m.Path)
+			}
+			seen[m.Path] = true
+		}
+		if !more {
+			if len(page) != 10-offset {
+				t.Errorf("last page at %d holds %d matches", offset, len(page))
+			}
+			break
+		}
+		if len(page) != 4 {
+			t.Fatalf("page at %d holds %d matches, with more to follow", offset, len(page))
+		}
+	}
+	if len(seen) != 10 {
+		t.Errorf("pages held %d matches, want 10", len(seen))
+	}
+	if n, done, truncated := q.total(); n != 10 || !done || truncated {
+		t.Errorf("total = %d, done %v, truncated %v", n, done, truncated)
+	}
+
+	// Past the end, a page is empty
+	page, more, err := q.page(context.Background(), 50, 4)
+	if len(page) != 0 || more || err != nil {
+		t.Errorf("page past the end = %d matches, more %v, %v", len(page), more, err)
+	}
+}
+
+func TestPagedQueryWaitCancelled(t *testing.T) {
+	// A reader waiting on a walk that makes no progress gives up with its
+	// request
+	q := &pagedQuery{updated: make(chan struct{}), cancel: func() {}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := q.page(ctx, 0, 1); err != context.DeadlineExceeded {
+		t.Errorf("waiting page = %v, want the deadline", err)
+	}
+
+	// Matches arriving wake it up
+	go func() {
+		q.mu.Lock()
+		q.matches = append(q.matches, search.Match{Path: "late"})
+		q.notify()
+		q.mu.Unlock()
+	}()
+	page, more, err := q.page(context.Background(), 0, 1)
+	if err != nil || len(page) != 1 || !more {
+		t.Errorf("page = %v, more %v, %v", page, more, err)
+	}
+}
+
+// idleQuery returns a query last read idle ago, counting its cancellations
+func idleQuery(id string, idle time.Duration, cancelled *atomic.Int32) *pagedQuery {
+	return &pagedQuery{
+		id:       id,
+		updated:  make(chan struct{}),
+		lastRead: time.Now().Add(-idle),
+		cancel:   func() { cancelled.Add(1) },
+	}
+}
+
+func TestPagedQueryExpiry(t *testing.T) {
+	queries := newPagedQueries()
+	var cancelled atomic.Int32
+	q := idleQuery("q", 0, &cancelled)
+	queries.add(q)
+	if _, ok := queries.get("q"); !ok {
+		t.Fatal("query missing once added")
+	}
+
+	// Unread for its time to live, the query is dropped and its walk stopped
+	q.mu.Lock()
+	q.expiry.Reset(time.Millisecond)
+	q.mu.Unlock()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := queries.get("q"); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("query not dropped once expired")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if cancelled.Load() != 1 {
+		t.Errorf("expired query cancelled %d times, want 1", cancelled.Load())
+	}
+}
+
+func TestPagedQueriesDropLeastRecentlyRead(t *testing.T) {
+	queries := newPagedQueries()
+	var cancelled, oldestCancelled atomic.Int32
+	for i := range maxPagedQueries - 1 {
+		queries.add(idleQuery(fmt.Sprint(i), time.Duration(i)*time.Second, &cancelled))
+	}
+	queries.add(idleQuery("oldest", time.Hour, &oldestCancelled))
+	defer func() {
+		queries.mu.Lock()
+		for _, q := range queries.queries {
+			queries.drop(q)
+		}
+		queries.mu.Unlock()
+	}()
+	if cancelled.Load() != 0 || oldestCancelled.Load() != 0 {
+		t.Fatal("queries dropped before the limit")
+	}
+
+	queries.add(idleQuery("new", 0, &cancelled))
+	if _, ok := queries.get("oldest"); ok || oldestCancelled.Load() != 1 {
+		t.Errorf("least recently read query kept, cancelled %d times", oldestCancelled.Load())
+	}
+	if _, ok := queries.get("new"); !ok || cancelled.Load() != 0 {
+		t.Errorf("other queries dropped: %d cancelled", cancelled.Load())
+	}
+	if len(queries.queries) != maxPagedQueries {
+		t.Errorf("%d queries kept, want %d", len(queries.queries), maxPagedQueries)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Amount of input inspected to decide whether paths are NUL separated
+const pathSniffLen = 64 * 1024
+
+// openPathList opens the --files-from source, "-" meaning stdin
+func openPathList(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// streamPaths answers a search from a list of paths, one per line or NUL
+// separated, instead of walking the filesystem. Each path is stat-ed (its
+// target when following symlinks) and judged by the searcher as given.
+func streamPaths(ctx context.Context, run *search.Run, r io.Reader, follow bool, limit int) (<-chan search.Match, <-chan error) {
+	results := make(chan search.Match)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(results)
+
+		reader := bufio.NewReaderSize(r, pathSniffLen)
+		scanner := bufio.NewScanner(reader)
+		if head, _ := reader.Peek(pathSniffLen); bytes.IndexByte(head, 0) >= 0 {
+			scanner.Split(scanNul)
+		}
+
+		stat := os.Lstat
+		if follow {
+			stat = os.Stat
+		}
+		found := 0
+		for scanner.Scan() {
+			path := strings.TrimSuffix(scanner.Text(), "\r")
+			if path == "" {
+				continue
+			}
+			info, err := stat(path)
+			if err != nil {
+				reportSkipped(path, err)
+				continue
+			}
+			match, ok := run.Check("", path, fs.FileInfoToDirEntry(info))
+			if !ok {
+				continue
+			}
+			select {
+			case results <- match:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+			if found++; found == limit {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errc <- err
+		}
+	}()
+
+	return results, errc
+}
+
+// scanNul is a bufio.SplitFunc for NUL separated input
+func scanNul(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
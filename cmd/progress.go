@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// Progress is only shown for walks taking longer than this
+const progressDelay = time.Second
+
+// How often the progress line is redrawn
+const progressInterval = 100 * time.Millisecond
+
+// progress draws a live status line on stderr while the walk runs. Output
+// written through Writer clears the line first so the two don't mix.
+type progress struct {
+	dirs    atomic.Int64
+	current atomic.Value // string, the directory being walked
+	matches *atomic.Int64
+
+	mu    sync.Mutex
+	shown bool
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// newProgress starts reporting progress, counting matches from found
+func newProgress(found *atomic.Int64) *progress {
+	p := &progress{matches: found, done: make(chan struct{})}
+	p.current.Store("")
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+// visit records a directory entered by the walk
+func (p *progress) visit(path string) {
+	p.dirs.Add(1)
+	p.current.Store(path)
+}
+
+// run redraws the line until Stop, once the walk has taken long enough
+func (p *progress) run() {
+	defer p.wg.Done()
+	select {
+	case <-time.After(progressDelay):
+	case <-p.done:
+		return
+	}
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for {
+		p.draw()
+		select {
+		case <-ticker.C:
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// draw renders the status line, truncated to the terminal width
+func (p *progress) draw() {
+	width, _, err := terminalSize(os.Stderr)
+	if err != nil || width <= 0 {
+		width = 80
+	}
+	line := fmt.Sprintf("Searching: %d directories, %d matches  %s",
+		p.dirs.Load(), p.matches.Load(), p.current.Load().(string))
+	if utf8.RuneCountInString(line) >= width {
+		line = string([]rune(line)[:width-1])
+	}
+
+	p.mu.Lock()
+	fmt.Fprintf(os.Stderr, "\r%s\x1b[K", line)
+	p.shown = true
+	p.mu.Unlock()
+}
+
+// clear erases the status line; the caller holds mu
+func (p *progress) clear() {
+	if p.shown {
+		os.Stderr.WriteString("\r\x1b[K")
+		p.shown = false
+	}
+}
+
+// Stop ends reporting and erases the line
+func (p *progress) Stop() {
+	close(p.done)
+	p.wg.Wait()
+	p.mu.Lock()
+	p.clear()
+	p.mu.Unlock()
+}
+
+// Writer wraps w so that writes erase the status line before any output
+func (p *progress) Writer(w io.Writer) io.Writer {
+	return progressWriter{p: p, w: w}
+}
+
+// progressWriter clears the progress line before passing writes on
+type progressWriter struct {
+	p *progress
+	w io.Writer
+}
+
+func (pw progressWriter) Write(b []byte) (int, error) {
+	pw.p.mu.Lock()
+	defer pw.p.mu.Unlock()
+	pw.p.clear()
+	return pw.w.Write(b)
+}
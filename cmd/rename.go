@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// renamer computes the new path of a matched path
+type renamer func(path string) (string, error)
+
+// parseSubstitution parses a sed style s/regex/replacement/flags
+// expression applied to base names. Any character can stand in for the
+// slash; the flags are g, replacing every match instead of the first, and
+// i, ignoring case. The replacement may refer to groups as $1 or ${name}.
+func parseSubstitution(expr string) (renamer, error) {
+	if len(expr) < 2 || expr[0] != 's' {
+		return nil, fmt.Errorf("invalid --rename expression %q, expected s/old/new/", expr)
+	}
+	delim := expr[1:2]
+	parts := strings.Split(expr[2:], delim)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid --rename expression %q, expected s/old/new/", expr)
+	}
+	pattern, replacement, flags := parts[0], parts[1], parts[2]
+
+	global := false
+	for _, flag := range flags {
+		switch flag {
+		case 'g':
+			global = true
+		case 'i':
+			pattern = "(?i)" + pattern
+		default:
+			return nil, fmt.Errorf("unknown --rename flag: %c", flag)
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(path string) (string, error) {
+		dir, name := filepath.Split(path)
+		if global {
+			name = re.ReplaceAllString(name, replacement)
+		} else if loc := re.FindStringSubmatchIndex(name); loc != nil {
+			expanded := re.ExpandString(nil, replacement, name, loc)
+			name = name[:loc[0]] + string(expanded) + name[loc[1]:]
+		}
+		if name == "" {
+			return "", errors.New("the new name is empty")
+		}
+		return filepath.Join(dir, name), nil
+	}, nil
+}
+
+// Values of the placeholders of --rename-to templates
+var renamePlaceholders = map[string]func(path string) string{
+	"path": func(path string) string { return path },
+	"dir":  filepath.Dir,
+	"name": filepath.Base,
+	"stem": func(path string) string {
+		name := filepath.Base(path)
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	},
+	"ext": func(path string) string { return filepath.Ext(path) },
+}
+
+// parseRenameTemplate parses a --rename-to template such as
+// "{dir}/{stem}.bak{ext}", where {path} is the matched path, {dir} its
+// directory, {name} its base name, {stem} the name without extension and
+// {ext} the extension including the dot
+func parseRenameTemplate(template string) (renamer, error) {
+	var parts []func(path string) string
+	rest := template
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			literal := rest
+			parts = append(parts, func(string) string { return literal })
+			break
+		}
+		if open > 0 {
+			literal := rest[:open]
+			parts = append(parts, func(string) string { return literal })
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder in --rename-to template %q", template)
+		}
+		name := rest[open+1 : open+end]
+		placeholder, ok := renamePlaceholders[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown placeholder {%s} in --rename-to template", name)
+		}
+		parts = append(parts, placeholder)
+		rest = rest[open+end+1:]
+	}
+
+	return func(path string) (string, error) {
+		var buf strings.Builder
+		for _, part := range parts {
+			buf.WriteString(part(path))
+		}
+		if buf.Len() == 0 {
+			return "", errors.New("the new name is empty")
+		}
+		return filepath.Clean(buf.String()), nil
+	}, nil
+}
+
+// rename is a planned move of a match to a new path
+type rename struct {
+	from, to string
+}
+
+// RenameMatches renames every match as computed by the renamer, printing
+// each rename. Nothing is renamed if any new path is invalid, taken by an
+// existing file or shared by two matches. Deeper paths are renamed first,
+// so that renaming a directory doesn't move matches inside it before they
+// are renamed. With dryRun set the renames are only printed. It returns
+// the exit code.
+func RenameMatches(newPath renamer, matches <-chan search.Match, dryRun bool, w io.Writer) int {
+	plan, problems := planRenames(newPath, matches)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			logger.Error("cannot rename", "err", problem)
+		}
+		logger.Error("nothing was renamed")
+		return exitFailure
+	}
+
+	code := exitMatch
+	for _, r := range plan {
+		if dryRun {
+			fmt.Fprintf(w, "%s -> %s\n", r.from, r.to)
+			continue
+		}
+		if err := os.Rename(r.from, r.to); err != nil {
+			logger.Error("cannot rename", "err", err)
+			code = exitFailure
+			continue
+		}
+		fmt.Fprintf(w, "%s -> %s\n", r.from, r.to)
+	}
+	return code
+}
+
+// planRenames computes the renames of the matches, deepest first, along
+// with every reason not to carry them out
+func planRenames(newPath renamer, matches <-chan search.Match) ([]rename, []string) {
+	var plan []rename
+	var problems []string
+	targets := make(map[string]string) // new path to the match taking it
+	for match := range matches {
+		to, err := newPath(match.Path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", match.Path, err))
+			continue
+		}
+		from := filepath.Clean(match.Path)
+		if to == from {
+			continue // unchanged
+		}
+		if other, ok := targets[to]; ok {
+			problems = append(problems, fmt.Sprintf("%s and %s would both be renamed to %s", other, from, to))
+			continue
+		}
+		targets[to] = from
+		plan = append(plan, rename{from: from, to: to})
+	}
+
+	moving := make(map[string]bool, len(plan))
+	for _, r := range plan {
+		moving[r.from] = true
+	}
+	for _, r := range plan {
+		// Renaming onto another match would depend on the order of the two
+		if moving[r.to] {
+			problems = append(problems, fmt.Sprintf("%s would be renamed to %s, which is renamed too", r.from, r.to))
+			continue
+		}
+		if info, err := os.Lstat(r.to); err == nil {
+			// On case-insensitive filesystems the new name may be the file itself
+			if from, err := os.Lstat(r.from); err == nil && os.SameFile(info, from) {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s would be renamed to %s, which already exists", r.from, r.to))
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			problems = append(problems, fmt.Sprintf("%s: %v", r.to, err))
+		}
+	}
+
+	sort.SliceStable(plan, func(i, j int) bool {
+		di := strings.Count(plan[i].from, string(filepath.Separator))
+		dj := strings.Count(plan[j].from, string(filepath.Separator))
+		if di != dj {
+			return di > dj
+		}
+		return plan[i].from < plan[j].from
+	})
+	return plan, problems
+}
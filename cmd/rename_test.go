@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// matchesOf sends the paths as matches on a closed channel
+func matchesOf(paths ...string) <-chan search.Match {
+	matches := make(chan search.Match, len(paths))
+	for _, path := range paths {
+		matches <- search.Match{Path: path}
+	}
+	close(matches)
+	return matches
+}
+
+// quietLogger discards the log for the rest of the test
+func quietLogger(t *testing.T) {
+	saved := logger
+	logger = slog.New(slog.DiscardHandler)
+	t.Cleanup(func() { logger = saved })
+}
+
+func TestParseSubstitution(t *testing.T) {
+	tests := []struct {
+		expr, path string
+		want       string // empty for an error
+	}{
+		{"s/a/b/", "dir/aaa.txt", "dir/baa.txt"},
+		{"s/a/b/g", "dir/aaa.txt", "dir/bbb.txt"},
+		{"s/A/b/i", "aaa", "baa"},
+		{"s/A/b/gi", "aaa", "bbb"},
+		{`s/(\w+)\.jpeg$/$1.jpg/`, "img/cat.jpeg", "img/cat.jpg"},
+		{`s/(?P<n>\d+)/<${n}>/`, "v12", "v<12>"},
+		{"s|x|y|", "x/x", "x/y"}, // only the base name changes
+		{"s,nomatch,y,", "a.txt", "a.txt"},
+		{"s/.*//", "a.txt", ""},
+		{"s/a/b", "a", ""},
+		{"s/a/b/c/", "a", ""},
+		{"y/a/b/", "a", ""},
+		{"s/a/b/x", "a", ""},
+		{"s/(/b/", "a", ""},
+		{"s", "a", ""},
+	}
+	for _, tt := range tests {
+		rename, err := parseSubstitution(tt.expr)
+		var got string
+		if err == nil {
+			got, err = rename(filepath.FromSlash(tt.path))
+		}
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%q on %s = %s, want an error", tt.expr, tt.path, got)
+			}
+			continue
+		}
+		if err != nil || got != filepath.FromSlash(tt.want) {
+			t.Errorf("%q on %s = %s, %v, want %s", tt.expr, tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseRenameTemplate(t *testing.T) {
+	path := filepath.FromSlash("src/main.go")
+	tests := []struct {
+		template string
+		want     string // empty for an error
+	}{
+		{"{dir}/{stem}.bak{ext}", "src/main.bak.go"},
+		{"{path}.orig", "src/main.go.orig"},
+		{"out/{name}", "out/main.go"},
+		{"{dir}/../{name}", "main.go"},
+		{"plain", "plain"},
+		{"{dir}/{unknown}", ""},
+		{"{dir", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		rename, err := parseRenameTemplate(tt.template)
+		var got string
+		if err == nil {
+			got, err = rename(path)
+		}
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%q = %s, want an error", tt.template, got)
+			}
+			continue
+		}
+		if err != nil || got != filepath.FromSlash(tt.want) {
+			t.Errorf("%q = %s, %v, want %s", tt.template, got, err, tt.want)
+		}
+	}
+}
+
+func TestRenameMatches(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "old/a.txt", "old/b.txt", "keep.md")
+	dir := filepath.Join(root, "old")
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	rename, err := parseSubstitution("s/(old|txt)$/new/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A dry run prints the renames, leaving the files be
+	var out bytes.Buffer
+	if code := RenameMatches(rename, matchesOf(dir, a, b), true, &out); code != exitMatch {
+		t.Fatalf("dry run exited with %d", code)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 3 || lines[2] != dir+" -> "+filepath.Join(root, "new") {
+		t.Errorf("dry run printed %q, the directory last", out.String())
+	}
+	if _, err := os.Stat(a); err != nil {
+		t.Errorf("dry run renamed: %v", err)
+	}
+
+	// The files inside the directory are renamed before it moves
+	out.Reset()
+	if code := RenameMatches(rename, matchesOf(dir, a, b), false, &out); code != exitMatch {
+		t.Fatalf("rename exited with %d: %s", code, out.String())
+	}
+	for _, name := range []string{"new/a.new", "new/b.new", "keep.md"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Errorf("after the rename: %v", err)
+		}
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("%s still there: %v", dir, err)
+	}
+}
+
+func TestRenameMatchesCollisions(t *testing.T) {
+	quietLogger(t)
+	root := t.TempDir()
+	writeFiles(t, root, "a.txt", "b.txt", "a.md", "c.txt", "c.log")
+	in := func(name string) string { return filepath.Join(root, name) }
+	substitute := func(expr string) renamer {
+		rename, err := parseSubstitution(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rename
+	}
+	tests := []struct {
+		name   string
+		rename renamer
+		paths  []string
+	}{
+		{"existing file", substitute("s/txt/md/"), []string{in("a.txt")}},
+		{"two matches to one name", substitute("s/^[ab]/x/"), []string{in("a.txt"), in("b.txt")}},
+		{"onto another match", func(path string) (string, error) {
+			if path == in("c.log") {
+				return in("c.txt"), nil
+			}
+			return path + ".moved", nil
+		}, []string{in("c.log")}},
+		{"empty name", substitute("s/.*//"), []string{in("b.txt")}},
+	}
+	for _, tt := range tests {
+		// A single problem stops every rename, not only the one in question
+		paths := append(tt.paths, in("c.txt"))
+		var out bytes.Buffer
+		if code := RenameMatches(tt.rename, matchesOf(paths...), false, &out); code != exitFailure || out.Len() != 0 {
+			t.Errorf("%s: exited with %d, printing %q", tt.name, code, out.String())
+		}
+		for _, name := range []string{"a.txt", "b.txt", "a.md", "c.txt", "c.log"} {
+			if _, err := os.Stat(in(name)); err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync/atomic"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// replaceContent applies --replace to the matched files. A dry run prints
+// the changes as a unified diff without context, which patch can apply;
+// otherwise every rewritten file is listed. It returns the number of
+// changed lines and whether every file could be rewritten.
+func replaceContent(w io.Writer, opts *Options, files <-chan search.Match, rewrite func(string) string) (int, bool) {
+	re, err := search.NewContentPattern(opts.content, opts.caseSensitiveFor(opts.content, true))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: invalid content pattern:", err)
+		os.Exit(exitUsage)
+	}
+	replacement := search.Replacement{
+		Pattern:      re,
+		Text:         *opts.replace,
+		DryRun:       opts.isDryRun,
+		BackupSuffix: opts.backupSuffix,
+		Throttle:     opts.readThrottle,
+	}
+
+	// Files that can't be rewritten are always reported, from the workers
+	var failed atomic.Bool
+	onError := func(path string, err error) {
+		logger.Error("cannot rewrite", "path", path, "err", err)
+		failed.Store(true)
+	}
+	var edits []search.FileEdit
+	for edit := range search.ReplaceContent(files, replacement, opts.jobs, onError) {
+		edits = append(edits, edit)
+	}
+	// Files are rewritten in parallel, but listed in order
+	sort.Slice(edits, func(i, j int) bool { return edits[i].Path < edits[j].Path })
+
+	lines := 0
+	for _, edit := range edits {
+		lines += len(edit.Changes)
+		path := edit.Path
+		if rewrite != nil {
+			path = rewrite(path)
+		}
+		if !opts.isDryRun {
+			unit := "lines"
+			if len(edit.Changes) == 1 {
+				unit = "line"
+			}
+			fmt.Fprintf(w, "Replaced %d %s in %s\n", len(edit.Changes), unit, path)
+			continue
+		}
+		fmt.Fprintf(w, "--- %s\n+++ %s\n", path, path)
+		for _, change := range edit.Changes {
+			fmt.Fprintf(w, "@@ -%d +%d @@\n-%s\n+%s\n", change.Line, change.Line, change.Old, change.New)
+		}
+	}
+	if lines == 0 && !failed.Load() {
+		fmt.Fprintln(w, "No content matches the pattern")
+	}
+	return lines, !failed.Load()
+}
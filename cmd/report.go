@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Width of the longest bar of a histogram
+const histogramWidth = 40
+
+// reports are the summaries --report prints instead of the matches, by name
+var reports = map[string]func(w io.Writer) Formatter{
+	"age-histogram":  newAgeHistogram,
+	"size-histogram": newSizeHistogram,
+}
+
+// reportNames returns the names of the reports, sorted
+func reportNames() []string {
+	names := make([]string, 0, len(reports))
+	for name := range reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// histogramBucket is a row of a histogram: the files falling in a range
+type histogramBucket struct {
+	label string
+	count int
+	bytes int64
+}
+
+// histogram counts the files of a report into buckets, printing a bar of
+// each with their number and total size on Close. Directories are left out.
+type histogram struct {
+	w       io.Writer
+	title   string
+	buckets []histogramBucket
+	bucket  func(match search.Match) int // index of the bucket of a file
+}
+
+func (h *histogram) Write(match search.Match) error {
+	if match.IsDir() {
+		return nil
+	}
+	b := &h.buckets[h.bucket(match)]
+	b.count++
+	b.bytes += match.Size
+	return nil
+}
+
+func (h *histogram) Close() error {
+	most, total := 0, 0
+	labelWidth := len(h.title)
+	for _, b := range h.buckets {
+		most = max(most, b.count)
+		total += b.count
+		labelWidth = max(labelWidth, len(b.label))
+	}
+	if total == 0 {
+		_, err := fmt.Fprintln(h.w, "No path matches the pattern")
+		return err
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "%-*s  %8s  %6s\n", labelWidth, h.title, "Files", "Total")
+	for _, b := range h.buckets {
+		bar := strings.Repeat("#", (b.count*histogramWidth+most-1)/most)
+		line := fmt.Sprintf("%-*s  %8d  %6s  %s", labelWidth, b.label, b.count, humanSize(b.bytes), bar)
+		fmt.Fprintln(&out, strings.TrimRight(line, " "))
+	}
+	_, err := io.WriteString(h.w, out.String())
+	return err
+}
+
+// Upper bounds of the age buckets, the last bucket holding older files
+var ageBuckets = []struct {
+	label string
+	age   time.Duration
+}{
+	{"< 1 day", 24 * time.Hour},
+	{"< 1 week", 7 * 24 * time.Hour},
+	{"< 1 month", 30 * 24 * time.Hour},
+	{"< 1 year", 365 * 24 * time.Hour},
+}
+
+// newAgeHistogram reports the files by the time since their last
+// modification
+func newAgeHistogram(w io.Writer) Formatter {
+	h := &histogram{w: w, title: "Age"}
+	for _, b := range ageBuckets {
+		h.buckets = append(h.buckets, histogramBucket{label: b.label})
+	}
+	h.buckets = append(h.buckets, histogramBucket{label: ">= 1 year"})
+	now := time.Now()
+	h.bucket = func(match search.Match) int {
+		age := now.Sub(match.ModTime)
+		for i, b := range ageBuckets {
+			if age < b.age {
+				return i
+			}
+		}
+		return len(ageBuckets)
+	}
+	return h
+}
+
+// Upper bounds of the size buckets after the empty files, the last bucket
+// holding bigger files
+var sizeBuckets = []struct {
+	label string
+	size  int64
+}{
+	{"< 1K", 1 << 10},
+	{"< 10K", 10 << 10},
+	{"< 100K", 100 << 10},
+	{"< 1M", 1 << 20},
+	{"< 10M", 10 << 20},
+	{"< 100M", 100 << 20},
+	{"< 1G", 1 << 30},
+}
+
+// newSizeHistogram reports the files by size, by powers of ten of the
+// binary units
+func newSizeHistogram(w io.Writer) Formatter {
+	h := &histogram{w: w, title: "Size", buckets: []histogramBucket{{label: "empty"}}}
+	for _, b := range sizeBuckets {
+		h.buckets = append(h.buckets, histogramBucket{label: b.label})
+	}
+	h.buckets = append(h.buckets, histogramBucket{label: ">= 1G"})
+	h.bucket = func(match search.Match) int {
+		if match.Size == 0 {
+			return 0
+		}
+		for i, b := range sizeBuckets {
+			if match.Size < b.size {
+				return i + 1
+			}
+		}
+		return len(sizeBuckets) + 1
+	}
+	return h
+}
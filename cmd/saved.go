@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// savedSearch is a search stored by the save subcommand: the arguments of
+// the search command and the directory they were given in
+type savedSearch struct {
+	dir  string
+	args []string
+}
+
+// savedSearchDir returns the directory saved searches are stored in, next
+// to the default config file
+func savedSearchDir() (string, error) {
+	config, err := defaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(config), "searches"), nil
+}
+
+// savedSearchPath returns the file the search saved under a name is in
+func savedSearchPath(name string) (string, error) {
+	if name == "" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "-") ||
+		strings.ContainsAny(name, `/\:`) {
+		return "", fmt.Errorf("invalid search name: %s", name)
+	}
+	dir, err := savedSearchDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".toml"), nil
+}
+
+// loadSavedSearch reads a saved search, a file of dir and args keys in the
+// TOML subset of the config file
+func loadSavedSearch(path string) (*savedSearch, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	saved, err := parseSavedSearch(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return saved, nil
+}
+
+// parseSavedSearch reads the keys of a saved search
+func parseSavedSearch(r io.Reader) (*savedSearch, error) {
+	saved := &savedSearch{}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "dir":
+			saved.dir, err = parseString(value)
+		case "args":
+			saved.args, err = parseStringArray(value)
+		default:
+			return nil, fmt.Errorf("line %d: unknown key: %s", lineNo, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid value for %s: %s", lineNo, key, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(saved.args) == 0 {
+		return nil, errors.New("no args")
+	}
+	return saved, nil
+}
+
+// encode returns the saved search in the format parseSavedSearch reads
+func (saved *savedSearch) encode(name string) string {
+	quoted := make([]string, len(saved.args))
+	for i, arg := range saved.args {
+		quoted[i] = strconv.Quote(arg)
+	}
+	var b strings.Builder
+	b.WriteString("# Saved by go-search save, run with: search run " + name + "\n")
+	fmt.Fprintf(&b, "dir = %s\n", strconv.Quote(saved.dir))
+	fmt.Fprintf(&b, "args = [%s]\n", strings.Join(quoted, ", "))
+	return b.String()
+}
+
+// write stores the saved search at path, making sure it reads back as is
+func (saved *savedSearch) write(path string) error {
+	data := saved.encode(strings.TrimSuffix(filepath.Base(path), ".toml"))
+	back, err := parseSavedSearch(strings.NewReader(data))
+	if err != nil || back.dir != saved.dir || !slices.Equal(back.args, saved.args) {
+		return errors.New("the arguments cannot be saved as they would not read back the same")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(data), 0o644)
+}
+
+// runSave implements the save subcommand, storing the arguments of a
+// search under a name once they parse
+func runSave(program string, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		displaySaveHelp(program)
+		if len(args) > 0 {
+			return exitMatch
+		}
+		return exitUsage
+	}
+	name, args := args[0], args[1:]
+	force := false
+	if len(args) > 0 && args[0] == "--force" {
+		force, args = true, args[1:]
+	}
+	path, err := savedSearchPath(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: save requires the arguments of the search")
+		displaySaveHelp(program)
+		return exitUsage
+	}
+	if err := checkFlags(append([]string{program}, args...)); errors.Is(err, errHelp) {
+		fmt.Fprintln(os.Stderr, "Error: --help cannot be saved")
+		return exitUsage
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitUsage
+	}
+
+	if _, err := os.Stat(path); err == nil && !force {
+		fmt.Fprintf(os.Stderr, "Error: %s is already saved, use --force to replace it\n", name)
+		return exitFailure
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		logger.Error("cannot save the search", "err", err)
+		return exitFailure
+	}
+	if err := (&savedSearch{dir: dir, args: args}).write(path); err != nil {
+		logger.Error("cannot save the search", "path", path, "err", err)
+		return exitFailure
+	}
+	fmt.Println("Saved", path)
+	return exitMatch
+}
+
+// savedSearchArgs implements the run subcommand, returning the command line
+// of the saved search with the extra arguments appended, after changing to
+// the directory it was saved in. Without a name the saved searches are
+// listed; the exit code is returned when the line is nil.
+func savedSearchArgs(program string, args []string) ([]string, int) {
+	if len(args) == 0 {
+		return nil, listSavedSearches()
+	}
+	if args[0] == "-h" || args[0] == "--help" {
+		displayRunHelp(program)
+		return nil, exitMatch
+	}
+	path, err := savedSearchPath(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return nil, exitUsage
+	}
+	saved, err := loadSavedSearch(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, "Error: no saved search named", args[0])
+		return nil, exitUsage
+	}
+	if err != nil {
+		logger.Error("cannot read the saved search", "path", path, "err", err)
+		return nil, exitFailure
+	}
+	if saved.dir != "" {
+		if err := os.Chdir(saved.dir); err != nil {
+			logger.Error("cannot change to the saved directory", "dir", saved.dir, "err", err)
+			return nil, exitFailure
+		}
+	}
+	line := append([]string{program}, saved.args...)
+	return append(line, args[1:]...), exitMatch
+}
+
+// listSavedSearches prints the name and arguments of every saved search
+func listSavedSearches() int {
+	dir, err := savedSearchDir()
+	if err != nil {
+		logger.Error("cannot list the saved searches", "err", err)
+		return exitFailure
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Error("cannot list the saved searches", "err", err)
+		return exitFailure
+	}
+	code := exitNoMatch
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".toml")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		saved, err := loadSavedSearch(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			logger.Warn("cannot read saved search", "err", err)
+			continue
+		}
+		fmt.Printf("%s\t%s\n", name, strings.Join(saved.args, " "))
+		code = exitMatch
+	}
+	return code
+}
+
+// displaySaveHelp prints usage instructions for the save subcommand
+func displaySaveHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s save <name> [--force] <directory>... <pattern> [OPTIONS]\n", program)
+	fmt.Fprintln(os.Stderr, "Stores the arguments of a search under a name in the user config directory")
+	fmt.Fprintln(os.Stderr, "(go-search/searches), along with the current directory, for run to repeat it.")
+	fmt.Fprintln(os.Stderr, "      --force            Replace a search saved under the same name")
+}
+
+// displayRunHelp prints usage instructions for the run subcommand
+func displayRunHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s run [<name> [OPTIONS]]\n", program)
+	fmt.Fprintln(os.Stderr, "Repeats a search stored by save, from the directory it was saved in, adding")
+	fmt.Fprintln(os.Stderr, "the options given; without a name the saved searches are listed.")
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSavedSearchRoundTrip(t *testing.T) {
+	tests := [][]string{
+		{".", "*.go"},
+		{"src", "a,b", "--exclude", "#tmp"},
+		{`C:\Users\me`, `say "hi"`, `back\slash`, `\"`},
+		{"tab\there", "new\nline", "caf\u00e9", "\x00\x7f"},
+		{"bad\xffutf8", "]", "[", "'", " spaced "},
+		{""},
+	}
+	for _, args := range tests {
+		saved := &savedSearch{dir: `/home/me/my "dir" # 1`, args: args}
+		back, err := parseSavedSearch(strings.NewReader(saved.encode("name")))
+		if err != nil {
+			t.Errorf("%q: %v", args, err)
+			continue
+		}
+		if back.dir != saved.dir || !slices.Equal(back.args, saved.args) {
+			t.Errorf("%q read back as %q in %q", args, back.args, back.dir)
+		}
+	}
+}
+
+func TestParseSavedSearch(t *testing.T) {
+	tests := []struct {
+		text    string
+		dir     string
+		args    []string
+		wantErr string
+	}{
+		{"dir = \"/src\"\nargs = [\"*.go\"]\n", "/src", []string{"*.go"}, ""},
+		{"# comment\nargs = ['*.go', \"-H\"] # trailing\n", "", []string{"*.go", "-H"}, ""},
+		{"dir = '/a#b'\nargs = [\"x\"]", "/a#b", []string{"x"}, ""},
+		{"dir = \"/src\"\n", "", nil, "no args"},
+		{"args = []\n", "", nil, "no args"},
+		{"args\n", "", nil, "line 1: expected key = value"},
+		{"args = [\"x\"]\nname = \"y\"\n", "", nil, "line 2: unknown key: name"},
+		{"dir = /src\nargs = [\"x\"]", "", nil, "line 1: invalid value for dir"},
+		{"args = \"x\"", "", nil, "line 1: invalid value for args"},
+		{"args = [\"x]", "", nil, "line 1: invalid value for args"},
+	}
+	for _, tt := range tests {
+		saved, err := parseSavedSearch(strings.NewReader(tt.text))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseSavedSearch(%q) error = %v, want %q", tt.text, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSavedSearch(%q): %v", tt.text, err)
+			continue
+		}
+		if saved.dir != tt.dir || !slices.Equal(saved.args, tt.args) {
+			t.Errorf("parseSavedSearch(%q) = %q in %q, want %q in %q", tt.text, saved.args, saved.dir, tt.args, tt.dir)
+		}
+	}
+}
+
+func TestSavedSearchPath(t *testing.T) {
+	// The config directory, wherever the platform looks for it
+	dir := t.TempDir()
+	for _, env := range []string{"XDG_CONFIG_HOME", "AppData", "HOME"} {
+		t.Setenv(env, dir)
+	}
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"logs", true},
+		{"big-files_2", true},
+		{"a.b", true},
+		{"", false},
+		{".hidden", false},
+		{"-flag", false},
+		{"a/b", false},
+		{`a\b`, false},
+		{"c:x", false},
+	}
+	for _, tt := range tests {
+		path, err := savedSearchPath(tt.name)
+		if (err == nil) != tt.ok {
+			t.Errorf("savedSearchPath(%q) error = %v, want ok %v", tt.name, err, tt.ok)
+			continue
+		}
+		if err == nil && filepath.Base(path) != tt.name+".toml" {
+			t.Errorf("savedSearchPath(%q) = %s", tt.name, path)
+		}
+	}
+}
+
+func TestSavedSearchWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "searches", "tricky.toml")
+	saved := &savedSearch{dir: "/src", args: []string{".", `a "b" \c`, "# no comment"}}
+	if err := saved.write(path); err != nil {
+		t.Fatal(err)
+	}
+	back, err := loadSavedSearch(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.dir != saved.dir || !slices.Equal(back.args, saved.args) {
+		t.Errorf("loaded %q in %q, want %q in %q", back.args, back.dir, saved.args, saved.dir)
+	}
+}
+
+func TestCheckFlags(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		// Files named by the flags may not exist yet when the search is saved
+		{[]string{".", "--queries", missing}, false},
+		{[]string{".", "*.go", "--ignore-file", missing}, false},
+		{[]string{".", "*.go", "--config", missing}, false},
+		// Deletions are confirmed on the terminal the search later runs in
+		{[]string{".", "*.log", "--delete"}, false},
+		{[]string{".", "*.go", "--max-depth", "x"}, true},
+		{[]string{".", "--queries", missing, "--all"}, true},
+		{[]string{"*.go"}, true},
+	}
+	for _, tt := range tests {
+		err := checkFlags(append([]string{"search"}, tt.args...))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkFlags(%q) = %v, want error %v", tt.args, err, tt.wantErr)
+		}
+	}
+	for _, flag := range []string{"-h", "--help"} {
+		if err := checkFlags([]string{"search", ".", "*.go", flag}); !errors.Is(err, errHelp) {
+			t.Errorf("checkFlags with %s = %v, want errHelp", flag, err)
+		}
+	}
+	// The search itself still needs the files
+	if _, err := ParseFlags([]string{"search", ".", "--queries", missing}); err == nil {
+		t.Errorf("ParseFlags accepts a missing --queries file")
+	}
+}
@@ -1,150 +1,1934 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
-	"sync"
-	"syscall"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/sean1832/go-search/index"
+	"github.com/sean1832/go-search/query"
+	"github.com/sean1832/go-search/search"
+)
+
+// Exit codes of the program
+const (
+	exitMatch   = 0 // at least one match was found
+	exitNoMatch = 1 // the search completed without matches
+	exitUsage   = 2 // invalid flags, arguments or patterns
+	exitFailure = 3 // some paths couldn't be read, or another error occurred
+	exitTimeout = 4 // the search was cut short by --timeout
 )
 
+// skipped records whether any path was skipped because of an error
+var skipped atomic.Bool
+
 // Custom structure to hold flag options
 type Options struct {
-	isFileOnly      bool
-	isDirOnly       bool
+	types           search.TypeSet
 	isCaseSensitive bool
-	directory       string
+	isSmartCase     bool
+	isRegex         bool
+	isFixed         bool
+	fullPath        bool
+	directories     []string
 	pattern         string
+	patterns        []string // given with --pattern, the first being pattern
+	queries         string   // file of patterns whose matches are tagged
+	query           *query.Query
+	matchAll        bool
+	invert          bool
+	content         string
+	jobs            int
+	noIgnore        bool
+	ignoreFiles     []string
+	showHidden      bool
+	maxDepth        int
+	minDepth        int
+	excludes        []string
+	prunes          []string
+	format          string
+	print0          bool
+	exec            []string
+	isExecBatch     bool
+	sizeFilters     []search.SizeFilter
+	mtimeFilters    []search.TimeFilter
+	ctimeFilters    []search.TimeFilter
+	atimeFilters    []search.TimeFilter
+	ownerFilters    []search.OwnerFilter
+	permFilters     []search.PermFilter
+	mimeFilters     []search.MimeFilter
+	presets         []search.Preset
+	languages       []string
+	allDrives       bool
+	backend         string
+	locateDB        string
+	gitRev          string
+	customPresets   map[string]search.Preset // defined in the config file
+	follow          bool
+	breadthFirst    bool
+	normalization   search.Normalization
+	maxSymlinkDepth int
+	maxDirEntries   int
+	isFuzzy         bool
+	fuzzyThreshold  int
+	approx          int
+	sortKey         search.SortKey
+	isReverse       bool
+	color           string
+	useIndex        bool
+	noDaemon        bool
+	noDedupe        bool
+	maxResults      int
+	timeout         time.Duration
+	maxMemory       int64
+	entryThrottle   *search.Throttle // entries matched per second, with --throttle
+	readThrottle    *search.Throttle // bytes read from files per second, with --throttle
+	ioNice          bool
+	isCount         bool
+	isQuiet         bool
+	filesFrom       string
+	isAbsolute      bool
+	relativeTo      string
+	isLong          bool
+	isTree          bool
+	groupBy         string
+	report          string
+	newHash         func() hash.Hash
+	template        *template.Template
+	isHuman         bool
+	du              bool
+	logLevel        slog.Level
+	xattrFilters    []search.XattrFilter
+	withXattrs      bool
+	withACL         bool
+	withSecContext  bool
+	withErrors      bool
+	gitStates       []string        // given with --git-tracked, --git-modified and --git-untracked
+	filterCmds      []string        // given with --filter-cmd
+	filterPlugins   []string        // given with --filter-plugin
+	filters         []search.Filter // loaded from the two
+	logFormat       string
+	isInteractive   bool
+	noProgress      bool
+	noCache         bool
+	showStats       bool
+	rawPaths        bool
+	oneFileSystem   bool
+	uniqueInodes    bool
+	isOpen          bool
+	openWith        string
+	openConfirm     int
+	rename          renamer
+	isDryRun        bool
+	replace         *string // replacement of content matches, set by --replace
+	backupSuffix    string
+	delete          deleteOptions
+	transfer        transferOptions
+	archiveTo       string
+}
+
+// caseSensitiveFor reports whether a pattern is matched case-sensitively,
+// which with --smart-case depends on the pattern itself
+func (opts *Options) caseSensitiveFor(pattern string, isRegex bool) bool {
+	return opts.isCaseSensitive || opts.isSmartCase && search.HasUppercase(pattern, isRegex)
 }
 
+// errHelp is returned by ParseFlags for -h and --help, the caller printing
+// the help instead of an error
+var errHelp = errors.New("help requested")
+
 // ParseFlags parses the flags and positional arguments in any order
 func ParseFlags(args []string) (*Options, error) {
-	var opts Options
+	return parseFlagsIn("", args)
+}
+
+// parseFlagsIn parses the flags as ParseFlags does, reading the files they
+// name relative to dir rather than the working directory when it is set
+func parseFlagsIn(dir string, args []string) (*Options, error) {
+	return parseArgs(dir, args, false)
+}
+
+// checkFlags reports whether the flags parse, as ParseFlags does, for a
+// search that runs later: the config file and the files named by the flags
+// are left unread, and the terminal isn't looked at
+func checkFlags(args []string) error {
+	_, err := parseArgs("", args, true)
+	return err
+}
+
+func parseArgs(dir string, args []string, checkOnly bool) (*Options, error) {
+	opts := Options{
+		jobs:            runtime.NumCPU(),
+		logLevel:        slog.LevelWarn,
+		logFormat:       "text",
+		maxDepth:        -1,
+		approx:          -1,
+		maxSymlinkDepth: search.DefaultMaxSymlinkDepth,
+		openConfirm:     defaultOpenConfirm,
+	}
 	var positionalArgs []string
-	var program string = args[0]
-	args = args[1:]
-	for _, arg := range args {
+	args = args[1:] // past the program name
+
+	// The config file provides defaults, so it is loaded before the flags
+	if !checkOnly {
+		if cfg, err := configFromArgs(dir, args); err != nil {
+			return nil, err
+		} else if cfg != nil {
+			cfg.apply(&opts)
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		switch arg {
 		case "-f", "--file":
-			opts.isFileOnly = true
+			opts.types |= search.TypeFileKind
 		case "-d", "--dir":
-			opts.isDirOnly = true
+			opts.types |= search.TypeDirKind
+		case "--empty":
+			opts.types |= search.TypeEmpty
+		case "--broken":
+			opts.types |= search.TypeBroken
+		case "-t", "--type":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			types, err := search.ParseTypeSet(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.types |= types
 		case "-c", "--casesensitive":
 			opts.isCaseSensitive = true
+		case "-S", "--smart-case":
+			opts.isSmartCase = true
+		case "--no-smart-case":
+			opts.isSmartCase = false
+		case "-e", "--regex":
+			opts.isRegex = true
+		case "-F", "--fixed":
+			opts.isFixed = true
+		case "-p", "--full-path":
+			opts.fullPath = true
+		case "--pattern":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.patterns = append(opts.patterns, value)
+		case "--all":
+			opts.matchAll = true
+		case "-v", "--invert":
+			opts.invert = true
+		case "--queries":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.queries = value
+		case "--query":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.query, err = query.Parse(value); err != nil {
+				return nil, err
+			}
+		case "--content":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.content = value
+		case "-j", "--jobs":
+			jobs, err := intFlagValue(args, &i, 1)
+			if err != nil {
+				return nil, err
+			}
+			opts.jobs = jobs
+		case "--max-depth":
+			depth, err := intFlagValue(args, &i, 0)
+			if err != nil {
+				return nil, err
+			}
+			opts.maxDepth = depth
+		case "--min-depth":
+			depth, err := intFlagValue(args, &i, 0)
+			if err != nil {
+				return nil, err
+			}
+			opts.minDepth = depth
+		case "--max-dir-entries":
+			n, err := intFlagValue(args, &i, 1)
+			if err != nil {
+				return nil, err
+			}
+			opts.maxDirEntries = n
+		case "-x", "--exclude":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := filepath.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid exclude pattern %q: %v", value, err)
+			}
+			opts.excludes = append(opts.excludes, value)
+		case "--prune":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := filepath.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid prune pattern %q: %v", value, err)
+			}
+			opts.prunes = append(opts.prunes, value)
+		case "--format":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.format = value
+		case "-0", "--print0":
+			opts.print0 = true
+		case "--exec", "--exec-batch":
+			command, err := commandFlagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.exec = command
+			opts.isExecBatch = arg == "--exec-batch"
+		case "--size":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			filter, err := search.ParseSizeFilter(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.sizeFilters = append(opts.sizeFilters, filter)
+		case "--newer-than", "--older-than":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			at, err := search.ParseTimeSpec(value, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			opts.mtimeFilters = append(opts.mtimeFilters, search.TimeFilter{Newer: arg == "--newer-than", At: at})
+		case "--changed-within", "--changed-before":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			at, err := search.ParseTimeSpec(value, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			opts.ctimeFilters = append(opts.ctimeFilters, search.TimeFilter{Newer: arg == "--changed-within", At: at})
+		case "--accessed-within", "--accessed-before":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			at, err := search.ParseTimeSpec(value, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			opts.atimeFilters = append(opts.atimeFilters, search.TimeFilter{Newer: arg == "--accessed-within", At: at})
+		case "--perm":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			filter, err := search.ParsePermFilter(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.permFilters = append(opts.permFilters, filter)
+		case "--owner", "--group":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			parse := search.ParseOwnerFilter
+			if arg == "--group" {
+				parse = search.ParseGroupFilter
+			}
+			filter, err := parse(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.ownerFilters = append(opts.ownerFilters, filter)
+		case "--mime":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			filter, err := search.ParseMimeFilter(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.mimeFilters = append(opts.mimeFilters, filter)
+		case "--preset":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			preset, ok := opts.customPresets[value]
+			if !ok {
+				if preset, ok = search.BuiltinPreset(value); !ok {
+					return nil, fmt.Errorf("unknown preset: %s (expected %s, or one defined in the config file)",
+						value, strings.Join(search.BuiltinPresets(), ", "))
+				}
+			}
+			opts.presets = append(opts.presets, preset)
+		case "--lang":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			lang, err := search.ParseLanguage(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.languages = append(opts.languages, lang)
+		case "--db":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.locateDB = value
+		case "--backend":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if value != "walk" && value != "mft" {
+				return nil, fmt.Errorf("unknown backend: %s (expected walk or mft)", value)
+			}
+			opts.backend = value
+		case "--all-drives":
+			opts.allDrives = true
+		case "-X", "--one-file-system":
+			opts.oneFileSystem = true
+		case "--unique-inodes":
+			opts.uniqueInodes = true
+		case "--no-dedupe":
+			opts.noDedupe = true
+		case "-L", "--follow":
+			opts.follow = true
+		case "--no-follow":
+			opts.follow = false
+		case "--normalize":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.normalization, err = search.ParseNormalization(value); err != nil {
+				return nil, err
+			}
+		case "--no-normalize":
+			opts.normalization = search.NormalizeNone
+		case "--strategy":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			switch value {
+			case "dfs":
+				opts.breadthFirst = false
+			case "bfs":
+				opts.breadthFirst = true
+			default:
+				return nil, fmt.Errorf("unknown --strategy: %s (expected dfs or bfs)", value)
+			}
+		case "--max-symlink-depth":
+			depth, err := intFlagValue(args, &i, 0)
+			if err != nil {
+				return nil, err
+			}
+			opts.maxSymlinkDepth = depth
+		case "--fuzzy":
+			opts.isFuzzy = true
+		case "--fuzzy-threshold":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			threshold, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %s", arg, value)
+			}
+			opts.fuzzyThreshold = threshold
+		case "--approx":
+			distance, err := intFlagValue(args, &i, 0)
+			if err != nil {
+				return nil, err
+			}
+			opts.approx = distance
+		case "--sort":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			key, err := search.ParseSortKey(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.sortKey = key
+		case "--reverse":
+			opts.isReverse = true
+		case "--color":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.color = value
+		case "--use-index":
+			opts.useIndex = true
+		case "--no-daemon":
+			opts.noDaemon = true
+		case "--max-results":
+			n, err := intFlagValue(args, &i, 1)
+			if err != nil {
+				return nil, err
+			}
+			opts.maxResults = n
+		case "-1":
+			opts.maxResults = 1
+		case "--max-memory":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.maxMemory, err = search.ParseSize(value); err != nil || opts.maxMemory == 0 {
+				return nil, fmt.Errorf("invalid value for --max-memory: %s", value)
+			}
+		case "--throttle":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if value == "" {
+				return nil, fmt.Errorf("invalid value for --throttle: %s", value)
+			}
+			// A unit makes the rate one of bytes read rather than of entries
+			var rate int64
+			if last := value[len(value)-1]; last >= '0' && last <= '9' {
+				rate, err = strconv.ParseInt(value, 10, 64)
+				opts.entryThrottle = search.NewThrottle(rate)
+			} else {
+				rate, err = search.ParseSize(value)
+				opts.readThrottle = search.NewThrottle(rate)
+			}
+			if err != nil || rate <= 0 {
+				return nil, fmt.Errorf("invalid value for --throttle: %s", value)
+			}
+		case "--io-nice":
+			opts.ioNice = true
+		case "--timeout":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.timeout, err = time.ParseDuration(value); err != nil || opts.timeout <= 0 {
+				return nil, fmt.Errorf("invalid value for --timeout: %s (expected a duration such as 30s or 2m)", value)
+			}
+		case "--files-from":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.filesFrom = value
+		case "--absolute":
+			opts.isAbsolute = true
+		case "--relative-to":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.relativeTo = value
+		case "-l", "--long":
+			opts.isLong = true
+		case "--tree":
+			opts.isTree = true
+		case "--group-by":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if value != "dir" {
+				return nil, fmt.Errorf("unknown --group-by key: %s (expected dir)", value)
+			}
+			opts.groupBy = value
+		case "--report":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := reports[value]; !ok {
+				return nil, fmt.Errorf("unknown report: %s (expected %s)", value, strings.Join(reportNames(), " or "))
+			}
+			opts.report = value
+		case "--template":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.template, err = parseOutputTemplate(value); err != nil {
+				return nil, err
+			}
+		case "--hash":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.newHash, err = search.HashFunc(value); err != nil {
+				return nil, err
+			}
+		case "--human":
+			opts.isHuman = true
+		case "--du":
+			opts.du = true
+		case "--interactive":
+			opts.isInteractive = true
+		case "--count":
+			opts.isCount = true
+		case "-q", "--quiet":
+			opts.isQuiet = true
+		case "--no-ignore":
+			opts.noIgnore = true
+		case "--ignore":
+			opts.noIgnore = false
+		case "--ignore-file":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			value = pathIn(dir, value)
+			if !checkOnly {
+				if _, err := os.Stat(value); err != nil {
+					return nil, err
+				}
+			}
+			opts.ignoreFiles = append(opts.ignoreFiles, value)
+		case "-H", "--hidden":
+			opts.showHidden = true
+		case "--no-hidden":
+			opts.showHidden = false
+		case "--verbose":
+			opts.logLevel = min(opts.logLevel, slog.LevelInfo)
+		case "--xattr":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			filter, err := search.ParseXattrFilter(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.xattrFilters = append(opts.xattrFilters, filter)
+		case "--with-xattrs":
+			opts.withXattrs = true
+		case "--with-acl":
+			opts.withACL = true
+		case "--with-secontext":
+			opts.withSecContext = true
+		case "--with-errors":
+			opts.withErrors = true
+		case "--git-rev":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.gitRev = value
+		case "--git-tracked":
+			opts.gitStates = append(opts.gitStates, gitTracked)
+		case "--git-modified":
+			opts.gitStates = append(opts.gitStates, gitModified)
+		case "--git-untracked":
+			opts.gitStates = append(opts.gitStates, gitUntracked)
+		case "--filter-cmd":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.filterCmds = append(opts.filterCmds, value)
+		case "--filter-plugin":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.filterPlugins = append(opts.filterPlugins, value)
+		case "--log-level":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.logLevel, err = parseLogLevel(value); err != nil {
+				return nil, err
+			}
+		case "--log-format":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if value != "text" && value != "json" {
+				return nil, fmt.Errorf("unknown log format: %s (expected text or json)", value)
+			}
+			opts.logFormat = value
+		case "--no-progress":
+			opts.noProgress = true
+		case "--no-cache":
+			opts.noCache = true
+		case "--raw-paths":
+			opts.rawPaths = true
+		case "--stats":
+			opts.showStats = true
+		case "--open":
+			opts.isOpen = true
+		case "--open-with":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.isOpen = true
+			opts.openWith = value
+		case "--rename", "--rename-to":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.rename != nil {
+				return nil, fmt.Errorf("only one --rename or --rename-to can be given")
+			}
+			parse := parseSubstitution
+			if arg == "--rename-to" {
+				parse = parseRenameTemplate
+			}
+			if opts.rename, err = parse(value); err != nil {
+				return nil, err
+			}
+		case "--dry-run":
+			opts.isDryRun = true
+		case "--replace":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.replace = &value
+		case "--backup-suffix":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if value == "" {
+				return nil, fmt.Errorf("--backup-suffix cannot be empty")
+			}
+			opts.backupSuffix = value
+		case "--delete":
+			opts.delete.files = true
+		case "--delete-empty-dirs":
+			opts.delete.emptyDirs = true
+		case "--force":
+			opts.delete.force = true
+		case "--copy-to", "--move-to":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if opts.transfer.dest != "" {
+				return nil, fmt.Errorf("you cannot use both --copy-to and --move-to at the same time")
+			}
+			opts.transfer.dest, opts.transfer.move = value, args[i-1] == "--move-to"
+		case "--archive-to":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := archiveFormat(value); err != nil {
+				return nil, err
+			}
+			opts.archiveTo = value
+		case "--on-conflict":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			switch value {
+			case conflictSkip, conflictOverwrite, conflictRename:
+				opts.transfer.onConflict = value
+			default:
+				return nil, fmt.Errorf("unknown conflict policy: %s (expected skip, overwrite or rename)", value)
+			}
+		case "--config":
+			i++ // already loaded
+		case "--no-config":
 		case "-h", "--help":
-			displayHelp(program)
-			os.Exit(0)
+			return nil, errHelp
 		default:
 			// Collect positional arguments (directory and pattern)
 			positionalArgs = append(positionalArgs, arg)
 		}
 	}
 
-	if len(positionalArgs) != 2 {
+	// Paths read from a list replace the root directories
+	if opts.filesFrom == "" && len(positionalArgs) == 2 && positionalArgs[0] == "-" {
+		opts.filesFrom = "-"
+		positionalArgs = positionalArgs[1:]
+	}
+	// Every local drive is searched, along with any directory given
+	if opts.allDrives {
+		if opts.filesFrom != "" || opts.useIndex || opts.locateDB != "" || opts.gitRev != "" {
+			return nil, fmt.Errorf("--all-drives can only be used when walking directories")
+		}
+		drives, err := search.LocalDrives()
+		if err != nil {
+			return nil, fmt.Errorf("cannot use --all-drives: %w", err)
+		}
+		positionalArgs = append(drives, positionalArgs...)
+	}
+	if opts.queries != "" {
+		if opts.matchAll {
+			return nil, fmt.Errorf("you cannot use both --queries and --all at the same time")
+		}
+		// Left unread, the file stands for a single pattern
+		patterns := []string{opts.queries}
+		if !checkOnly {
+			var err error
+			if patterns, err = readQueries(pathIn(dir, opts.queries)); err != nil {
+				return nil, fmt.Errorf("cannot read --queries: %w", err)
+			}
+		}
+		opts.patterns = append(opts.patterns, patterns...)
+	}
+	if len(opts.patterns) > 0 {
+		// Every positional argument is a root directory
+		if opts.isInteractive {
+			return nil, fmt.Errorf("--interactive takes its query as an argument, not with --pattern")
+		}
+		if opts.filesFrom == "" && len(positionalArgs) == 1 && positionalArgs[0] == "-" {
+			opts.filesFrom = "-"
+			positionalArgs = nil
+		}
+		if opts.filesFrom != "" && len(positionalArgs) > 0 {
+			return nil, fmt.Errorf("--files-from takes no directories")
+		}
+		if opts.filesFrom == "" && len(positionalArgs) == 0 {
+			return nil, fmt.Errorf("invalid number of positional arguments")
+		}
+		// Append the first pattern so that it is split off below
+		positionalArgs = append(positionalArgs, opts.patterns[0])
+	}
+	if opts.matchAll && len(opts.patterns) < 2 {
+		return nil, fmt.Errorf("--all requires more than one --pattern")
+	}
+	if opts.filesFrom != "" {
+		if len(positionalArgs) != 1 {
+			return nil, fmt.Errorf("--files-from takes no directories, only a pattern")
+		}
+		if opts.useIndex {
+			return nil, fmt.Errorf("you cannot use both --files-from and --use-index at the same time")
+		}
+		if opts.locateDB != "" {
+			return nil, fmt.Errorf("you cannot use both --files-from and --db at the same time")
+		}
+	} else if opts.isInteractive && len(positionalArgs) == 1 {
+		// The pattern is only the initial query, so it may be left out
+		positionalArgs = append(positionalArgs, "")
+	} else if opts.query != nil && len(positionalArgs) == 1 {
+		// So may the pattern of a --query, which can match names itself
+		positionalArgs = append(positionalArgs, "*")
+	} else if len(positionalArgs) < 2 {
 		return nil, fmt.Errorf("invalid number of positional arguments")
 	}
 
-	opts.directory = positionalArgs[0]
-	opts.pattern = positionalArgs[1]
+	// Every positional argument but the last is a root directory
+	last := len(positionalArgs) - 1
+	opts.directories = positionalArgs[:last]
+	opts.pattern = positionalArgs[last]
+
+	if opts.isFuzzy && opts.isRegex {
+		return nil, fmt.Errorf("you cannot use both --fuzzy and --regex at the same time")
+	}
+	if opts.isFixed && (opts.isFuzzy || opts.isRegex) {
+		return nil, fmt.Errorf("you cannot use --fixed with --fuzzy or --regex")
+	}
+	if opts.approx >= 0 && (opts.isFuzzy || opts.isRegex || opts.isFixed) {
+		return nil, fmt.Errorf("you cannot use --approx with --fuzzy, --regex or --fixed")
+	}
+	if (len(opts.xattrFilters) > 0 || opts.withXattrs || opts.withACL || opts.withSecContext) && !search.XattrsSupported() {
+		return nil, fmt.Errorf("--xattr, --with-xattrs, --with-acl and --with-secontext are not supported on %s", runtime.GOOS)
+	}
+	if opts.invert && (opts.isFuzzy || opts.approx >= 0 || opts.queries != "") {
+		return nil, fmt.Errorf("you cannot use --invert with --fuzzy, --approx or --queries")
+	}
+
+	// Fuzzy and approximate results are ranked best first unless another
+	// order is requested
+	if (opts.isFuzzy || opts.approx >= 0) && opts.sortKey == "" {
+		opts.sortKey = search.SortByScore
+	}
+	if opts.isReverse && opts.sortKey == "" {
+		return nil, fmt.Errorf("--reverse requires --sort")
+	}
 
-	if opts.isFileOnly && opts.isDirOnly {
-		return nil, fmt.Errorf("you cannot use both --fileonly and --dironly at the same time")
+	// The index doesn't record who owns what
+	if opts.useIndex && len(opts.ownerFilters) > 0 {
+		return nil, fmt.Errorf("you cannot use --owner or --group with --use-index")
+	}
+	if opts.useIndex && len(opts.ctimeFilters)+len(opts.atimeFilters) > 0 {
+		return nil, fmt.Errorf("you cannot use --changed-* or --accessed-* with --use-index")
+	}
+	if opts.locateDB != "" && opts.useIndex {
+		return nil, fmt.Errorf("you cannot use both --db and --use-index at the same time")
+	}
+	// The files of a revision are in the repository, not on disk
+	if opts.gitRev != "" {
+		if opts.filesFrom != "" || opts.useIndex || opts.locateDB != "" || len(opts.gitStates) > 0 {
+			return nil, fmt.Errorf("you cannot use --git-rev with --files-from, --use-index, --db or the other --git options")
+		}
+		if opts.content != "" || opts.newHash != nil || opts.du || len(opts.mimeFilters)+len(opts.languages) > 0 ||
+			len(opts.ownerFilters)+len(opts.ctimeFilters)+len(opts.atimeFilters) > 0 ||
+			len(opts.xattrFilters) > 0 || opts.withXattrs || opts.withACL || opts.withSecContext {
+			return nil, fmt.Errorf("you cannot use --git-rev with options reading files or their metadata, " +
+				"such as --content, --hash, --du, --mime, --lang, --owner or --xattr")
+		}
+	}
+	// Hard links are only deduplicated by the walk
+	if opts.uniqueInodes && (opts.useIndex || opts.filesFrom != "" || opts.locateDB != "" || opts.gitRev != "") {
+		return nil, fmt.Errorf("--unique-inodes can only be used when walking directories")
+	}
+	// Nor are ignore files read without one
+	if len(opts.ignoreFiles) > 0 && (opts.useIndex || opts.filesFrom != "" || opts.locateDB != "" || opts.gitRev != "") {
+		return nil, fmt.Errorf("--ignore-file can only be used when walking directories")
+	}
+	if opts.maxDirEntries > 0 && (opts.useIndex || opts.filesFrom != "" || opts.locateDB != "" || opts.gitRev != "") {
+		return nil, fmt.Errorf("--max-dir-entries can only be used when walking directories")
+	}
+	if opts.backend == "mft" {
+		if opts.useIndex || opts.filesFrom != "" || opts.locateDB != "" || opts.gitRev != "" {
+			return nil, fmt.Errorf("--backend can only be used when walking directories")
+		}
+		// The table lists each volume on its own, as stored
+		if opts.follow || opts.oneFileSystem || opts.uniqueInodes || opts.maxDirEntries > 0 || len(opts.ignoreFiles) > 0 {
+			return nil, fmt.Errorf("you cannot use --backend mft with --follow, --one-file-system, --unique-inodes, " +
+				"--max-dir-entries or --ignore-file")
+		}
 	}
 
-	return &opts, nil
-}
+	if opts.maxDepth >= 0 && opts.minDepth > opts.maxDepth {
+		return nil, fmt.Errorf("--min-depth cannot be greater than --max-depth")
+	}
 
-// Search function with additional flags
-func Search(rootDir string, pattern string, isFileOnly bool, isDirOnly bool, isCaseSensitive bool) ([]string, error) {
-	var matches []string
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+	if opts.content != "" && opts.types.Kinds()&^search.TypeFileKind != 0 {
+		return nil, fmt.Errorf("--content only searches files and cannot be used with other --type kinds")
+	}
 
-	err := filepath.WalkDir(rootDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			// Handle permission errors gracefully
-			if pathErr, ok := err.(*os.PathError); ok {
-				// Check if the error is an access denied error (on Windows)
-				if errno, ok := pathErr.Err.(syscall.Errno); ok && errno == syscall.ERROR_ACCESS_DENIED {
-					// Skip the directory we don't have permission to access
-					fmt.Printf("Skipping: %s (Access Denied)\n", path)
-					return nil
+	// A template is an output format of its own
+	if opts.template != nil {
+		if opts.format != "" && opts.format != "text" || opts.print0 || opts.isLong || opts.isTree || opts.groupBy != "" {
+			return nil, fmt.Errorf("you cannot use --template with --format, --long, --tree, --group-by or --print0")
+		}
+		if opts.content != "" {
+			return nil, fmt.Errorf("you cannot use --template with --content")
+		}
+		opts.format = "template"
+	}
+
+	if opts.content != "" && opts.format != "" && opts.format != "text" {
+		return nil, fmt.Errorf("--format is not supported with --content")
+	}
+
+	if opts.exec != nil && (opts.content != "" || opts.format != "" || opts.print0) {
+		return nil, fmt.Errorf("you cannot use --exec with --content, --format or --print0")
+	}
+
+	if opts.isCount || opts.isQuiet {
+		if opts.isCount && opts.isQuiet {
+			return nil, fmt.Errorf("you cannot use both --count and --quiet at the same time")
+		}
+		if opts.exec != nil || opts.print0 || opts.format != "" && opts.format != "text" {
+			return nil, fmt.Errorf("you cannot use --count or --quiet with --exec, --format or --print0")
+		}
+	}
+
+	if opts.isOpen {
+		if opts.exec != nil || opts.content != "" || opts.isCount || opts.isQuiet ||
+			opts.format != "" || opts.print0 || opts.isLong || opts.isTree || opts.groupBy != "" || opts.isInteractive {
+			return nil, fmt.Errorf("--open only combines with options selecting what to open")
+		}
+		// The editor and the confirmation prompt need the terminal
+		if opts.filesFrom == "-" {
+			return nil, fmt.Errorf("you cannot use --open with paths read from stdin")
+		}
+	}
+
+	if opts.rename != nil {
+		if opts.exec != nil || opts.isOpen || opts.content != "" || opts.isCount || opts.isQuiet ||
+			opts.format != "" || opts.print0 || opts.isLong || opts.isTree || opts.groupBy != "" || opts.isInteractive {
+			return nil, fmt.Errorf("--rename only combines with options selecting what to rename")
+		}
+	}
+
+	if opts.delete.files || opts.delete.emptyDirs {
+		if opts.rename != nil || opts.exec != nil || opts.isOpen || opts.content != "" || opts.isCount ||
+			opts.isQuiet || opts.format != "" || opts.print0 || opts.isLong || opts.isTree || opts.groupBy != "" || opts.isInteractive {
+			return nil, fmt.Errorf("--delete only combines with options selecting what to delete")
+		}
+		for _, pattern := range append([]string{opts.pattern}, opts.patterns...) {
+			if !opts.invert && !matchesEverything(pattern, opts.isRegex, opts.isFixed) {
+				continue
+			}
+			for _, dir := range opts.directories {
+				if isFilesystemRoot(dir) {
+					return nil, fmt.Errorf("refusing to delete everything under %s", dir)
 				}
 			}
-			// Return other types of errors
-			fmt.Printf("Skipping: %s (Unhandle Error)\n", err)
-			return nil
 		}
+		// Each deletion is confirmed on the terminal unless forced
+		if !opts.delete.force && !opts.isDryRun && (opts.filesFrom == "-" || !checkOnly && !isTerminal(os.Stdin)) {
+			return nil, fmt.Errorf("--delete needs a terminal to confirm each deletion, or --force")
+		}
+		opts.delete.dryRun = opts.isDryRun
+	} else if opts.delete.force {
+		return nil, fmt.Errorf("--force requires --delete or --delete-empty-dirs")
+	}
+	if opts.transfer.dest != "" {
+		flag, verb := "--copy-to", "copy"
+		if opts.transfer.move {
+			flag, verb = "--move-to", "move"
+		}
+		if opts.rename != nil || opts.delete.files || opts.delete.emptyDirs || opts.exec != nil || opts.isOpen ||
+			opts.content != "" || opts.isCount || opts.isQuiet || opts.format != "" || opts.print0 || opts.isLong ||
+			opts.isTree || opts.groupBy != "" || opts.report != "" || opts.du || opts.isInteractive || opts.gitRev != "" {
+			return nil, fmt.Errorf("%s only combines with options selecting what to %s", flag, verb)
+		}
+		if opts.transfer.onConflict == "" {
+			opts.transfer.onConflict = conflictSkip
+		}
+		opts.transfer.dryRun = opts.isDryRun
+	} else if opts.transfer.onConflict != "" {
+		return nil, fmt.Errorf("--on-conflict requires --copy-to or --move-to")
+	}
+	if opts.archiveTo != "" {
+		if opts.transfer.dest != "" || opts.rename != nil || opts.delete.files || opts.delete.emptyDirs || opts.exec != nil ||
+			opts.isOpen || opts.content != "" || opts.isCount || opts.isQuiet || opts.format != "" || opts.print0 ||
+			opts.isLong || opts.isTree || opts.groupBy != "" || opts.report != "" || opts.du || opts.isInteractive || opts.gitRev != "" {
+			return nil, fmt.Errorf("--archive-to only combines with options selecting what to archive")
+		}
+	}
+	if opts.replace != nil {
+		if opts.content == "" {
+			return nil, fmt.Errorf("--replace requires --content")
+		}
+		if opts.exec != nil || opts.isCount || opts.isQuiet {
+			return nil, fmt.Errorf("you cannot use --replace with --exec, --count or --quiet")
+		}
+	}
+	if opts.backupSuffix != "" && opts.replace == nil {
+		return nil, fmt.Errorf("--backup-suffix requires --replace")
+	}
+	if opts.isDryRun && opts.rename == nil && !opts.delete.files && !opts.delete.emptyDirs && opts.replace == nil &&
+		opts.transfer.dest == "" && opts.archiveTo == "" {
+		return nil, fmt.Errorf("--dry-run requires --rename, --rename-to, --delete, --replace, --copy-to, --move-to or --archive-to")
+	}
+
+	if opts.showStats && opts.isQuiet {
+		return nil, fmt.Errorf("you cannot use both --stats and --quiet at the same time")
+	}
 
-		// Determine if we should skip based on file or directory flag
-		if isFileOnly && d.IsDir() {
-			return nil // Skip directories if isFileOnly is true
+	if opts.isAbsolute && opts.relativeTo != "" {
+		return nil, fmt.Errorf("you cannot use both --absolute and --relative-to at the same time")
+	}
+	// Commands are given paths they can open, which relative ones may not be
+	if opts.relativeTo != "" && (opts.exec != nil || opts.rename != nil) {
+		return nil, fmt.Errorf("you cannot use --relative-to with --exec or --rename")
+	}
+
+	// A quiet search only needs to know whether anything matches
+	if opts.isQuiet {
+		opts.sortKey = ""
+		opts.isReverse = false
+		if opts.content == "" {
+			opts.maxResults = 1
 		}
-		if isDirOnly && !d.IsDir() {
-			return nil // Skip files if isDirOnly is true
+	}
+
+	// Checksums are printed next to paths, so there must be paths to print
+	if opts.newHash != nil && (opts.content != "" || opts.exec != nil || opts.isOpen || opts.rename != nil ||
+		opts.delete.files || opts.delete.emptyDirs || opts.isCount || opts.isQuiet || opts.print0 ||
+		opts.isLong || opts.isTree || opts.groupBy != "" || opts.isInteractive) {
+		return nil, fmt.Errorf("--hash only combines with the text, json, jsonl, csv and tsv formats")
+	}
+	if opts.isInteractive && (opts.filesFrom != "" || opts.useIndex || opts.locateDB != "" || opts.gitRev != "" || opts.exec != nil ||
+		opts.content != "" || opts.format != "" || opts.print0 || opts.isLong || opts.isTree || opts.groupBy != "" ||
+		opts.isCount || opts.isQuiet || opts.showStats) {
+		return nil, fmt.Errorf("--interactive only combines with options selecting what to search")
+	}
+
+	// The long listing is a format of its own
+	if opts.isLong {
+		if opts.format != "" && opts.format != "text" || opts.print0 {
+			return nil, fmt.Errorf("you cannot use --long with --format or --print0")
+		}
+		if opts.content != "" || opts.exec != nil || opts.isCount || opts.isQuiet {
+			return nil, fmt.Errorf("you cannot use --long with --content, --exec, --count or --quiet")
+		}
+		opts.format = "long"
+	}
+	// So is the tree
+	if opts.isTree {
+		if opts.format != "" && opts.format != "text" || opts.print0 {
+			return nil, fmt.Errorf("you cannot use --tree with --format, --long or --print0")
 		}
+		if opts.content != "" || opts.exec != nil || opts.isCount || opts.isQuiet {
+			return nil, fmt.Errorf("you cannot use --tree with --content, --exec, --count or --quiet")
+		}
+		opts.format = "tree"
+	}
+	// And the grouped listing
+	if opts.groupBy != "" {
+		if opts.format != "" && opts.format != "text" || opts.print0 {
+			return nil, fmt.Errorf("you cannot use --group-by with --format, --long, --tree or --print0")
+		}
+		if opts.content != "" || opts.exec != nil || opts.isCount || opts.isQuiet {
+			return nil, fmt.Errorf("you cannot use --group-by with --content, --exec, --count or --quiet")
+		}
+		opts.format = "group"
+	}
+	// Reports replace the listing altogether
+	if opts.report != "" {
+		if opts.format != "" && opts.format != "text" || opts.print0 {
+			return nil, fmt.Errorf("you cannot use --report with --format, --long, --tree, --group-by or --print0")
+		}
+		if opts.content != "" || opts.exec != nil || opts.isCount || opts.isQuiet || opts.du {
+			return nil, fmt.Errorf("you cannot use --report with --content, --exec, --count, --quiet or --du")
+		}
+		opts.format = "report"
+	}
+	// Directory sizes are listed du style unless another format is asked for
+	if opts.du {
+		if opts.content != "" {
+			return nil, fmt.Errorf("you cannot use --du with --content")
+		}
+		if opts.format == "" && !opts.print0 {
+			opts.format = "du"
+		}
+	}
+	if opts.isHuman && !opts.isLong && !opts.du {
+		return nil, fmt.Errorf("--human requires --long or --du")
+	}
 
-		wg.Add(1)
-		go func(path string, d os.DirEntry) {
-			defer wg.Done()
+	// NUL separated output is a variant of the plain text format
+	if opts.print0 {
+		if opts.format != "" && opts.format != "text" {
+			return nil, fmt.Errorf("you cannot use --print0 with --format %s", opts.format)
+		}
+		if opts.content != "" {
+			return nil, fmt.Errorf("you cannot use --print0 with --content")
+		}
+		opts.format = "print0"
+	}
+	if opts.withErrors && opts.format != "json" && opts.format != "jsonl" {
+		return nil, fmt.Errorf("--with-errors requires --format json or jsonl")
+	}
 
-			baseName := filepath.Base(path)
+	return &opts, nil
+}
 
-			// Handle case sensitivity
-			if !isCaseSensitive {
-				baseName = strings.ToLower(baseName)
-				pattern = strings.ToLower(pattern)
-			}
+// flagValue consumes the value following the flag at args[*i]
+func flagValue(args []string, i *int) (string, error) {
+	if *i+1 >= len(args) {
+		return "", fmt.Errorf("flag %s requires a value", args[*i])
+	}
+	*i++
+	return args[*i], nil
+}
 
-			matched, err := filepath.Match(pattern, baseName)
-			if err == nil && matched {
-				mu.Lock()
-				matches = append(matches, path)
-				mu.Unlock()
-			}
-		}(path, d)
+// pathIn returns a path given relative to dir, or as is when it is
+// absolute or dir is empty
+func pathIn(dir, path string) string {
+	if dir == "" || path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
 
-		return nil
-	})
+// commandFlagValue consumes a command line following the flag at args[*i],
+// up to a lone ";" argument or the end of the arguments
+func commandFlagValue(args []string, i *int) ([]string, error) {
+	flag := args[*i]
+	var command []string
+	for *i+1 < len(args) {
+		*i++
+		if args[*i] == ";" {
+			break
+		}
+		command = append(command, args[*i])
+	}
+	if len(command) == 0 {
+		return nil, fmt.Errorf("flag %s requires a command", flag)
+	}
+	return command, nil
+}
+
+// intFlagValue consumes an integer flag value that must be at least min
+func intFlagValue(args []string, i *int, min int) (int, error) {
+	flag := args[*i]
+	value, err := flagValue(args, i)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min {
+		return 0, fmt.Errorf("invalid value for %s: %s", flag, value)
+	}
+	return n, nil
+}
 
-	wg.Wait()
-	return matches, err
+// needsInfo reports whether the output shows or sorts by the size, mtime or
+// mode of matches, so the searcher should stat each of them as it goes
+func needsInfo(opts *Options) bool {
+	if opts.sortKey == search.SortBySize || opts.sortKey == search.SortByMtime {
+		return true
+	}
+	switch opts.format {
+	case "", "text", "tree", "group", "print0":
+		return false
+	default:
+		return true
+	}
+}
+
+// newSearcher builds a library searcher from the parsed options, followed by
+// any extra options
+func newSearcher(opts *Options, extra ...search.Option) (*search.Searcher, error) {
+	options := []search.Option{
+		search.WithCaseSensitive(opts.isCaseSensitive),
+		search.WithSmartCase(opts.isSmartCase),
+		search.WithRegex(opts.isRegex),
+		search.WithFixed(opts.isFixed),
+		search.WithFullPath(opts.fullPath),
+		search.WithMatchAll(opts.matchAll),
+		search.WithTypes(opts.types),
+		search.WithJobs(opts.jobs),
+		search.WithIgnoreFiles(!opts.noIgnore),
+		search.WithIgnoreFile(opts.ignoreFiles...),
+		search.WithBreadthFirst(opts.breadthFirst),
+		search.WithNormalization(opts.normalization),
+		search.WithHidden(opts.showHidden),
+		search.WithMaxDepth(opts.maxDepth),
+		search.WithMinDepth(opts.minDepth),
+		search.WithExcludes(opts.excludes...),
+		search.WithPrunes(opts.prunes...),
+		search.WithFollowSymlinks(opts.follow),
+		search.WithMaxSymlinkDepth(opts.maxSymlinkDepth),
+		search.WithMaxDirEntries(opts.maxDirEntries),
+		search.WithRawPaths(opts.rawPaths),
+		search.WithOneFileSystem(opts.oneFileSystem),
+		search.WithUniqueInodes(opts.uniqueInodes),
+		search.WithInfo(needsInfo(opts)),
+		search.WithErrorHandler(reportSkipped),
+	}
+	if opts.entryThrottle != nil {
+		options = append(options, search.WithEntryThrottle(opts.entryThrottle))
+	}
+	if opts.readThrottle != nil {
+		options = append(options, search.WithReadThrottle(opts.readThrottle))
+	}
+	// Sorted output limits after sorting, so the whole tree must be walked
+	if opts.sortKey == "" {
+		options = append(options, search.WithMaxResults(opts.maxResults))
+	}
+	if opts.isFuzzy {
+		options = append(options, search.WithFuzzy(opts.fuzzyThreshold))
+	}
+	if opts.approx >= 0 {
+		options = append(options, search.WithApprox(opts.approx))
+	}
+	if len(opts.patterns) > 1 {
+		options = append(options, search.WithPatterns(opts.patterns[1:]...))
+	}
+	if opts.queries != "" {
+		options = append(options, search.WithPatternTags(true))
+	}
+	if opts.invert {
+		options = append(options, search.WithInvert(true))
+	}
+	for _, filter := range opts.xattrFilters {
+		options = append(options, search.WithXattrFilter(filter))
+	}
+	if opts.withXattrs {
+		options = append(options, search.WithXattrs(true))
+	}
+	if opts.withACL {
+		options = append(options, search.WithACL(true))
+	}
+	if opts.withSecContext {
+		options = append(options, search.WithSecContext(true))
+	}
+	if opts.query != nil {
+		options = append(options, search.WithFilter(opts.query))
+	}
+	for _, filter := range opts.filters {
+		options = append(options, search.WithFilter(filter))
+	}
+	for _, filter := range opts.sizeFilters {
+		options = append(options, search.WithSizeFilter(filter))
+	}
+	for _, filter := range opts.mtimeFilters {
+		options = append(options, search.WithModTimeFilter(filter))
+	}
+	for _, filter := range opts.ctimeFilters {
+		options = append(options, search.WithChangeTimeFilter(filter))
+	}
+	for _, filter := range opts.atimeFilters {
+		options = append(options, search.WithAccessTimeFilter(filter))
+	}
+	for _, filter := range opts.permFilters {
+		options = append(options, search.WithPermFilter(filter))
+	}
+	for _, filter := range opts.ownerFilters {
+		options = append(options, search.WithOwnerFilter(filter))
+	}
+	for _, filter := range opts.mimeFilters {
+		options = append(options, search.WithMimeFilter(filter))
+	}
+	for _, preset := range opts.presets {
+		options = append(options, search.WithPreset(preset))
+	}
+	for _, lang := range opts.languages {
+		options = append(options, search.WithLanguage(lang))
+	}
+	if opts.newHash != nil {
+		options = append(options, search.WithHash(opts.newHash))
+	}
+	return search.New(opts.pattern, append(options, extra...)...)
+}
+
+// reportSkipped records a path the search could not read, logging it at
+// the info level. Files locked by the system, such as the page file of a
+// Windows drive, are not counted as failures.
+func reportSkipped(path string, err error) {
+	inUse := search.IsFileInUse(err)
+	if !inUse {
+		skipped.Store(true)
+	}
+	reason := err.Error()
+	switch {
+	case inUse:
+		reason = "in use by another process"
+	case errors.Is(err, fs.ErrPermission):
+		reason = "permission denied"
+	}
+	logger.Info("skipping", "path", path, "reason", reason)
 }
 
 // displayHelp prints usage instructions
 func displayHelp(program string) {
-	fmt.Printf("Usage: %s <directory> <pattern> [OPTIONS]\n", program)
-	fmt.Println("Options:")
-	fmt.Println("  -f, --file        	 Only return files")
-	fmt.Println("  -d, --dir         	 Only return directories")
-	fmt.Println("  -c, --casesensitive    Make the search case-sensitive")
-	fmt.Println("  -h, --help        	 Display this help message")
+	fmt.Fprintf(os.Stderr, "Usage: %s <directory>... <pattern> [OPTIONS]\n", program)
+	fmt.Fprintf(os.Stderr, "       %s <directory>... --pattern <pattern>... [OPTIONS]\n", program)
+	fmt.Fprintf(os.Stderr, "       %s - <pattern> [OPTIONS] < paths\n", program)
+	fmt.Fprintf(os.Stderr, "       %s <directory>... [<query>] --interactive [OPTIONS]\n", program)
+	fmt.Fprintf(os.Stderr, "       %s index [update|watch] <directory>...\n", program)
+	fmt.Fprintf(os.Stderr, "       %s serve [--addr <host:port> | --grpc <host:port>]\n", program)
+	fmt.Fprintf(os.Stderr, "       %s daemon [--delay <duration>] [--refresh <duration>] <directory>...\n", program)
+	fmt.Fprintf(os.Stderr, "       %s dupes [--delete-interactive] <directory>...\n", program)
+	fmt.Fprintf(os.Stderr, "       %s diff [--hash <algorithm>] <old directory> <new directory>\n", program)
+	fmt.Fprintf(os.Stderr, "       %s save <name> [--force] <directory>... <pattern> [OPTIONS]\n", program)
+	fmt.Fprintf(os.Stderr, "       %s run [<name> [OPTIONS]]\n", program)
+	fmt.Fprintf(os.Stderr, "       %s config init [--config <path>] [--force]\n", program)
+	fmt.Fprintf(os.Stderr, "       %s completion <bash|zsh|fish|powershell>\n", program)
+	fmt.Fprintln(os.Stderr, "Patterns support *, ?, [classes], {a,b} and ** for any number of directories.")
+	fmt.Fprintln(os.Stderr, "Patterns containing a / are matched against the path relative to the directory.")
+	fmt.Fprintln(os.Stderr, "Options:")
+	fmt.Fprintln(os.Stderr, "  -f, --file        	 Only return files")
+	fmt.Fprintln(os.Stderr, "  -d, --dir         	 Only return directories")
+	fmt.Fprintln(os.Stderr, "  -t, --type <types>     Only return the given types, comma separated (repeatable):")
+	fmt.Fprintln(os.Stderr, "                         f file, d dir, l symlink, s socket, p pipe,")
+	fmt.Fprintln(os.Stderr, "                         x executable, e empty, b broken symlink (combined with the kinds)")
+	fmt.Fprintln(os.Stderr, "      --empty            Only return empty files and directories, same as --type empty")
+	fmt.Fprintln(os.Stderr, "      --broken           Only return broken symlinks, same as --type broken")
+	fmt.Fprintln(os.Stderr, "  -c, --casesensitive    Make the search case-sensitive")
+	fmt.Fprintln(os.Stderr, "  -S, --smart-case       Case-sensitive only if the pattern contains uppercase letters")
+	fmt.Fprintln(os.Stderr, "      --no-smart-case    Turn off smart case set in the config file")
+	fmt.Fprintln(os.Stderr, "      --normalize <nfc|nfd>")
+	fmt.Fprintln(os.Stderr, "                         Unicode form patterns and names are compared in (default: nfc)")
+	fmt.Fprintln(os.Stderr, "      --no-normalize     Compare names byte for byte, without Unicode normalization")
+	fmt.Fprintln(os.Stderr, "  -e, --regex       	 Interpret the pattern as a regular expression")
+	fmt.Fprintln(os.Stderr, "  -F, --fixed            Match names containing the pattern as a plain string")
+	fmt.Fprintln(os.Stderr, "  -p, --full-path        Match the pattern against the path relative to the directory, not the name")
+	fmt.Fprintln(os.Stderr, "      --pattern <pattern>")
+	fmt.Fprintln(os.Stderr, "                         Match this pattern (repeatable); all arguments are then directories")
+	fmt.Fprintln(os.Stderr, "      --all              Only return entries matching every --pattern, not any of them")
+	fmt.Fprintln(os.Stderr, "  -v, --invert           Return the entries not matching the pattern, other filters still applying")
+	fmt.Fprintln(os.Stderr, "      --queries <file>   Match every pattern of the file, one per line, tagging matches with them")
+	fmt.Fprintln(os.Stderr, "      --query <expr>     Only return entries matching the expression, e.g.")
+	fmt.Fprintln(os.Stderr, "                         'name:*.log AND size>10M AND NOT path:*/cache/*' (see Queries)")
+	fmt.Fprintln(os.Stderr, "      --content <regex>  Search the contents of matching files")
+	fmt.Fprintln(os.Stderr, "  -j, --jobs <N>         Number of parallel workers (default: number of CPUs)")
+	fmt.Fprintln(os.Stderr, "  -x, --exclude <glob>   Leave names matching the glob out of the results (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --prune <glob>     Don't descend into directories matching the glob (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --format <fmt>     Output format: text, json, jsonl, csv or tsv (default: text)")
+	fmt.Fprintln(os.Stderr, "  -0, --print0           Separate results with NUL bytes (for xargs -0)")
+	fmt.Fprintln(os.Stderr, "      --exec <cmd> [;]   Run a command for each match, {} is replaced by the path")
+	fmt.Fprintln(os.Stderr, "      --exec-batch <cmd> [;]")
+	fmt.Fprintln(os.Stderr, "                         Run a command once with all matches as arguments")
+	fmt.Fprintln(os.Stderr, "      --size <[+-]N[bkMG]>")
+	fmt.Fprintln(os.Stderr, "                         Only return files larger (+), smaller (-) or exactly N in size")
+	fmt.Fprintln(os.Stderr, "      --no-ignore        Don't respect .gitignore, .ignore, .searchignore and global git excludes")
+	fmt.Fprintln(os.Stderr, "      --ignore           Respect ignore files when the config file sets no_ignore")
+	fmt.Fprintln(os.Stderr, "      --ignore-file <path>")
+	fmt.Fprintln(os.Stderr, "                         Also skip paths matching the gitignore-style rules in the file,")
+	fmt.Fprintln(os.Stderr, "                         relative to each directory searched (repeatable)")
+	fmt.Fprintln(os.Stderr, "  -H, --hidden           Include hidden files and directories")
+	fmt.Fprintln(os.Stderr, "      --no-hidden        Skip hidden files and directories when the config file includes them")
+	fmt.Fprintln(os.Stderr, "      --max-depth <N>    Descend at most N directory levels below the root")
+	fmt.Fprintln(os.Stderr, "      --min-depth <N>    Only return entries at least N levels below the root")
+	fmt.Fprintln(os.Stderr, "      --max-dir-entries <N>")
+	fmt.Fprintln(os.Stderr, "                         Don't descend into directories holding more than N entries")
+	fmt.Fprintln(os.Stderr, "      --newer-than <duration|date>")
+	fmt.Fprintln(os.Stderr, "                         Only return entries modified after the time (e.g. 2d, 12h, 2024-01-01)")
+	fmt.Fprintln(os.Stderr, "      --older-than <duration|date>")
+	fmt.Fprintln(os.Stderr, "                         Only return entries modified before the time")
+	fmt.Fprintln(os.Stderr, "      --changed-within <duration|date>")
+	fmt.Fprintln(os.Stderr, "                         Only return entries whose status changed after the time (Unix only)")
+	fmt.Fprintln(os.Stderr, "      --changed-before <duration|date>")
+	fmt.Fprintln(os.Stderr, "                         Only return entries whose status last changed before the time (Unix only)")
+	fmt.Fprintln(os.Stderr, "      --accessed-within <duration|date>")
+	fmt.Fprintln(os.Stderr, "                         Only return entries accessed after the time")
+	fmt.Fprintln(os.Stderr, "      --accessed-before <duration|date>")
+	fmt.Fprintln(os.Stderr, "                         Only return entries last accessed before the time")
+	fmt.Fprintln(os.Stderr, "      --perm <[-/]mode>  Only return entries with exactly this mode, octal (0644) or")
+	fmt.Fprintln(os.Stderr, "                         symbolic (u+x), or with all (-) or any (/) of its bits")
+	fmt.Fprintln(os.Stderr, "      --owner <user>     Only return entries owned by the user, a name or uid (Unix only)")
+	fmt.Fprintln(os.Stderr, "      --group <group>    Only return entries owned by the group, a name or gid (Unix only)")
+	fmt.Fprintln(os.Stderr, "      --mime <type>      Only return files whose content is of the type, e.g. image/* or")
+	fmt.Fprintln(os.Stderr, "                         application/pdf, sniffed from their first bytes (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --preset <name>    Only return files of a kind: code, images, video, audio or docs,")
+	fmt.Fprintln(os.Stderr, "                         by extension, or a preset from the config file (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --lang <language>  Only return source files in the language, e.g. go, python or rust,")
+	fmt.Fprintln(os.Stderr, "                         by extension, shebang or modeline (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --xattr <name[=value]>")
+	fmt.Fprintln(os.Stderr, "                         Require the extended attribute, with the value if given (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --with-xattrs      Include extended attributes in the json, jsonl and template output")
+	fmt.Fprintln(os.Stderr, "      --with-acl         Include POSIX ACLs in the long, json, jsonl and template output")
+	fmt.Fprintln(os.Stderr, "      --with-secontext   Include SELinux contexts in the long, json, jsonl and template output")
+	fmt.Fprintln(os.Stderr, "      --with-errors      Include skipped paths and their errors in the json and jsonl output")
+	fmt.Fprintln(os.Stderr, "      --git-rev <rev>    Match the paths of a git revision, such as HEAD~5 or v1.0, instead of the disk")
+	fmt.Fprintln(os.Stderr, "      --git-tracked      Only return files tracked by git")
+	fmt.Fprintln(os.Stderr, "      --git-modified     Only return files changed in the git working tree or index")
+	fmt.Fprintln(os.Stderr, "      --git-untracked    Only return files untracked by git and not ignored")
+	fmt.Fprintln(os.Stderr, "      --filter-cmd <command>")
+	fmt.Fprintln(os.Stderr, "                         Only return entries the command accepts, asked line by line (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --filter-plugin <file>")
+	fmt.Fprintln(os.Stderr, "                         Only return entries the Filter of the Go plugin accepts (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --all-drives       Search every local drive, on top of any directory given (Windows only)")
+	fmt.Fprintln(os.Stderr, "  -X, --one-file-system  Don't descend into directories on other filesystems")
+	fmt.Fprintln(os.Stderr, "      --unique-inodes    Report files with several hard links only once")
+	fmt.Fprintln(os.Stderr, "      --no-dedupe        Print paths found more than once each time they are found")
+	fmt.Fprintln(os.Stderr, "  -L, --follow           Follow symbolic links")
+	fmt.Fprintln(os.Stderr, "      --no-follow        Don't follow symbolic links when the config file does")
+	fmt.Fprintln(os.Stderr, "      --strategy <dfs|bfs>")
+	fmt.Fprintln(os.Stderr, "                         Walk depth first (default) or breadth first, finding shallow matches first")
+	fmt.Fprintln(os.Stderr, "      --max-symlink-depth <N>")
+	fmt.Fprintln(os.Stderr, "                         Follow at most N nested symbolic links (default: 40)")
+	fmt.Fprintln(os.Stderr, "      --fuzzy            Fuzzy match names against the pattern, best matches first")
+	fmt.Fprintln(os.Stderr, "      --fuzzy-threshold <N>")
+	fmt.Fprintln(os.Stderr, "                         Minimum fuzzy score a name must reach (default: 0)")
+	fmt.Fprintln(os.Stderr, "      --approx <N>       Match names within N typos (edit distance) of the pattern, closest first")
+	fmt.Fprintln(os.Stderr, "      --sort <key>       Sort results by name, size, mtime or depth")
+	fmt.Fprintln(os.Stderr, "      --reverse          Reverse the sort order")
+	fmt.Fprintln(os.Stderr, "      --max-memory <N[kMG]>")
+	fmt.Fprintln(os.Stderr, "                         Sort at most this much in memory, merging through temporary files beyond it")
+	fmt.Fprintln(os.Stderr, "      --color <when>     Color output: auto, always or never (default: auto)")
+	fmt.Fprintln(os.Stderr, "      --use-index        Answer from the index built by the index subcommand")
+	fmt.Fprintln(os.Stderr, "      --db <path>        Answer from an mlocate database written by updatedb,")
+	fmt.Fprintln(os.Stderr, "                         e.g. /var/lib/mlocate/mlocate.db")
+	fmt.Fprintln(os.Stderr, "      --backend <walk|mft>")
+	fmt.Fprintln(os.Stderr, "                         Read NTFS volumes from their Master File Table, falling back to")
+	fmt.Fprintln(os.Stderr, "                         walking without administrator rights (default: walk, Windows only)")
+	fmt.Fprintln(os.Stderr, "      --no-daemon        Search on its own even when a daemon indexes the directories")
+	fmt.Fprintln(os.Stderr, "      --max-results <N>  Stop after N matches (after sorting, with --sort)")
+	fmt.Fprintln(os.Stderr, "  -1                     Stop after the first match, same as --max-results 1")
+	fmt.Fprintln(os.Stderr, "      --throttle <N|N[kMG]>")
+	fmt.Fprintln(os.Stderr, "                         Match at most N entries a second, or with a unit read at most")
+	fmt.Fprintln(os.Stderr, "                         that many bytes a second for --hash and --content (repeatable)")
+	fmt.Fprintln(os.Stderr, "      --io-nice          Run with the lowest CPU and I/O priority, for background scans")
+	fmt.Fprintln(os.Stderr, "      --timeout <duration>")
+	fmt.Fprintln(os.Stderr, "                         Stop searching after the duration (e.g. 30s), keeping what was found")
+	fmt.Fprintln(os.Stderr, "      --files-from <file>")
+	fmt.Fprintln(os.Stderr, "                         Match the paths listed in the file (- for stdin, or pass -")
+	fmt.Fprintln(os.Stderr, "                         as the directory) instead of walking, one per line or NUL separated")
+	fmt.Fprintln(os.Stderr, "      --absolute         Print absolute, cleaned paths")
+	fmt.Fprintln(os.Stderr, "      --relative-to <dir>")
+	fmt.Fprintln(os.Stderr, "                         Print paths relative to the directory")
+	fmt.Fprintln(os.Stderr, "      --raw-paths        Print the \\\\?\\ extended-length paths used on Windows as is")
+	fmt.Fprintln(os.Stderr, "  -l, --long             Print permissions, owner, group, size and mtime of each match")
+	fmt.Fprintln(os.Stderr, "      --human            Print sizes with K, M and G units in the long or du listing")
+	fmt.Fprintln(os.Stderr, "      --du               Print the total size of the files below each matched directory")
+	fmt.Fprintln(os.Stderr, "      --tree             Print the matches as a tree below each directory")
+	fmt.Fprintln(os.Stderr, "      --group-by <key>   Print each directory once, followed by its matches indented (key: dir)")
+	fmt.Fprintln(os.Stderr, "      --report <name>    Print a summary of the matched files instead of them:")
+	fmt.Fprintln(os.Stderr, "                         age-histogram or size-histogram")
+	fmt.Fprintln(os.Stderr, "      --template <tmpl>  Print each match with a Go text/template over its fields,")
+	fmt.Fprintln(os.Stderr, "                         e.g. '{{.Path}}\\t{{.Size}}\\t{{.ModTime.Format \"2006-01-02\"}}'")
+	fmt.Fprintln(os.Stderr, "      --hash <algorithm> Print the sha256, md5 or xxh64 checksum next to each matched file")
+	fmt.Fprintln(os.Stderr, "      --interactive      Narrow the results by typing, Enter prints the selected path")
+	fmt.Fprintln(os.Stderr, "      --open             Open the matches in $VISUAL or $EDITOR, asking first for more than 10")
+	fmt.Fprintln(os.Stderr, "                         (open_confirm in the config file)")
+	fmt.Fprintln(os.Stderr, "      --open-with <cmd>  Open the matches with this command, {} is replaced by the paths")
+	fmt.Fprintln(os.Stderr, "      --rename <s/old/new/[gi]>")
+	fmt.Fprintln(os.Stderr, "                         Rename matches by a regex substitution on their names")
+	fmt.Fprintln(os.Stderr, "      --rename-to <template>")
+	fmt.Fprintln(os.Stderr, "                         Rename matches to the template, e.g. {dir}/{stem}.bak{ext}")
+	fmt.Fprintln(os.Stderr, "      --delete           Delete the matched files, asking before each one")
+	fmt.Fprintln(os.Stderr, "      --delete-empty-dirs")
+	fmt.Fprintln(os.Stderr, "                         Delete the matched directories that are empty, deepest first")
+	fmt.Fprintln(os.Stderr, "      --force            Delete without asking")
+	fmt.Fprintln(os.Stderr, "      --copy-to <dir>    Copy the matched files below the directory, keeping their relative paths")
+	fmt.Fprintln(os.Stderr, "      --move-to <dir>    Move the matched files below the directory, keeping their relative paths")
+	fmt.Fprintln(os.Stderr, "      --on-conflict <policy>")
+	fmt.Fprintln(os.Stderr, "                         What --copy-to and --move-to do with existing files: skip (default),")
+	fmt.Fprintln(os.Stderr, "                         overwrite, or rename to name-1.ext")
+	fmt.Fprintln(os.Stderr, "      --archive-to <file>")
+	fmt.Fprintln(os.Stderr, "                         Pack the matched files into a .tar, .tar.gz or .zip archive,")
+	fmt.Fprintln(os.Stderr, "                         keeping their relative paths and mtimes")
+	fmt.Fprintln(os.Stderr, "      --replace <text>   Replace the --content matches in the files, $1 or ${name} being groups")
+	fmt.Fprintln(os.Stderr, "      --backup-suffix <suffix>")
+	fmt.Fprintln(os.Stderr, "                         Keep the original of each file changed by --replace, e.g. .orig")
+	fmt.Fprintln(os.Stderr, "      --dry-run          Print what --rename, --delete, --copy-to, --move-to or --archive-to")
+	fmt.Fprintln(os.Stderr, "                         would do, or the diff of --replace, without doing it")
+	fmt.Fprintln(os.Stderr, "      --count            Only print the number of matches")
+	fmt.Fprintln(os.Stderr, "  -q, --quiet            Print nothing, exit with 0 if anything matches and 1 otherwise")
+	fmt.Fprintln(os.Stderr, "      --verbose          Report paths skipped because they couldn't be read on stderr")
+	fmt.Fprintln(os.Stderr, "      --log-level <level>")
+	fmt.Fprintln(os.Stderr, "                         Log diagnostics on stderr from this level on: debug, info")
+	fmt.Fprintln(os.Stderr, "                         (skipped paths, as with --verbose), warn (default) or error")
+	fmt.Fprintln(os.Stderr, "      --log-format <format>")
+	fmt.Fprintln(os.Stderr, "                         Format of the diagnostics: text (default) or json")
+	fmt.Fprintln(os.Stderr, "      --stats            Print a summary of the work done on stderr when finished")
+	fmt.Fprintln(os.Stderr, "      --no-progress      Don't show a progress line on stderr during long searches")
+	fmt.Fprintln(os.Stderr, "      --no-cache         Don't reuse or save directory listings cached by recent searches")
+	fmt.Fprintln(os.Stderr, "      --config <path>    Read defaults from this config file")
+	fmt.Fprintln(os.Stderr, "      --no-config        Don't read the config file")
+	fmt.Fprintln(os.Stderr, "  -h, --help        	 Display this help message")
+	fmt.Fprintln(os.Stderr, "Exit status: 0 if anything matched, 1 if nothing did, 2 on usage errors,")
+	fmt.Fprintln(os.Stderr, "3 if some paths couldn't be read or an --exec command failed, 4 if --timeout was hit.")
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "index":
+			os.Exit(runIndex(os.Args[0], os.Args[2:]))
+		case "serve":
+			os.Exit(runServe(os.Args[0], os.Args[2:]))
+		case "daemon":
+			os.Exit(runDaemon(os.Args[0], os.Args[2:]))
+		case "dupes":
+			os.Exit(runDupes(os.Args[0], os.Args[2:]))
+		case "diff":
+			os.Exit(runDiff(os.Args[0], os.Args[2:]))
+		case "config":
+			os.Exit(runConfig(os.Args[0], os.Args[2:]))
+		case "completion":
+			os.Exit(runCompletion(os.Args[0], os.Args[2:]))
+		case "save":
+			os.Exit(runSave(os.Args[0], os.Args[2:]))
+		case "run":
+			// The saved search then runs as if typed
+			args, code := savedSearchArgs(os.Args[0], os.Args[2:])
+			if args == nil {
+				os.Exit(code)
+			}
+			os.Args = args
+		}
+	}
+
 	// Parse the flags and positional arguments manually
 	opts, err := ParseFlags(os.Args)
+	if errors.Is(err, errHelp) {
+		displayHelp(os.Args[0])
+		os.Exit(exitMatch)
+	}
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		displayHelp(os.Args[0])
-		os.Exit(1)
+		os.Exit(exitUsage)
+	}
+	logger = slog.New(newLogHandler(os.Stderr, opts.logFormat, opts.logLevel))
+	if opts.ioNice {
+		if err := lowerPriority(); err != nil {
+			logger.Warn("cannot lower the priority", "err", err)
+		}
 	}
 
-	// Search for files or directories based on flags
-	matches, err := Search(opts.directory, opts.pattern, opts.isFileOnly, opts.isDirOnly, opts.isCaseSensitive)
+	// Custom filters are loaded once, interactive searches reusing them
+	filters, stopFilters, err := loadFilters(opts)
 	if err != nil {
-		fmt.Println("Error during file search:", err)
-		return
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitUsage)
+	}
+	opts.filters = filters
+
+	if opts.isInteractive {
+		os.Exit(runInteractive(opts))
+	}
+
+	// Content search only ever looks inside files
+	if opts.content != "" {
+		opts.types |= search.TypeFileKind
 	}
 
-	// Output the results
-	if len(matches) == 0 {
-		fmt.Println("No path matches the pattern")
+	// Count what is found to decide between the match and no match codes
+	var found atomic.Int64
+	start := time.Now()
+
+	// Long walks show a progress line on the terminal, cleared before any
+	// output; commands run by --exec write to the terminal directly
+	var out io.Writer = os.Stdout
+	var extra []search.Option
+	var status *progress
+	if !opts.noProgress && opts.exec == nil && !opts.isOpen && !opts.isQuiet && !opts.delete.files &&
+		!opts.delete.emptyDirs && isTerminal(os.Stderr) {
+		status = newProgress(&found)
+		out = status.Writer(os.Stdout)
+		logger = slog.New(newLogHandler(status.Writer(os.Stderr), opts.logFormat, opts.logLevel))
+		extra = append(extra, search.WithDirHandler(status.visit))
+	}
+	// Listings of recently walked directories are reused between runs
+	var listings *index.Listings
+	if !opts.noCache && opts.filesFrom == "" && !opts.useIndex && opts.locateDB == "" && opts.gitRev == "" {
+		listings = index.LoadListings(opts.directories)
+		extra = append(extra, search.WithListingCache(listings))
+	}
+
+	searcher, err := newSearcher(opts, extra...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: invalid pattern:", err)
+		os.Exit(exitUsage)
+	}
+
+	// Statistics and skipped paths are those of this search alone
+	run := searcher.NewRun()
+
+	// Nothing of an inverted match is highlighted
+	var spanner search.Spanner
+	if !opts.invert {
+		spanner, _ = searcher.Matcher().(search.Spanner)
+	}
+	colors, err := newColorizer(opts.color, spanner)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitUsage)
+	}
+
+	// Skipped paths are only listed with the matches on request
+	var skippedPaths func() []search.WalkError
+	if opts.withErrors {
+		skippedPaths = run.Errors
+	}
+	formatter, err := NewFormatter(opts.format, out, formatConfig{
+		colors:     colors,
+		humanSizes: opts.isHuman,
+		acl:        opts.withACL,
+		secContext: opts.withSecContext,
+		roots:      opts.directories,
+		keepOrder:  opts.sortKey != "",
+		hashes:     opts.newHash != nil,
+		template:   opts.template,
+		errors:     skippedPaths,
+		report:     opts.report,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitUsage)
+	}
+
+	// Search for files or directories based on flags, printing as we go
+	var matches <-chan search.Match
+	var errc <-chan error
+	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+	limit := opts.maxResults
+	if opts.sortKey != "" {
+		limit = 0
+	}
+	// A running daemon answers from its indexes in memory
+	fromDaemon := false
+	if usesDaemon(opts) {
+		matches, errc, fromDaemon = streamFromDaemon(ctx, os.Args[1:])
+	}
+	if fromDaemon {
+		// Matches come from the daemon
+	} else if opts.filesFrom != "" {
+		list, err := openPathList(opts.filesFrom)
+		if err != nil {
+			logger.Error("cannot read --files-from", "err", err)
+			os.Exit(exitFailure)
+		}
+		defer list.Close()
+		matches, errc = streamPaths(ctx, run, list, opts.follow, limit)
+	} else if opts.useIndex {
+		matches, errc = streamIndexed(ctx, run, opts.directories, limit)
+	} else if opts.locateDB != "" {
+		matches, errc = streamLocateDB(ctx, run, opts.locateDB, opts.directories, limit)
+	} else if opts.gitRev != "" {
+		matches, errc = streamGitRev(ctx, run, opts.gitRev, opts.directories, limit)
+	} else if opts.backend == "mft" {
+		matches, errc = streamMFT(ctx, run, opts.directories, limit)
+	} else {
+		matches, errc = run.Stream(ctx, opts.directories...)
+	}
+
+	rewrite, err := newPathRewriter(opts.isAbsolute, opts.relativeTo)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitUsage)
+	}
+
+	// Directory sizes are summed before paths are rewritten, while they
+	// still lead to the directories
+	if opts.du {
+		matches = search.DiskUsage(matches, opts.jobs, reportSkipped)
+	}
+	// Roots overlapping through a symbolic link, or a list naming a path
+	// twice, would otherwise print the same match more than once
+	if !opts.noDedupe && (len(opts.directories) > 1 || opts.filesFrom != "") {
+		matches = deduped(matches)
+	}
+	matches = counted(matches, &found)
+	failed := false
+	if rewrite != nil && opts.content == "" {
+		matches = rewritePaths(matches, rewrite)
+	}
+	if opts.exec != nil {
+		code := 0
+		if opts.isExecBatch {
+			code = ExecBatch(opts.exec, matches)
+		} else {
+			code = ExecEach(opts.exec, matches, opts.jobs)
+		}
+		failed = code != 0
+	} else if opts.delete.files || opts.delete.emptyDirs {
+		failed = DeleteMatches(matches, opts.delete, out) != 0
+	} else if opts.rename != nil {
+		failed = RenameMatches(opts.rename, matches, opts.isDryRun, out) != 0
+	} else if opts.transfer.dest != "" {
+		failed = TransferMatches(matches, opts.transfer, out) != 0
+	} else if opts.archiveTo != "" {
+		failed = ArchiveMatches(matches, opts.archiveTo, opts.isDryRun, out) != 0
+	} else if opts.isOpen {
+		if opts.sortKey != "" {
+			matches = sorted(matches, opts.sortKey, opts.isReverse, opts.maxResults, opts.maxMemory)
+		}
+		failed = OpenMatches(editorCommand(opts.openWith), matches, opts.openConfirm) != 0
+	} else if opts.replace != nil {
+		lines, ok := replaceContent(out, opts, matches, rewrite)
+		failed = !ok
+		found.Store(int64(lines))
+	} else if opts.content != "" {
+		// Content searches succeed on matching lines, not matching files
+		lines := searchContent(out, opts, matches, rewrite)
+		if opts.isCount {
+			fmt.Fprintln(out, lines)
+		}
+		found.Store(int64(lines))
+	} else if opts.isCount || opts.isQuiet {
+		for range matches {
+		}
+		if opts.isCount {
+			fmt.Fprintln(out, found.Load())
+		}
 	} else {
-		fmt.Println("Found Paths:")
-		for _, match := range matches {
-			fmt.Println(match)
+		if opts.sortKey != "" {
+			matches = sorted(matches, opts.sortKey, opts.isReverse, opts.maxResults, opts.maxMemory)
+		}
+		for match := range matches {
+			if err := formatter.Write(match); err != nil {
+				logger.Error("cannot write output", "err", err)
+				os.Exit(exitFailure)
+			}
+		}
+		if err := formatter.Close(); err != nil {
+			logger.Error("cannot write output", "err", err)
+			os.Exit(exitFailure)
 		}
 	}
+
+	err = <-errc
+	if status != nil {
+		status.Stop()
+	}
+	if listings != nil {
+		if err := listings.Save(); err != nil {
+			logger.Info("could not save the listing cache", "err", err)
+		}
+	}
+	if opts.showStats {
+		printStats(os.Stderr, run.Stats(), found.Load(), time.Since(start), opts.jobs)
+	}
+	stopFilters()
+	// Hitting the timeout isn't an error, the matches found so far stand
+	timedOut := errors.Is(err, context.DeadlineExceeded)
+	if timedOut {
+		logger.Warn("the search timed out, the results are incomplete", "timeout", opts.timeout)
+	} else if err != nil {
+		failed = true
+		if errors.Is(err, index.ErrNotFound) {
+			logger.Error("search failed", "err", err, "hint", fmt.Sprintf("build an index first with: %s index <directory>", os.Args[0]))
+		} else {
+			logger.Error("search failed", "err", err)
+		}
+	}
+	switch {
+	case timedOut:
+		os.Exit(exitTimeout)
+	case failed || skipped.Load():
+		os.Exit(exitFailure)
+	case found.Load() == 0:
+		os.Exit(exitNoMatch)
+	}
+	os.Exit(exitMatch)
+}
+
+// counted passes matches through, counting them in n
+func counted(matches <-chan search.Match, n *atomic.Int64) <-chan search.Match {
+	out := make(chan search.Match)
+	go func() {
+		defer close(out)
+		for match := range matches {
+			n.Add(1)
+			out <- match
+		}
+	}()
+	return out
+}
+
+// deduped passes matches through, dropping those whose path was already
+// seen. Paths are compared absolute, with the symbolic links in their root
+// resolved, and case-insensitively on Windows.
+func deduped(matches <-chan search.Match) <-chan search.Match {
+	out := make(chan search.Match)
+	go func() {
+		defer close(out)
+		seen := make(map[string]bool)
+		roots := make(map[string]string) // each root, resolved
+		for match := range matches {
+			key := dedupeKey(match, roots)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			out <- match
+		}
+	}()
+	return out
+}
+
+// dedupeKey returns the normalized absolute path of a match
+func dedupeKey(match search.Match, roots map[string]string) string {
+	key := match.Path
+	if rel, err := filepath.Rel(match.Root, match.Path); match.Root != "" && err == nil {
+		root, ok := roots[match.Root]
+		if !ok {
+			root = match.Root
+			if abs, err := filepath.Abs(root); err == nil {
+				root = abs
+			}
+			if resolved, err := filepath.EvalSymlinks(root); err == nil {
+				root = resolved
+			}
+			roots[match.Root] = root
+		}
+		key = filepath.Join(root, rel)
+	} else if abs, err := filepath.Abs(key); err == nil {
+		key = abs
+	}
+	if runtime.GOOS == "windows" {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// sorted collects every match and replays them in the requested order,
+// keeping only the first limit matches when limit is positive. Past budget
+// bytes, when it is positive, the matches collected go to temporary files.
+func sorted(matches <-chan search.Match, key search.SortKey, reverse bool, limit int, budget int64) <-chan search.Match {
+	sorter := search.NewMatchSorter(key, reverse, budget)
+	for match := range matches {
+		if err := sorter.Add(match); err != nil {
+			sorter.Close()
+			logger.Error("cannot sort results", "err", err)
+			os.Exit(exitFailure)
+		}
+	}
+
+	sorted := make(chan search.Match)
+	go func() {
+		defer close(sorted)
+		defer sorter.Close()
+		n := 0
+		err := sorter.Each(func(match search.Match) bool {
+			if limit > 0 && n == limit {
+				return false
+			}
+			n++
+			sorted <- match
+			return true
+		})
+		if err != nil {
+			sorter.Close()
+			logger.Error("cannot sort results", "err", err)
+			os.Exit(exitFailure)
+		}
+	}()
+	return sorted
+}
+
+// searchContent greps the matched files and prints each matching line,
+// unless only counting, returning the number of matching lines. Printed
+// paths go through rewrite when it isn't nil.
+func searchContent(w io.Writer, opts *Options, files <-chan search.Match, rewrite func(string) string) int {
+	re, err := search.NewContentPattern(opts.content, opts.caseSensitiveFor(opts.content, true))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: invalid content pattern:", err)
+		os.Exit(exitUsage)
+	}
+
+	found := 0
+	for match := range search.ScanContentThrottled(files, re, opts.jobs, opts.readThrottle, reportSkipped) {
+		found++
+		if !opts.isCount && !opts.isQuiet {
+			path := match.Path
+			if rewrite != nil {
+				path = rewrite(path)
+			}
+			fmt.Fprintf(w, "%s:%d:%s\n", path, match.Line, match.Text)
+		}
+	}
+	if found == 0 && !opts.isCount && !opts.isQuiet {
+		fmt.Fprintln(w, "No content matches the pattern")
+	}
+	return found
 }
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Address the server listens on unless --addr is given
+const defaultServeAddr = "127.0.0.1:8080"
+
+// searchResponse is the JSON body returned by GET /search
+type searchResponse struct {
+	Roots   []string       `json:"roots"`
+	Pattern string         `json:"pattern"`
+	Count   int            `json:"count"`
+	Matches []search.Match `json:"matches"`
+}
+
+// pageResponse is the JSON body returned for a page of a paged query
+type pageResponse struct {
+	searchResponse
+	Query     string `json:"query"`               // handle to read further pages with
+	Offset    int    `json:"offset"`              // index of the first match of the page
+	More      bool   `json:"more"`                // whether matches may follow the page
+	Total     *int   `json:"total,omitempty"`     // number of matches, once the walk is over
+	Truncated bool   `json:"truncated,omitempty"` // whether the walk stopped at the most matches kept
+}
+
+// Page size of a paged query read without a limit
+const defaultPageLimit = 1000
+
+// errorResponse is the JSON body returned when a request fails
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// runServe implements the serve subcommand
+func runServe(program string, args []string) int {
+	addr := defaultServeAddr
+	grpcAddr := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--addr":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+			addr = value
+		case "--grpc":
+			value, err := flagValue(args, &i)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return exitUsage
+			}
+			grpcAddr = value
+		case "-h", "--help":
+			displayServeHelp(program)
+			return 0
+		default:
+			fmt.Fprintln(os.Stderr, "Error: unknown argument:", args[i])
+			displayServeHelp(program)
+			return exitUsage
+		}
+	}
+
+	queries := newPagedQueries()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		handleSearch(w, r, queries)
+	})
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// gRPC runs over HTTP/2, here without TLS
+	if grpcAddr != "" {
+		server.Addr = grpcAddr
+		server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handleGRPC(w, r, queries)
+		})
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetUnencryptedHTTP2(true)
+		fmt.Printf("Serving gRPC on %s\n", grpcAddr)
+	} else {
+		fmt.Printf("Listening on http://%s\n", addr)
+	}
+	if err := server.ListenAndServe(); err != nil {
+		logger.Error("server failed", "err", err)
+		return exitFailure
+	}
+	return 0
+}
+
+// handleSearch runs a search described by the query string:
+//
+//	root     directory to search, repeatable (required)
+//	pattern  glob pattern, regex when regex=true, plain string when fixed=true (required)
+//	type     comma separated types, as for --type (f,d,l,s,p,x,e,b)
+//	regex, fixed, casesensitive, smartcase, follow, hidden, noignore   booleans
+//	maxdepth, mindepth                        integers
+//	exclude  glob to leave out of the results, repeatable
+//	prune    glob of directories not to descend into, repeatable
+//	limit, offset  page size and start, which make it a paged query
+//	query    handle of a paged query to read another page of, instead of the above
+func handleSearch(w http.ResponseWriter, r *http.Request, queries *pagedQueries) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "only GET is supported"})
+		return
+	}
+
+	query := r.URL.Query()
+	offset, err := pageParam(query, "offset", 0)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	limit, err := pageParam(query, "limit", defaultPageLimit)
+	if err != nil || limit == 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid value for limit: " + query.Get("limit")})
+		return
+	}
+	if id := query.Get("query"); id != "" {
+		q, ok := queries.get(id)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, errorResponse{Error: "unknown or expired query: " + id})
+			return
+		}
+		writePage(w, r, q, offset, limit)
+		return
+	}
+
+	roots := query["root"]
+	pattern := query.Get("pattern")
+	if len(roots) == 0 || pattern == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "root and pattern are required"})
+		return
+	}
+
+	options, err := queryOptions(query)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	searcher, err := search.New(pattern, options...)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	// Paged queries keep their results for the following pages
+	if query.Has("limit") || query.Has("offset") {
+		q := startPagedQuery(searcher, roots, pattern)
+		queries.add(q)
+		writePage(w, r, q, offset, limit)
+		return
+	}
+
+	matches, err := searcher.Search(r.Context(), roots...)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if matches == nil {
+		matches = []search.Match{}
+	}
+	writeJSON(w, http.StatusOK, searchResponse{
+		Roots:   roots,
+		Pattern: pattern,
+		Count:   len(matches),
+		Matches: matches,
+	})
+}
+
+// writePage sends a page of a paged query, once its matches are found
+func writePage(w http.ResponseWriter, r *http.Request, q *pagedQuery, offset, limit int) {
+	matches, more, err := q.page(r.Context(), offset, limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := pageResponse{
+		searchResponse: searchResponse{
+			Roots:   q.roots,
+			Pattern: q.pattern,
+			Count:   len(matches),
+			Matches: matches,
+		},
+		Query:  q.id,
+		Offset: offset,
+		More:   more,
+	}
+	if total, done, truncated := q.total(); done {
+		response.Total, response.Truncated = &total, truncated
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// pageParam reads a non-negative integer parameter of a paged query
+func pageParam(query url.Values, key string, fallback int) (int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid value for %s: %s", key, value)
+	}
+	return n, nil
+}
+
+// queryOptions translates query parameters into searcher options
+func queryOptions(query map[string][]string) ([]search.Option, error) {
+	get := func(key string) string {
+		if values := query[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	boolParam := func(key string) (bool, error) {
+		value := get(key)
+		if value == "" {
+			return false, nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+		return b, nil
+	}
+	intParam := func(key string, fallback int) (int, error) {
+		value := get(key)
+		if value == "" {
+			return fallback, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+		return n, nil
+	}
+
+	var options []search.Option
+	if value := get("type"); value != "" {
+		types, err := search.ParseTypeSet(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for type: %s", value)
+		}
+		options = append(options, search.WithTypes(types))
+	}
+
+	for key, option := range map[string]func(bool) search.Option{
+		"regex":         search.WithRegex,
+		"fixed":         search.WithFixed,
+		"casesensitive": search.WithCaseSensitive,
+		"smartcase":     search.WithSmartCase,
+		"follow":        search.WithFollowSymlinks,
+		"hidden":        search.WithHidden,
+	} {
+		enabled, err := boolParam(key)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option(enabled))
+	}
+	noIgnore, err := boolParam("noignore")
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, search.WithIgnoreFiles(!noIgnore))
+
+	maxDepth, err := intParam("maxdepth", -1)
+	if err != nil {
+		return nil, err
+	}
+	minDepth, err := intParam("mindepth", 0)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, search.WithMaxDepth(maxDepth), search.WithMinDepth(minDepth))
+	options = append(options, search.WithExcludes(query["exclude"]...), search.WithPrunes(query["prune"]...))
+	// Responses carry the size and mtime of every match
+	options = append(options, search.WithInfo(true))
+	return options, nil
+}
+
+// writeJSON sends value as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// displayServeHelp prints usage instructions for the serve subcommand
+func displayServeHelp(program string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s serve [--addr <host:port> | --grpc <host:port>]\n", program)
+	fmt.Fprintf(os.Stderr, "Serves GET /search?root=<dir>&pattern=<glob> as JSON (default address: %s).\n", defaultServeAddr)
+	fmt.Fprintln(os.Stderr, "Query parameters: root (repeatable), pattern, type (f,d,l,s,p,x,e,b), regex,")
+	fmt.Fprintln(os.Stderr, "fixed, casesensitive, smartcase, follow, hidden, noignore, maxdepth, mindepth,")
+	fmt.Fprintln(os.Stderr, "exclude and prune (both repeatable).")
+	fmt.Fprintln(os.Stderr, "With limit and offset, the results are kept for later pages, read with the")
+	fmt.Fprintln(os.Stderr, "returned query handle: /search?query=<handle>&offset=<N>&limit=<N>.")
+	fmt.Fprintln(os.Stderr, "With --grpc, serves the gosearch.v1.SearchService defined in proto/search.proto")
+	fmt.Fprintln(os.Stderr, "instead, over unencrypted HTTP/2: Search streams the matches, and SearchPage")
+	fmt.Fprintln(os.Stderr, "pages through them with page_size and page_token.")
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// printStats writes the --stats summary of a finished search
+func printStats(w io.Writer, stats search.Stats, matches int64, elapsed time.Duration, jobs int) {
+	fmt.Fprintln(w, "Statistics:")
+	fmt.Fprintf(w, "  Directories traversed: %d (%d listed from cache)\n", stats.Dirs, stats.CachedDirs)
+	fmt.Fprintf(w, "  Entries examined:      %d\n", stats.Entries)
+	fmt.Fprintf(w, "  Matches:               %d\n", matches)
+	fmt.Fprintf(w, "  Errors skipped:        %d\n", stats.Errors)
+	fmt.Fprintf(w, "  Bytes stat'ed:         %d (%s)\n", stats.Bytes, humanSize(stats.Bytes))
+	fmt.Fprintf(w, "  Elapsed:               %s\n", elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "  Peak busy workers:     %d of %d\n", stats.PeakWorkers, jobs)
+	fmt.Fprintf(w, "  Peak goroutines:       %d\n", stats.PeakGoroutines)
+}
@@ -0,0 +1,21 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// errNoTerminal is returned where terminal control isn't implemented
+var errNoTerminal = errors.New("interactive mode is not supported on this platform")
+
+// makeRaw is unsupported on this platform
+func makeRaw(f *os.File) (func(), error) {
+	return nil, errNoTerminal
+}
+
+// terminalSize is unsupported on this platform
+func terminalSize(f *os.File) (int, int, error) {
+	return 0, 0, errNoTerminal
+}
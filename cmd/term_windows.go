@@ -0,0 +1,77 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Console modes used for raw input with VT escape sequences
+const (
+	enableProcessedInput            = 0x0001
+	enableLineInput                 = 0x0002
+	enableEchoInput                 = 0x0004
+	enableVirtualTerminalInput      = 0x0200
+	enableVirtualTerminalProcessing = 0x0004
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// consoleScreenBufferInfo mirrors CONSOLE_SCREEN_BUFFER_INFO
+type consoleScreenBufferInfo struct {
+	size, cursorPosition     [2]int16
+	attributes               uint16
+	left, top, right, bottom int16
+	maximumWindowSize        [2]int16
+}
+
+// setConsoleMode changes the mode of a console handle
+func setConsoleMode(handle syscall.Handle, mode uint32) error {
+	if r, _, err := procSetConsoleMode.Call(uintptr(handle), uintptr(mode)); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// makeRaw switches the console to unbuffered VT input, and VT output on
+// stderr where the interface is drawn, returning a function restoring the
+// previous modes
+func makeRaw(f *os.File) (func(), error) {
+	in := syscall.Handle(f.Fd())
+	var oldIn uint32
+	if err := syscall.GetConsoleMode(in, &oldIn); err != nil {
+		return nil, err
+	}
+	raw := oldIn&^(enableProcessedInput|enableLineInput|enableEchoInput) | enableVirtualTerminalInput
+	if err := setConsoleMode(in, raw); err != nil {
+		return nil, err
+	}
+
+	out := syscall.Handle(os.Stderr.Fd())
+	var oldOut uint32
+	hasOut := syscall.GetConsoleMode(out, &oldOut) == nil
+	if hasOut {
+		setConsoleMode(out, oldOut|enableVirtualTerminalProcessing)
+	}
+	return func() {
+		setConsoleMode(in, oldIn)
+		if hasOut {
+			setConsoleMode(out, oldOut)
+		}
+	}, nil
+}
+
+// terminalSize returns the width and height of the console window
+func terminalSize(f *os.File) (int, int, error) {
+	var info consoleScreenBufferInfo
+	if r, _, err := procGetConsoleScreenBufferInfo.Call(f.Fd(), uintptr(unsafe.Pointer(&info))); r == 0 {
+		return 0, 0, err
+	}
+	return int(info.right-info.left) + 1, int(info.bottom-info.top) + 1, nil
+}
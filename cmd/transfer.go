@@ -0,0 +1,231 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// What --on-conflict does when a destination path exists
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictRename    = "rename"
+)
+
+// transferOptions selects where --copy-to and --move-to put the matches
+type transferOptions struct {
+	dest       string // destination directory, none when empty
+	move       bool   // remove the matches once copied
+	onConflict string // one of the conflict policies
+	dryRun     bool   // only print what would be done
+}
+
+// TransferMatches copies or moves the matched files below the destination,
+// at their paths relative to the directory they were found in. Directories
+// are created as needed rather than copied as a whole, and symbolic links
+// are copied as links. Matches already inside the destination are left
+// alone. It returns the exit code.
+func TransferMatches(matches <-chan search.Match, opts transferOptions, w io.Writer) int {
+	verb, done := "copy", "Copied:"
+	if opts.move {
+		verb, done = "move", "Moved:"
+	}
+	dest, err := filepath.Abs(opts.dest)
+	if err != nil {
+		logger.Error("cannot "+verb, "err", err)
+		return exitFailure
+	}
+
+	// Everything is found before anything is written, so that the walk
+	// doesn't come across the copies
+	var files []search.Match
+	for match := range matches {
+		if match.IsDir() {
+			logger.Info("skipping", "path", match.Path, "reason", "a directory")
+			continue
+		}
+		if abs, err := filepath.Abs(match.Path); err == nil && isWithin(dest, abs) {
+			continue
+		}
+		files = append(files, match)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	code := exitMatch
+	for _, file := range files {
+		rel, err := transferPath(file)
+		if err != nil {
+			logger.Error("cannot "+verb, "path", file.Path, "err", err)
+			code = exitFailure
+			continue
+		}
+		to, ok, err := resolveConflict(filepath.Join(opts.dest, rel), opts.onConflict)
+		if err == nil {
+			// Nothing is ever written outside the destination
+			if abs, absErr := filepath.Abs(to); absErr != nil || !isWithin(dest, abs) {
+				err = fmt.Errorf("%s is outside %s", to, opts.dest)
+			}
+		}
+		if err != nil {
+			logger.Error("cannot "+verb, "path", file.Path, "err", err)
+			code = exitFailure
+			continue
+		}
+		if !ok {
+			logger.Warn("skipping", "path", file.Path, "reason", to+" already exists")
+			continue
+		}
+		if opts.dryRun {
+			fmt.Fprintf(w, "Would %s: %s -> %s\n", verb, file.Path, to)
+			continue
+		}
+		if err := transferFile(file, to, opts.move); err != nil {
+			logger.Error("cannot "+verb, "path", file.Path, "err", err)
+			code = exitFailure
+			continue
+		}
+		fmt.Fprintln(w, done, file.Path, "->", to)
+	}
+	return code
+}
+
+// transferPath returns the path of a match below the destination: relative
+// to its root, or for paths listed without one, the path itself without
+// its volume, leading separators and leading .. elements. Paths that still
+// wouldn't be local to the destination are an error.
+func transferPath(match search.Match) (string, error) {
+	rel := ""
+	if match.Root != "" {
+		root, err := filepath.Abs(match.Root)
+		path, err2 := filepath.Abs(match.Path)
+		if err == nil && err2 == nil && isWithin(root, path) && root != path {
+			rel, _ = filepath.Rel(root, path)
+		}
+	}
+	if rel == "" {
+		rel = filepath.Clean(match.Path)
+		rel = strings.TrimPrefix(rel, filepath.VolumeName(rel))
+		rel = strings.TrimLeft(rel, `/\`)
+		// Cleaning leaves .. elements only at the start
+		for rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			rel = strings.TrimLeft(rel[2:], `/\`)
+		}
+	}
+	if !filepath.IsLocal(rel) || rel == "." {
+		return "", fmt.Errorf("%s has no path below the destination", match.Path)
+	}
+	return rel, nil
+}
+
+// isWithin reports whether an absolute path is dir or below it
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolveConflict returns the path to write to under the policy when the
+// destination exists, reporting false when the file is to be skipped.
+// Renaming picks the first free name of the form stem-1.ext, stem-2.ext...
+func resolveConflict(to, policy string) (string, bool, error) {
+	_, err := os.Lstat(to)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return to, true, nil
+	case err != nil:
+		return "", false, err
+	}
+	switch policy {
+	case conflictOverwrite:
+		return to, true, nil
+	case conflictRename:
+		ext := filepath.Ext(to)
+		stem := strings.TrimSuffix(to, ext)
+		for n := 1; ; n++ {
+			next := stem + "-" + strconv.Itoa(n) + ext
+			if _, err := os.Lstat(next); errors.Is(err, fs.ErrNotExist) {
+				return next, true, nil
+			} else if err != nil {
+				return "", false, err
+			}
+		}
+	}
+	return to, false, nil
+}
+
+// transferFile copies or moves a file to a path, creating its directory.
+// Moves are renames when both are on the same filesystem, and copies
+// followed by removing the file otherwise.
+func transferFile(file search.Match, to string, move bool) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+	if move {
+		if info, err := os.Lstat(to); err == nil && !info.IsDir() {
+			os.Remove(to) // replaced, as with overwriting copies
+		}
+		if os.Rename(file.Path, to) == nil {
+			return nil
+		}
+	}
+	if err := copyEntry(file.Path, to); err != nil {
+		return err
+	}
+	if move {
+		return os.Remove(file.Path)
+	}
+	return nil
+}
+
+// copyEntry copies a file with its permissions and mtime, or a symbolic
+// link as a link, replacing what is at the path. Files are written next to
+// it and renamed into place, so a symbolic link there is replaced rather
+// than written through.
+func copyEntry(from, to string) error {
+	info, err := os.Lstat(from)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(from)
+		if err != nil {
+			return err
+		}
+		os.Remove(to)
+		return os.Symlink(target, to)
+	}
+
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(to), "."+filepath.Base(to)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails once renamed into place
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), to)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+func TestTransferPath(t *testing.T) {
+	tests := []struct {
+		root, path string
+		want       string // empty for an error
+	}{
+		{"src", "src/a/b.txt", "a/b.txt"},
+		{"src", "src/a", "a"},
+		{"src/", "src/a", "a"},
+		{"src", "src", "src"},
+		{"src", "other/x", "other/x"},
+		{"", "a/b", "a/b"},
+		{"", "./a/b", "a/b"},
+		{"", "/etc/passwd", "etc/passwd"},
+		{"", "../../x", "x"},
+		{"", "a/../../b", "b"},
+		{"", "../a/../../b", "b"},
+		{"", "..", ""},
+		{"", "/", ""},
+		{"", ".", ""},
+		{"", "../..", ""},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests,
+			struct{ root, path, want string }{"", `C:\Users\me\x`, "Users/me/x"},
+			struct{ root, path, want string }{"", `\\server\share\x`, "x"},
+			struct{ root, path, want string }{"", `C:..\x`, "x"},
+		)
+	}
+	for _, tt := range tests {
+		match := search.Match{Path: filepath.FromSlash(tt.path), Root: filepath.FromSlash(tt.root)}
+		got, err := transferPath(match)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("transferPath(%q under %q) = %q, want an error", tt.path, tt.root, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("transferPath(%q under %q): %v", tt.path, tt.root, err)
+			continue
+		}
+		if got != filepath.FromSlash(tt.want) {
+			t.Errorf("transferPath(%q under %q) = %q, want %q", tt.path, tt.root, got, tt.want)
+		}
+		if !filepath.IsLocal(got) {
+			t.Errorf("transferPath(%q under %q) = %q is not local", tt.path, tt.root, got)
+		}
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	dir := filepath.FromSlash("/data/out")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/data/out", true},
+		{"/data/out/a/b", true},
+		{"/data/out/..a", true},
+		{"/data/outside", false},
+		{"/data", false},
+		{"/data/out/../x", false},
+		{"/elsewhere", false},
+	}
+	for _, tt := range tests {
+		if got := isWithin(dir, filepath.FromSlash(tt.path)); got != tt.want {
+			t.Errorf("isWithin(%q, %q) = %v, want %v", dir, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCopyEntryReplacesSymlink(t *testing.T) {
+	dir := t.TempDir()
+	from := filepath.Join(dir, "from.txt")
+	outside := filepath.Join(dir, "outside.txt")
+	to := filepath.Join(dir, "to.txt")
+	if err := os.WriteFile(from, []byte("new"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(from, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(outside, []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, to); err != nil {
+		t.Skip("cannot create symbolic links:", err)
+	}
+
+	if err := copyEntry(from, to); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(outside); string(data) != "keep" {
+		t.Errorf("the link target was written through: %q", data)
+	}
+	info, err := os.Lstat(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Mode().IsRegular() {
+		t.Errorf("%s is %v, want a regular file", to, info.Mode())
+	}
+	if data, _ := os.ReadFile(to); string(data) != "new" {
+		t.Errorf("%s holds %q, want %q", to, data, "new")
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o640 {
+		t.Errorf("%s has mode %v, want 0640", to, info.Mode().Perm())
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("%s has mtime %v, want %v", to, info.ModTime(), mtime)
+	}
+	// Nothing is left over from writing it
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 3 {
+		t.Errorf("%d entries in the directory, want 3", len(entries))
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// treeNode is a path component in the tree of matches
+type treeNode struct {
+	name     string
+	match    *search.Match // nil for directories only leading to matches
+	children []*treeNode
+	index    map[string]*treeNode // children by name
+}
+
+// child returns the child named name, adding it if needed
+func (n *treeNode) child(name string) *treeNode {
+	if c, ok := n.index[name]; ok {
+		return c
+	}
+	c := &treeNode{name: name}
+	if n.index == nil {
+		n.index = make(map[string]*treeNode)
+	}
+	n.index[name] = c
+	n.children = append(n.children, c)
+	return c
+}
+
+// treeFormatter prints the matches as an indented tree below each root,
+// like tree --prune: only directories leading to matches are shown, and
+// chains of them are collapsed into one line. The whole tree is held until
+// Close.
+type treeFormatter struct {
+	w         io.Writer
+	colors    *colorizer
+	roots     []string
+	keepOrder bool // keep the order matches arrived in instead of sorting by name
+	tops      []*treeNode
+	count     int
+}
+
+func newTreeFormatter(w io.Writer, config formatConfig) *treeFormatter {
+	f := &treeFormatter{w: w, colors: config.colors, keepOrder: config.keepOrder}
+	// Longest roots first, so that a match is placed under the closest one
+	f.roots = append(f.roots, config.roots...)
+	sort.SliceStable(f.roots, func(i, j int) bool { return len(f.roots[i]) > len(f.roots[j]) })
+	return f
+}
+
+func (f *treeFormatter) Write(match search.Match) error {
+	f.count++
+	top, names := f.split(match.Path)
+	node := f.top(top)
+	for _, name := range names {
+		node = node.child(name)
+	}
+	node.match = &match
+	return nil
+}
+
+// split divides a path into the root it was found under, or its volume
+// and leading separator when it isn't below any root, and the names below it
+func (f *treeFormatter) split(path string) (string, []string) {
+	for _, root := range f.roots {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if rel == "." {
+			return root, nil
+		}
+		return root, strings.Split(rel, string(filepath.Separator))
+	}
+
+	path = filepath.Clean(path)
+	top := filepath.VolumeName(path)
+	rest := path[len(top):]
+	if strings.HasPrefix(rest, string(filepath.Separator)) {
+		top += string(filepath.Separator)
+		rest = rest[1:]
+	}
+	if top == "" {
+		top = "."
+	}
+	if rest == "" || rest == "." {
+		return top, nil
+	}
+	return top, strings.Split(rest, string(filepath.Separator))
+}
+
+// top returns the tree of a root, adding it if needed
+func (f *treeFormatter) top(name string) *treeNode {
+	for _, top := range f.tops {
+		if top.name == name {
+			return top
+		}
+	}
+	top := &treeNode{name: name}
+	f.tops = append(f.tops, top)
+	return top
+}
+
+func (f *treeFormatter) Close() error {
+	if f.count == 0 {
+		_, err := fmt.Fprintln(f.w, "No path matches the pattern")
+		return err
+	}
+	var buf strings.Builder
+	for _, top := range f.tops {
+		buf.WriteString(f.label(top, top.name))
+		buf.WriteString("\n")
+		f.render(&buf, top, "")
+	}
+	_, err := io.WriteString(f.w, buf.String())
+	return err
+}
+
+// render writes the children of a node, each line prefixed by the
+// branches of the levels above
+func (f *treeFormatter) render(buf *strings.Builder, node *treeNode, prefix string) {
+	children := node.children
+	if !f.keepOrder {
+		sort.Slice(children, func(i, j int) bool { return children[i].name < children[j].name })
+	}
+	for i, child := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		// Directories that only lead to a single directory are joined
+		name := child.name
+		for child.match == nil && len(child.children) == 1 && len(child.children[0].children) > 0 &&
+			child.children[0].match == nil {
+			child = child.children[0]
+			name += string(filepath.Separator) + child.name
+		}
+		buf.WriteString(prefix + branch + f.label(child, name) + "\n")
+		f.render(buf, child, prefix+indent)
+	}
+}
+
+// label renders the name of a node, colored like its match
+func (f *treeFormatter) label(node *treeNode, name string) string {
+	if f.colors == nil {
+		return name
+	}
+	if node.match == nil {
+		return f.colors.Dir(name)
+	}
+	return f.colors.Name(*node.match, name)
+}
@@ -1,3 +1,3 @@
 module github.com/sean1832/go-search
 
-go 1.23.1
+go 1.24.0
@@ -0,0 +1,500 @@
+// Package index stores a snapshot of a directory tree on disk so searches
+// can be answered without walking the filesystem.
+//
+// Indexes are compact binary files holding every entry under a root with
+// its mode, size and mtime. Update refreshes an index incrementally: a
+// directory whose mtime hasn't changed still has the same children, so its
+// listing is reused instead of being read again. Its files are stat-ed
+// again all the same, since writing to a file doesn't touch the mtime of
+// its directory.
+package index
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Magic bytes and version identifying an index file
+const (
+	magic   = "GSIX"
+	version = 1
+)
+
+// Extension of index files in the cache directory
+const fileExt = ".idx"
+
+// ErrNotFound is returned when no index covers a directory
+var ErrNotFound = errors.New("no index found")
+
+// Entry is a single filesystem entry recorded in an index
+type Entry struct {
+	Path    string // slash separated, relative to the index root
+	Mode    fs.FileMode
+	Size    int64
+	ModTime time.Time
+}
+
+// DirEntry presents the entry as an fs.DirEntry, as if read from disk
+func (e Entry) DirEntry() fs.DirEntry {
+	return fs.FileInfoToDirEntry(entryInfo{e})
+}
+
+// entryInfo implements fs.FileInfo for an indexed entry
+type entryInfo struct {
+	e Entry
+}
+
+func (i entryInfo) Name() string       { return path.Base(i.e.Path) }
+func (i entryInfo) Size() int64        { return i.e.Size }
+func (i entryInfo) Mode() fs.FileMode  { return i.e.Mode }
+func (i entryInfo) ModTime() time.Time { return i.e.ModTime }
+func (i entryInfo) IsDir() bool        { return i.e.Mode.IsDir() }
+func (i entryInfo) Sys() any           { return nil }
+
+// Index is a snapshot of every entry under Root, sorted by path
+type Index struct {
+	Root    string // absolute path of the indexed directory
+	Built   time.Time
+	Entries []Entry
+}
+
+// Stats summarizes the work done by Build or Update
+type Stats struct {
+	Entries     int
+	ScannedDirs int // directories read from disk
+	ReusedDirs  int // directories whose listing was reused from the old index
+	Errors      int // entries skipped because they couldn't be read
+}
+
+// Build indexes the tree under root from scratch
+func Build(root string) (*Index, Stats, error) {
+	return (&Index{}).rebuild(root, nil)
+}
+
+// Update refreshes the index, only reading directories that changed since
+// it was built
+func (idx *Index) Update() (*Index, Stats, error) {
+	return idx.rebuild(idx.Root, nil)
+}
+
+// UpdateDirs refreshes the index from the directories known to have
+// changed, slash separated and relative to the root, such as those a
+// filesystem notification named. Only they and directories new to the
+// index are read; nothing else is even stat-ed.
+func (idx *Index) UpdateDirs(dirs []string) (*Index, Stats, error) {
+	changed := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		changed[path.Clean(dir)] = true
+	}
+	return idx.rebuild(idx.Root, changed)
+}
+
+// rebuild walks root, reusing listings of unchanged directories from idx.
+// Directories are unchanged when their mtime is, or with a changed set,
+// when they aren't in it.
+func (idx *Index) rebuild(root string, changed map[string]bool) (*Index, Stats, error) {
+	var stats Stats
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, stats, err
+	}
+	info, err := os.Lstat(abs)
+	if err != nil {
+		return nil, stats, err
+	}
+	if !info.IsDir() {
+		return nil, stats, fmt.Errorf("%s is not a directory", root)
+	}
+
+	// Group the old entries by parent so unchanged listings can be reused
+	old := make(map[string]Entry, len(idx.Entries))
+	children := make(map[string][]Entry)
+	for _, e := range idx.Entries {
+		old[e.Path] = e
+		if e.Path != "." {
+			parent := path.Dir(e.Path)
+			children[parent] = append(children[parent], e)
+		}
+	}
+
+	next := &Index{Root: abs, Built: time.Now()}
+	var visit func(rel string, info fs.FileInfo)
+	visit = func(rel string, info fs.FileInfo) {
+		next.Entries = append(next.Entries, Entry{
+			Path:    rel,
+			Mode:    info.Mode(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		if !info.IsDir() {
+			return
+		}
+
+		dir := filepath.Join(abs, filepath.FromSlash(rel))
+		prev, ok := old[rel]
+		if changed != nil {
+			ok = ok && !changed[rel]
+		} else {
+			ok = ok && prev.ModTime.Equal(info.ModTime())
+		}
+		if ok && prev.Mode.IsDir() {
+			stats.ReusedDirs++
+			for _, child := range children[rel] {
+				// Files and subdirectories may have changed, so check them
+				// again, unless the changes are known
+				if changed != nil && !changed[child.Path] {
+					if child.Mode.IsDir() {
+						visit(child.Path, entryInfo{child})
+					} else {
+						next.Entries = append(next.Entries, child)
+					}
+					continue
+				}
+				childInfo, err := os.Lstat(filepath.Join(abs, filepath.FromSlash(child.Path)))
+				if errors.Is(err, fs.ErrNotExist) {
+					continue // removed since the notification
+				}
+				if err != nil {
+					stats.Errors++
+					continue
+				}
+				visit(child.Path, childInfo)
+			}
+			return
+		}
+
+		stats.ScannedDirs++
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			stats.Errors++
+			return
+		}
+		for _, entry := range entries {
+			childInfo, err := entry.Info()
+			if err != nil {
+				stats.Errors++
+				continue
+			}
+			visit(path.Join(rel, entry.Name()), childInfo)
+		}
+	}
+	visit(".", info)
+
+	sort.Slice(next.Entries, func(i, j int) bool { return next.Entries[i].Path < next.Entries[j].Path })
+	stats.Entries = len(next.Entries)
+	return next, stats, nil
+}
+
+// Under returns the entries inside dir, which must be the root or one of
+// its subdirectories, with paths made relative to dir
+func (idx *Index) Under(dir string) ([]Entry, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(idx.Root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is not inside the indexed directory %s", dir, idx.Root)
+	}
+	if rel == "." {
+		return idx.Entries, nil
+	}
+
+	prefix := filepath.ToSlash(rel)
+	var entries []Entry
+	for _, e := range idx.Entries {
+		switch {
+		case e.Path == prefix:
+			e.Path = "."
+		case strings.HasPrefix(e.Path, prefix+"/"):
+			e.Path = e.Path[len(prefix)+1:]
+		default:
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Dir returns the directory holding index files, under the user cache dir
+func Dir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "go-search", "index"), nil
+}
+
+// PathFor returns the file the index of root is stored in
+func PathFor(root string) (string, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+fileExt), nil
+}
+
+// Find loads the index covering dir, either its own or that of the nearest
+// indexed ancestor
+func Find(dir string) (*Index, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		file, err := PathFor(abs)
+		if err != nil {
+			return nil, err
+		}
+		if idx, err := Load(file); err == nil {
+			return idx, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil, fmt.Errorf("%w for %s", ErrNotFound, dir)
+		}
+		abs = parent
+	}
+}
+
+// All loads every index in the cache directory
+func All() ([]*Index, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+fileExt))
+	if err != nil {
+		return nil, err
+	}
+	var indexes []*Index
+	for _, file := range files {
+		idx, err := Load(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, nil
+}
+
+// Save writes the index to its file in the cache directory
+func (idx *Index) Save() error {
+	file, err := PathFor(idx.Root)
+	if err != nil {
+		return err
+	}
+	return idx.SaveTo(file)
+}
+
+// SaveTo atomically writes the index to file
+func (idx *Index) SaveTo(file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".index-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	if err := idx.encode(w); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// Load reads an index file
+func Load(file string) (*Index, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return decode(bufio.NewReader(f), info.Size())
+}
+
+// encode writes the index. Paths are front coded: each stores only the
+// length of the prefix shared with the previous path and the new suffix.
+func (idx *Index) encode(w *bufio.Writer) error {
+	var buf [binary.MaxVarintLen64]byte
+	putUvarint := func(v uint64) {
+		n := binary.PutUvarint(buf[:], v)
+		w.Write(buf[:n])
+	}
+	putVarint := func(v int64) {
+		n := binary.PutVarint(buf[:], v)
+		w.Write(buf[:n])
+	}
+	putString := func(s string) {
+		putUvarint(uint64(len(s)))
+		w.WriteString(s)
+	}
+
+	w.WriteString(magic)
+	putUvarint(version)
+	putString(idx.Root)
+	putVarint(idx.Built.UnixNano())
+	putUvarint(uint64(len(idx.Entries)))
+
+	prev := ""
+	for _, e := range idx.Entries {
+		shared := commonPrefix(prev, e.Path)
+		putUvarint(uint64(shared))
+		putString(e.Path[shared:])
+		putUvarint(uint64(e.Mode))
+		putVarint(e.Size)
+		putVarint(e.ModTime.UnixNano())
+		prev = e.Path
+	}
+	return nil
+}
+
+// minEntrySize is the fewest bytes an encoded entry takes, a byte for each
+// of its fields
+const minEntrySize = 5
+
+// decode reads an index written by encode, of size bytes. The counts and
+// lengths read are checked against the bytes left, so that a corrupt file
+// can't make it allocate more than the file holds.
+func decode(br *bufio.Reader, size int64) (*Index, error) {
+	r := &limitedReader{r: br, n: size}
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != magic {
+		return nil, errors.New("not an index file")
+	}
+	if v, err := binary.ReadUvarint(r); err != nil || v != version {
+		return nil, errors.New("unsupported index version")
+	}
+
+	var err error
+	readUvarint := func() uint64 {
+		if err != nil {
+			return 0
+		}
+		var v uint64
+		v, err = binary.ReadUvarint(r)
+		return v
+	}
+	readVarint := func() int64 {
+		if err != nil {
+			return 0
+		}
+		var v int64
+		v, err = binary.ReadVarint(r)
+		return v
+	}
+	readString := func() string {
+		n := readUvarint()
+		if err == nil && n > uint64(r.n) {
+			err = errors.New("string longer than the file")
+		}
+		if err != nil {
+			return ""
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(r, b)
+		return string(b)
+	}
+
+	idx := &Index{Root: readString(), Built: time.Unix(0, readVarint())}
+	count := readUvarint()
+	if err == nil && count > uint64(r.n)/minEntrySize {
+		err = errors.New("more entries than the file holds")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("corrupt index: %w", err)
+	}
+	idx.Entries = make([]Entry, 0, count)
+	prev := ""
+	for i := uint64(0); i < count; i++ {
+		shared := readUvarint()
+		suffix := readString()
+		if err == nil && shared > uint64(len(prev)) {
+			err = errors.New("bad path prefix")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("corrupt index: %w", err)
+		}
+		e := Entry{Path: prev[:shared] + suffix}
+		e.Mode = fs.FileMode(readUvarint())
+		e.Size = readVarint()
+		e.ModTime = time.Unix(0, readVarint())
+		if err != nil {
+			return nil, fmt.Errorf("corrupt index: %w", err)
+		}
+		idx.Entries = append(idx.Entries, e)
+		prev = e.Path
+	}
+	return idx, nil
+}
+
+// limitedReader reads at most n more bytes from r
+type limitedReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (l *limitedReader) ReadByte() (byte, error) {
+	if l.n <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b, err := l.r.ReadByte()
+	if err == nil {
+		l.n--
+	}
+	return b, err
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// commonPrefix returns the length of the prefix shared by a and b
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
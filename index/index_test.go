@@ -0,0 +1,167 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// encoded returns the bytes encode writes for the index
+func encoded(t *testing.T, idx *Index) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := idx.encode(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeBytes(data []byte) (*Index, error) {
+	return decode(bufio.NewReader(bytes.NewReader(data)), int64(len(data)))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	built := time.Date(2024, 6, 15, 12, 30, 0, 123, time.UTC)
+	tests := []struct {
+		name string
+		idx  *Index
+	}{
+		{"empty", &Index{Root: "/", Built: built}},
+		{"entries", &Index{Root: "/home/user/src", Built: built, Entries: []Entry{
+			{Path: ".", Mode: fs.ModeDir | 0o755, ModTime: built},
+			{Path: "a", Mode: fs.ModeDir | 0o700, Size: 4096, ModTime: built.Add(-time.Hour)},
+			{Path: "a/b.go", Mode: 0o644, Size: 1234, ModTime: built.Add(-2 * time.Hour)},
+			{Path: "a/b.go.bak", Mode: 0o600, ModTime: time.Unix(0, 1)},
+			{Path: "a/c", Mode: fs.ModeSymlink | 0o777, Size: 5, ModTime: time.Unix(-100, 0)},
+			{Path: "z/caf\u00e9 \u65e5\u672c", Mode: 0o644, Size: 1 << 40, ModTime: built},
+		}}},
+		{"windows root", &Index{Root: `C:\Users\me`, Built: built, Entries: []Entry{
+			{Path: "x", Mode: 0o666, Size: 1, ModTime: built},
+		}}},
+	}
+	for _, tt := range tests {
+		got, err := decodeBytes(encoded(t, tt.idx))
+		if err != nil {
+			t.Errorf("%s: decode: %v", tt.name, err)
+			continue
+		}
+		if got.Root != tt.idx.Root || !got.Built.Equal(tt.idx.Built) || len(got.Entries) != len(tt.idx.Entries) {
+			t.Errorf("%s: decoded %s %v with %d entries, want %s %v with %d", tt.name,
+				got.Root, got.Built, len(got.Entries), tt.idx.Root, tt.idx.Built, len(tt.idx.Entries))
+			continue
+		}
+		for i, e := range got.Entries {
+			want := tt.idx.Entries[i]
+			if e.Path != want.Path || e.Mode != want.Mode || e.Size != want.Size || !e.ModTime.Equal(want.ModTime) {
+				t.Errorf("%s: entry %d = %+v, want %+v", tt.name, i, e, want)
+			}
+		}
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	idx := &Index{Root: "/src", Built: time.Now(), Entries: []Entry{
+		{Path: "a", Mode: fs.ModeDir | 0o755, Size: 4096, ModTime: time.Now()},
+		{Path: "a/long file name.txt", Mode: 0o644, Size: 99999, ModTime: time.Now()},
+		{Path: "a/long file name.txt.orig", Mode: 0o644, Size: 7, ModTime: time.Now()},
+	}}
+	data := encoded(t, idx)
+	for n := 0; n < len(data); n++ {
+		if _, err := decodeBytes(data[:n]); err == nil {
+			t.Errorf("decoding the first %d of %d bytes succeeded", n, len(data))
+		}
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	header := magic + "\x01" // version 1
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"empty", "", "not an index file"},
+		{"magic", "GSIY\x01", "not an index file"},
+		{"version", magic + "\x02", "unsupported index version"},
+		// A root claiming to be far longer than the file
+		{"root length", header + "\xff\xff\xff\xff\x0f", "string longer than the file"},
+		// Root "/", built at 0, then a count of 2^40 entries
+		{"entry count", header + "\x01/\x00\x80\x80\x80\x80\x80\x20", "more entries than the file holds"},
+		// One entry sharing 3 bytes with the empty previous path
+		{"prefix", header + "\x01/\x00\x01\x03\x01a\x00\x00\x00", "bad path prefix"},
+	}
+	for _, tt := range tests {
+		_, err := decodeBytes([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: decode error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	idx := &Index{Root: dir, Built: time.Now(), Entries: []Entry{
+		{Path: ".", Mode: fs.ModeDir | 0o755, ModTime: time.Now()},
+		{Path: "f", Mode: 0o644, Size: 3, ModTime: time.Now()},
+	}}
+	file := filepath.Join(dir, "cache", "test"+fileExt)
+	if err := idx.SaveTo(file); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Root != idx.Root || len(got.Entries) != 2 || got.Entries[1].Path != "f" || got.Entries[1].Size != 3 {
+		t.Errorf("Load = %+v, want %+v", got, idx)
+	}
+}
+
+func TestUpdateSeesChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sub", "f.txt")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("short"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	idx, _, err := Build(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewriting a file in place leaves the mtime of its directory alone
+	dirInfo, err := os.Stat(filepath.Dir(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("a longer text"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Dir(file), dirInfo.ModTime(), dirInfo.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	updated, _, err := idx.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range updated.Entries {
+		if e.Path == "sub/f.txt" {
+			if e.Size != int64(len("a longer text")) {
+				t.Errorf("updated size = %d, want %d", e.Size, len("a longer text"))
+			}
+			return
+		}
+	}
+	t.Errorf("sub/f.txt missing from the updated index: %+v", updated.Entries)
+}
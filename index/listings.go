@@ -0,0 +1,331 @@
+package index
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Magic bytes and version identifying a listing cache file
+const (
+	listingsMagic   = "GSLC"
+	listingsVersion = 1
+)
+
+// Extension of listing cache files in the cache directory
+const listingsExt = ".lst"
+
+// ListingsTTL is how long the listings of a root are kept after it was last
+// searched
+const ListingsTTL = time.Hour
+
+// listing is the cached content of one directory
+type listing struct {
+	modTime time.Time
+	entries []fs.DirEntry
+}
+
+// Listings caches the directory listings of recently searched roots
+// between runs. Unlike an index it holds nothing but names and types, and
+// a listing is only used while its directory's mtime is unchanged, so the
+// results are never stale. It is safe for concurrent use.
+type Listings struct {
+	roots []string // absolute roots the listings are saved for
+	cwd   string
+
+	mu    sync.Mutex
+	dirs  map[string]listing // by absolute directory path
+	dirty bool
+}
+
+// LoadListings loads the cached listings of the roots. Roots without a
+// cache file, or with one older than ListingsTTL, start out empty.
+func LoadListings(roots []string) *Listings {
+	l := &Listings{dirs: make(map[string]listing)}
+	l.cwd, _ = os.Getwd()
+	for _, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		l.roots = append(l.roots, abs)
+		file, err := ListingsPathFor(abs)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(file); err != nil || time.Since(info.ModTime()) > ListingsTTL {
+			continue
+		}
+		// A corrupt or outdated file is simply rebuilt
+		_ = l.load(file)
+	}
+	return l
+}
+
+// listingsDir returns the directory holding listing cache files, under
+// the user cache dir
+func listingsDir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "go-search", "listings"), nil
+}
+
+// ListingsPathFor returns the file the listings under root are stored in
+func ListingsPathFor(root string) (string, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	dir, err := listingsDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+listingsExt), nil
+}
+
+// key returns the absolute path a walked directory is cached under,
+// without asking the OS for the working directory every time. The
+// extended-length paths walked on Windows are keyed by their plain form.
+func (l *Listings) key(dir string) string {
+	switch {
+	case strings.HasPrefix(dir, `\\?\UNC\`):
+		dir = `\\` + dir[len(`\\?\UNC\`):]
+	case strings.HasPrefix(dir, `\\?\`):
+		dir = dir[len(`\\?\`):]
+	}
+	if filepath.IsAbs(dir) || l.cwd == "" {
+		return filepath.Clean(dir)
+	}
+	return filepath.Join(l.cwd, dir)
+}
+
+// Listing returns the cached entries of dir if its mtime still matches
+func (l *Listings) Listing(dir string, modTime time.Time) ([]fs.DirEntry, bool) {
+	l.mu.Lock()
+	cached, ok := l.dirs[l.key(dir)]
+	l.mu.Unlock()
+	if !ok || !cached.modTime.Equal(modTime) {
+		return nil, false
+	}
+	entries := make([]fs.DirEntry, len(cached.entries))
+	for i, e := range cached.entries {
+		entry := e.(listingEntry)
+		entry.dir = dir
+		entries[i] = entry
+	}
+	return entries, true
+}
+
+// Store records the entries of dir read while it had the given mtime
+func (l *Listings) Store(dir string, modTime time.Time, entries []fs.DirEntry) {
+	cached := listing{modTime: modTime, entries: make([]fs.DirEntry, len(entries))}
+	for i, e := range entries {
+		cached.entries[i] = listingEntry{name: e.Name(), typ: e.Type()}
+	}
+	l.mu.Lock()
+	l.dirs[l.key(dir)] = cached
+	l.dirty = true
+	l.mu.Unlock()
+}
+
+// Save writes the listings of each root to its cache file, when any
+// changed, and removes the files of roots not searched for ListingsTTL
+func (l *Listings) Save() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.dirty {
+		// Still mark the roots as recently searched
+		for _, root := range l.roots {
+			if file, err := ListingsPathFor(root); err == nil {
+				now := time.Now()
+				os.Chtimes(file, now, now)
+			}
+		}
+		return nil
+	}
+
+	for _, root := range l.roots {
+		file, err := ListingsPathFor(root)
+		if err != nil {
+			return err
+		}
+		if err := l.saveTo(file, root); err != nil {
+			return err
+		}
+	}
+	l.dirty = false
+	return removeExpired()
+}
+
+// removeExpired deletes the listing files older than ListingsTTL
+func removeExpired() error {
+	dir, err := listingsDir()
+	if err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+listingsExt))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil && time.Since(info.ModTime()) > ListingsTTL {
+			os.Remove(file)
+		}
+	}
+	return nil
+}
+
+// saveTo atomically writes the listings of the directories under root
+func (l *Listings) saveTo(file, root string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".listings-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	l.encode(w, root)
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// encode writes the listings below root: each directory's path, mtime and
+// the name and type bits of its entries
+func (l *Listings) encode(w *bufio.Writer, root string) {
+	var buf [binary.MaxVarintLen64]byte
+	putUvarint := func(v uint64) {
+		n := binary.PutUvarint(buf[:], v)
+		w.Write(buf[:n])
+	}
+	putVarint := func(v int64) {
+		n := binary.PutVarint(buf[:], v)
+		w.Write(buf[:n])
+	}
+	putString := func(s string) {
+		putUvarint(uint64(len(s)))
+		w.WriteString(s)
+	}
+
+	var dirs []string
+	for dir := range l.dirs {
+		if dir == root || strings.HasPrefix(dir, root+string(filepath.Separator)) ||
+			strings.HasSuffix(root, string(filepath.Separator)) && strings.HasPrefix(dir, root) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	w.WriteString(listingsMagic)
+	putUvarint(listingsVersion)
+	putUvarint(uint64(len(dirs)))
+	for _, dir := range dirs {
+		cached := l.dirs[dir]
+		putString(dir)
+		putVarint(cached.modTime.UnixNano())
+		putUvarint(uint64(len(cached.entries)))
+		for _, e := range cached.entries {
+			putString(e.Name())
+			putUvarint(uint64(e.Type()))
+		}
+	}
+}
+
+// load reads a file written by encode into the cache
+func (l *Listings) load(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	header := make([]byte, len(listingsMagic))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != listingsMagic {
+		return errors.New("not a listing cache file")
+	}
+	if v, err := binary.ReadUvarint(r); err != nil || v != listingsVersion {
+		return errors.New("unsupported listing cache version")
+	}
+	readUvarint := func() uint64 {
+		if err != nil {
+			return 0
+		}
+		var v uint64
+		v, err = binary.ReadUvarint(r)
+		return v
+	}
+	readVarint := func() int64 {
+		if err != nil {
+			return 0
+		}
+		var v int64
+		v, err = binary.ReadVarint(r)
+		return v
+	}
+	readString := func() string {
+		n := readUvarint()
+		if err != nil {
+			return ""
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(r, b)
+		return string(b)
+	}
+
+	dirs := make(map[string]listing)
+	count := readUvarint()
+	for i := uint64(0); i < count && err == nil; i++ {
+		dir := readString()
+		cached := listing{modTime: time.Unix(0, readVarint())}
+		n := readUvarint()
+		for j := uint64(0); j < n && err == nil; j++ {
+			cached.entries = append(cached.entries, listingEntry{name: readString(), typ: fs.FileMode(readUvarint())})
+		}
+		dirs[dir] = cached
+	}
+	if err != nil {
+		return fmt.Errorf("corrupt listing cache: %w", err)
+	}
+	for dir, cached := range dirs {
+		l.dirs[dir] = cached
+	}
+	return nil
+}
+
+// listingEntry is a cached directory entry. Its name and type come from
+// the cache, while Info reads the entry from disk like os.ReadDir's
+// entries do.
+type listingEntry struct {
+	dir  string
+	name string
+	typ  fs.FileMode
+}
+
+func (e listingEntry) Name() string      { return e.name }
+func (e listingEntry) IsDir() bool       { return e.typ.IsDir() }
+func (e listingEntry) Type() fs.FileMode { return e.typ }
+
+func (e listingEntry) Info() (fs.FileInfo, error) {
+	return os.Lstat(filepath.Join(e.dir, e.name))
+}
@@ -0,0 +1,141 @@
+package index
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Magic bytes of the databases written by the updatedb of mlocate and of
+// plocate
+const (
+	mlocateMagic = "\x00mlocate"
+	plocateMagic = "\x00plocate"
+)
+
+// Size of the header of a directory in an mlocate database: its mtime in
+// seconds and nanoseconds, and padding
+const mlocateDirHeader = 16
+
+// Entry types in an mlocate database
+const (
+	mlocateFile   = 0
+	mlocateSubdir = 1
+	mlocateEnd    = 2
+)
+
+// ReadLocateDB calls fn for every path recorded in an mlocate database,
+// such as /var/lib/mlocate/mlocate.db, starting with the root the database
+// was built from and in the order updatedb wrote them. The database only
+// records names and whether they are directories, so the entries passed to
+// fn stat their path when asked for their info. An error returned by fn
+// stops reading and is returned.
+func ReadLocateDB(dbPath string, fn func(path string, d fs.DirEntry) error) error {
+	file, err := os.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	r := bufio.NewReaderSize(file, 1<<16)
+
+	head := make([]byte, len(mlocateMagic)+8)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return fmt.Errorf("%s is not an mlocate database", dbPath)
+	}
+	switch string(head[:len(mlocateMagic)]) {
+	case mlocateMagic:
+	case plocateMagic:
+		return fmt.Errorf("%s is a plocate database, whose compression isn't supported; "+
+			"use the mlocate database of updatedb instead", dbPath)
+	default:
+		return fmt.Errorf("%s is not an mlocate database", dbPath)
+	}
+	confSize := binary.BigEndian.Uint32(head[len(mlocateMagic):])
+	if dbVersion := head[len(mlocateMagic)+4]; dbVersion != 0 {
+		return fmt.Errorf("unsupported mlocate database version %d in %s", dbVersion, dbPath)
+	}
+	root, err := readCString(r)
+	if err != nil {
+		return corruptLocateDB(dbPath, err)
+	}
+	if _, err := io.CopyN(io.Discard, r, int64(confSize)); err != nil {
+		return corruptLocateDB(dbPath, err)
+	}
+	if err := fn(root, &locateEntry{path: root, dir: true}); err != nil {
+		return err
+	}
+
+	header := make([]byte, mlocateDirHeader)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return corruptLocateDB(dbPath, err)
+		}
+		dir, err := readCString(r)
+		if err != nil {
+			return corruptLocateDB(dbPath, err)
+		}
+		for {
+			kind, err := r.ReadByte()
+			if err != nil {
+				return corruptLocateDB(dbPath, err)
+			}
+			if kind == mlocateEnd {
+				break
+			}
+			name, err := readCString(r)
+			if err != nil {
+				return corruptLocateDB(dbPath, err)
+			}
+			path := filepath.Join(dir, name)
+			if err := fn(path, &locateEntry{path: path, dir: kind == mlocateSubdir}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readCString reads a NUL terminated string
+func readCString(r *bufio.Reader) (string, error) {
+	s, err := r.ReadString(0)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(s, "\x00"), nil
+}
+
+// corruptLocateDB describes an error reading the body of a database
+func corruptLocateDB(dbPath string, err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("corrupt mlocate database %s: %w", dbPath, err)
+}
+
+// locateEntry is a path recorded in a locate database
+type locateEntry struct {
+	path string
+	dir  bool
+}
+
+func (e *locateEntry) Name() string { return filepath.Base(e.path) }
+func (e *locateEntry) IsDir() bool  { return e.dir }
+
+func (e *locateEntry) Type() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+// Info stats the path, which may have changed since updatedb ran
+func (e *locateEntry) Info() (fs.FileInfo, error) {
+	return os.Lstat(e.path)
+}
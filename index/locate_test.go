@@ -0,0 +1,147 @@
+package index
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// locateDir is a directory record of an mlocate database
+type locateDir struct {
+	path    string
+	entries []string // names, those of subdirectories ending in /
+}
+
+// mlocateDB encodes a database as the updatedb of mlocate writes it
+func mlocateDB(root, conf string, dirs []locateDir) []byte {
+	b := []byte(mlocateMagic)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(conf)))
+	b = append(b, 0, 0, 0, 0) // version, visibility and padding
+	b = append(b, root+"\x00"+conf...)
+	for _, dir := range dirs {
+		b = append(b, make([]byte, mlocateDirHeader)...)
+		b = append(b, dir.path+"\x00"...)
+		for _, name := range dir.entries {
+			if strings.HasSuffix(name, "/") {
+				b = append(b, mlocateSubdir)
+				name = strings.TrimSuffix(name, "/")
+			} else {
+				b = append(b, mlocateFile)
+			}
+			b = append(b, name+"\x00"...)
+		}
+		b = append(b, mlocateEnd)
+	}
+	return b
+}
+
+// writeDB writes the database to a temporary file
+func writeDB(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mlocate.db")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// A database of a small tree under /data
+const (
+	validRoot = "/data"
+	validConf = "prune_bind_mounts\x001\x00\x00"
+)
+
+var (
+	validDirs = []locateDir{
+		{"/data", []string{"a.txt", "sub/"}},
+		{"/data/sub", []string{"b.go", "deeper/"}},
+		{"/data/sub/deeper", nil},
+	}
+	validDB = mlocateDB(validRoot, validConf, validDirs)
+)
+
+func TestReadLocateDB(t *testing.T) {
+	var paths []string
+	err := ReadLocateDB(writeDB(t, validDB), func(path string, d fs.DirEntry) error {
+		if d.Name() != filepath.Base(path) {
+			t.Errorf("%s named %s", path, d.Name())
+		}
+		if d.IsDir() != d.Type().IsDir() {
+			t.Errorf("%s: IsDir %v with type %v", path, d.IsDir(), d.Type())
+		}
+		if d.IsDir() {
+			path += "/"
+		}
+		paths = append(paths, filepath.ToSlash(path))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/data/", "/data/a.txt", "/data/sub/", "/data/sub/b.go", "/data/sub/deeper/"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("read %q, want %q", paths, want)
+	}
+
+	// An error from the callback stops reading
+	errStop := errors.New("stop")
+	n := 0
+	err = ReadLocateDB(writeDB(t, validDB), func(path string, d fs.DirEntry) error {
+		if n++; n == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || n != 2 {
+		t.Errorf("stopped after %d paths with %v", n, err)
+	}
+}
+
+func TestReadLocateDBMalformed(t *testing.T) {
+	head := len(mlocateMagic) + 8
+	version := slices.Clone(validDB)
+	version[len(mlocateMagic)+4] = 1
+	hugeConf := slices.Clone(validDB)
+	binary.BigEndian.PutUint32(hugeConf[len(mlocateMagic):], 1<<31)
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "not an mlocate database"},
+		{"short header", validDB[:head-1], "not an mlocate database"},
+		{"magic", append([]byte("\x00slocate"), validDB[len(mlocateMagic):]...), "not an mlocate database"},
+		{"plocate", append([]byte(plocateMagic), validDB[len(mlocateMagic):]...), "plocate database"},
+		{"version", version, "unsupported mlocate database version 1"},
+		{"unterminated root", validDB[:head+3], "corrupt"},
+		{"configuration past the end", hugeConf, "corrupt"},
+		{"truncated directory", validDB[:len(validDB)-1], "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		err := ReadLocateDB(writeDB(t, tt.data), func(string, fs.DirEntry) error { return nil })
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	// Every truncation is reported, but where a directory record ends
+	ends := map[int]bool{}
+	for i := range validDirs {
+		ends[len(mlocateDB(validRoot, validConf, validDirs[:i]))] = true
+	}
+	for n := 0; n < len(validDB); n++ {
+		err := ReadLocateDB(writeDB(t, validDB[:n]), func(string, fs.DirEntry) error { return nil })
+		if (err == nil) != ends[n] {
+			t.Errorf("reading the first %d of %d bytes = %v", n, len(validDB), err)
+		}
+	}
+
+	if err := ReadLocateDB(filepath.Join(t.TempDir(), "missing"), nil); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing database = %v", err)
+	}
+}
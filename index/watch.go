@@ -0,0 +1,95 @@
+package index
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// watcher reports the directories under a root whose listings or entries
+// changed, slash separated and relative to the root. An empty path means
+// changes were lost and everything must be checked again.
+type watcher interface {
+	changes() <-chan string
+	errors() <-chan error
+	close() error
+}
+
+// HasNotifications reports whether Watch is driven by filesystem
+// notifications on this platform, rather than by checking the whole tree
+// at intervals
+func HasNotifications() bool {
+	return notified
+}
+
+// Most delays a change waits for before the index is updated, however many
+// changes follow it
+const maxDelayFactor = 10
+
+// Watch keeps the index up to date as the tree under its root changes,
+// until ctx is done. Changes are gathered until none came for the delay,
+// or at most for maxDelayFactor times the delay when they keep coming,
+// then only the directories they touched are read again, and onUpdate is
+// called with the new index, typically to save it; an error it returns
+// stops the watch. Notifications come from inotify on Linux and
+// ReadDirectoryChangesW on Windows. Elsewhere there are none to wait for,
+// and the index is brought up to date with Update at intervals of about
+// twice the delay instead.
+func (idx *Index) Watch(ctx context.Context, delay time.Duration, onUpdate func(*Index, Stats) error) error {
+	w, err := newWatcher(idx.Root, delay)
+	if err != nil {
+		return err
+	}
+	defer w.close()
+
+	// Changes made before the watch started are caught up with first
+	current, stats, err := idx.Update()
+	if err != nil {
+		return err
+	}
+	if err := onUpdate(current, stats); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(delay)
+	timer.Stop()
+	defer timer.Stop()
+	var first time.Time // when the first pending change came
+	pending := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-w.errors():
+			return err
+		case dir := <-w.changes():
+			now := time.Now()
+			if len(pending) == 0 {
+				first = now
+			}
+			pending[dir] = true
+			// A steady stream of changes is flushed all the same
+			timer.Reset(min(delay, first.Add(maxDelayFactor*delay).Sub(now)))
+		case <-timer.C:
+			var next *Index
+			if pending[""] {
+				next, stats, err = current.Update()
+			} else {
+				dirs := make([]string, 0, len(pending))
+				for dir := range pending {
+					dirs = append(dirs, dir)
+				}
+				sort.Strings(dirs)
+				next, stats, err = current.UpdateDirs(dirs)
+			}
+			clear(pending)
+			if err != nil {
+				return err
+			}
+			if err := onUpdate(next, stats); err != nil {
+				return err
+			}
+			current = next
+		}
+	}
+}
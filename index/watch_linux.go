@@ -0,0 +1,158 @@
+//go:build linux
+
+package index
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Watch is driven by inotify notifications
+const notified = true
+
+// Events changing a directory's listing or the entries in it
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO |
+	syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_CLOSE_WRITE | syscall.IN_DELETE_SELF |
+	syscall.IN_ONLYDIR | syscall.IN_DONT_FOLLOW
+
+// inotifyWatcher watches every directory of a tree with inotify, which
+// only watches single directories
+type inotifyWatcher struct {
+	root    string
+	fd      int
+	file    *os.File // the inotify descriptor, for reading it
+	mu      sync.Mutex
+	dirs    map[int32]string // watch descriptor to relative path
+	changed chan string
+	errs    chan error
+	done    chan struct{}
+}
+
+func newWatcher(root string, _ time.Duration) (watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	// Non-blocking, the file is read through the poller, so closing it
+	// stops the reader
+	w := &inotifyWatcher{
+		root:    root,
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		dirs:    make(map[int32]string),
+		changed: make(chan string),
+		errs:    make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	if err := w.addTree("."); err != nil {
+		w.file.Close()
+		return nil, err
+	}
+	go w.read()
+	return w, nil
+}
+
+// addTree watches a directory and every directory below it
+func (w *inotifyWatcher) addTree(rel string) error {
+	return filepath.WalkDir(filepath.Join(w.root, filepath.FromSlash(rel)), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == w.root {
+				return err
+			}
+			return nil // gone or unreadable, as the index will find
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		wd, err := syscall.InotifyAddWatch(w.fd, p, inotifyMask)
+		if errors.Is(err, syscall.ENOSPC) {
+			return fmt.Errorf("too many directories to watch under %s, see fs.inotify.max_user_watches", w.root)
+		}
+		if err != nil {
+			return nil
+		}
+		sub, _ := filepath.Rel(w.root, p)
+		w.mu.Lock()
+		w.dirs[int32(wd)] = filepath.ToSlash(sub)
+		w.mu.Unlock()
+		return nil
+	})
+}
+
+// read turns the events read into changed directories
+func (w *inotifyWatcher) read() {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				w.fail(err)
+			}
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			name := string(buf[nameStart : nameStart+int(event.Len)])
+			offset = nameStart + int(event.Len)
+			for len(name) > 0 && name[len(name)-1] == 0 {
+				name = name[:len(name)-1]
+			}
+
+			if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				w.send("")
+				continue
+			}
+			w.mu.Lock()
+			dir, ok := w.dirs[event.Wd]
+			if event.Mask&syscall.IN_IGNORED != 0 {
+				delete(w.dirs, event.Wd)
+			}
+			w.mu.Unlock()
+			if !ok || event.Mask&syscall.IN_IGNORED != 0 {
+				continue
+			}
+			// New directories are watched too. Being new to the index,
+			// they are read in full, with anything added before the watch.
+			if event.Mask&syscall.IN_ISDIR != 0 && event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+				if err := w.addTree(path.Join(dir, name)); err != nil {
+					w.fail(err)
+					return
+				}
+			}
+			w.send(dir)
+		}
+	}
+}
+
+// send reports a changed directory, unless the watch was closed
+func (w *inotifyWatcher) send(dir string) {
+	select {
+	case w.changed <- dir:
+	case <-w.done:
+	}
+}
+
+// fail reports the error ending the watch
+func (w *inotifyWatcher) fail(err error) {
+	select {
+	case w.errs <- err:
+	case <-w.done:
+	}
+}
+
+func (w *inotifyWatcher) changes() <-chan string { return w.changed }
+func (w *inotifyWatcher) errors() <-chan error   { return w.errs }
+
+func (w *inotifyWatcher) close() error {
+	close(w.done)
+	return w.file.Close()
+}
@@ -0,0 +1,91 @@
+//go:build linux
+
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitChange reads the changes of the watcher until dir is reported
+func waitChange(t *testing.T, w watcher, dir string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case got := <-w.changes():
+			if got == dir {
+				return
+			}
+		case err := <-w.errors():
+			t.Fatal(err)
+		case <-timeout:
+			t.Fatalf("no change reported in %q", dir)
+		}
+	}
+}
+
+// waitWatched waits until n directories are watched, reading changes
+// meanwhile as the watcher sends them before it goes on
+func waitWatched(t *testing.T, w watcher, n int) {
+	t.Helper()
+	iw := w.(*inotifyWatcher)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		iw.mu.Lock()
+		watched := len(iw.dirs)
+		iw.mu.Unlock()
+		if watched == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d directories watched, want %d", watched, n)
+		}
+		select {
+		case <-w.changes():
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestInotifyWatcher(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "old", "deep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	w, err := newWatcher(root, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+
+	// Directories present when the watch started are watched
+	if err := os.WriteFile(filepath.Join(root, "old", "deep", "f"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitChange(t, w, "old/deep")
+	if err := os.WriteFile(filepath.Join(root, "top"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitChange(t, w, ".")
+
+	// So are the directories created since, however deep
+	if err := os.MkdirAll(filepath.Join(root, "new", "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	waitChange(t, w, ".")
+	waitWatched(t, w, 6)
+	if err := os.WriteFile(filepath.Join(root, "new", "a", "b", "f"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitChange(t, w, "new/a/b")
+
+	// Removed directories stop being watched, and are reported in their parent
+	if err := os.RemoveAll(filepath.Join(root, "old")); err != nil {
+		t.Fatal(err)
+	}
+	waitChange(t, w, ".")
+	waitWatched(t, w, 4)
+}
@@ -0,0 +1,44 @@
+//go:build !linux && !windows
+
+package index
+
+import "time"
+
+// Watch polls, there being no notifications
+const notified = false
+
+// pollWatcher stands in for notifications on platforms without a
+// supported API, asking for everything to be checked at each interval
+type pollWatcher struct {
+	ticker  *time.Ticker
+	changed chan string
+	done    chan struct{}
+}
+
+func newWatcher(_ string, interval time.Duration) (watcher, error) {
+	w := &pollWatcher{ticker: time.NewTicker(interval), changed: make(chan string), done: make(chan struct{})}
+	go func() {
+		for {
+			select {
+			case <-w.ticker.C:
+			case <-w.done:
+				return
+			}
+			select {
+			case w.changed <- "":
+			case <-w.done:
+				return
+			}
+		}
+	}()
+	return w, nil
+}
+
+func (w *pollWatcher) changes() <-chan string { return w.changed }
+func (w *pollWatcher) errors() <-chan error   { return nil }
+
+func (w *pollWatcher) close() error {
+	w.ticker.Stop()
+	close(w.done)
+	return nil
+}
@@ -0,0 +1,129 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// hasEntry reports whether the index holds the slash path
+func hasEntry(idx *Index, path string) bool {
+	for _, e := range idx.Entries {
+		if e.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWatch(t *testing.T) {
+	root := t.TempDir()
+	idx, _, err := Build(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Made after the index was built, before the watch
+	if err := os.WriteFile(filepath.Join(root, "early"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan *Index)
+	done := make(chan error)
+	go func() {
+		done <- idx.Watch(ctx, 20*time.Millisecond, func(next *Index, _ Stats) error {
+			select {
+			case updates <- next:
+			case <-ctx.Done():
+			}
+			return nil
+		})
+	}()
+	wait := func(path string) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case next := <-updates:
+				if hasEntry(next, path) {
+					return
+				}
+			case err := <-done:
+				t.Fatalf("watch ended with %v", err)
+			case <-timeout:
+				t.Fatalf("%s never indexed", path)
+			}
+		}
+	}
+	wait("early")
+
+	if err := os.MkdirAll(filepath.Join(root, "sub", "deeper"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sub", "deeper", "late"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wait("sub/deeper/late")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("cancelled watch ended with %v", err)
+	}
+}
+
+func TestWatchStopsOnUpdateError(t *testing.T) {
+	idx, _, err := Build(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	errSave := errors.New("cannot save")
+	err = idx.Watch(context.Background(), time.Millisecond, func(*Index, Stats) error { return errSave })
+	if !errors.Is(err, errSave) {
+		t.Errorf("watch ended with %v, want %v", err, errSave)
+	}
+}
+
+func TestWatchSteadyChanges(t *testing.T) {
+	// Changes coming faster than the delay are flushed all the same
+	if !HasNotifications() {
+		t.Skip("no filesystem notifications")
+	}
+	root := t.TempDir()
+	idx, _, err := Build(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	const delay = 50 * time.Millisecond
+	updated := make(chan *Index, 100)
+	go idx.Watch(ctx, delay, func(next *Index, _ Stats) error {
+		updated <- next
+		return nil
+	})
+	<-updated // the catch up
+
+	ticker := time.NewTicker(delay / 5)
+	defer ticker.Stop()
+	timeout := time.After(maxDelayFactor * delay * 10)
+	for i := 0; ; i++ {
+		select {
+		case next := <-updated:
+			if !hasEntry(next, "f0") {
+				t.Errorf("update without the first change")
+			}
+			return
+		case <-ticker.C:
+			if err := os.WriteFile(filepath.Join(root, fmt.Sprintf("f%d", i%10)), []byte{byte(i)}, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		case <-timeout:
+			t.Fatal("no update while the changes kept coming")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+//go:build windows
+
+package index
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Watch is driven by ReadDirectoryChangesW notifications
+const notified = true
+
+// Changes to names, sizes, attributes and mtimes anywhere below the root
+const watchFilter = syscall.FILE_NOTIFY_CHANGE_FILE_NAME | syscall.FILE_NOTIFY_CHANGE_DIR_NAME |
+	syscall.FILE_NOTIFY_CHANGE_ATTRIBUTES | syscall.FILE_NOTIFY_CHANGE_SIZE | syscall.FILE_NOTIFY_CHANGE_LAST_WRITE
+
+// dirWatcher watches a whole tree with ReadDirectoryChangesW
+type dirWatcher struct {
+	handle  syscall.Handle
+	changed chan string
+	errs    chan error
+	done    chan struct{}
+}
+
+func newWatcher(root string, _ time.Duration) (watcher, error) {
+	name, err := syscall.UTF16PtrFromString(root)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := syscall.CreateFile(name, syscall.FILE_LIST_DIRECTORY,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+	w := &dirWatcher{
+		handle:  handle,
+		changed: make(chan string),
+		errs:    make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	go w.read()
+	return w, nil
+}
+
+// read turns the changes read into the directories holding the changed
+// entries
+func (w *dirWatcher) read() {
+	buf := make([]byte, 64*1024)
+	for {
+		var n uint32
+		err := syscall.ReadDirectoryChanges(w.handle, &buf[0], uint32(len(buf)), true, watchFilter, &n, nil, 0)
+		if err != nil {
+			select {
+			case <-w.done:
+			case w.errs <- err:
+			}
+			return
+		}
+		// Nothing read means the changes didn't fit in the buffer
+		if n == 0 {
+			w.send("")
+			continue
+		}
+		for offset := uint32(0); ; {
+			info := (*syscall.FileNotifyInformation)(unsafe.Pointer(&buf[offset]))
+			name := unsafe.Slice(&info.FileName, info.FileNameLength/2)
+			w.send(path.Dir(filepath.ToSlash(syscall.UTF16ToString(name))))
+			if info.NextEntryOffset == 0 {
+				break
+			}
+			offset += info.NextEntryOffset
+		}
+	}
+}
+
+// send reports a changed directory, unless the watch was closed
+func (w *dirWatcher) send(dir string) {
+	select {
+	case w.changed <- dir:
+	case <-w.done:
+	}
+}
+
+func (w *dirWatcher) changes() <-chan string { return w.changed }
+func (w *dirWatcher) errors() <-chan error   { return w.errs }
+
+// close stops the watch, cancelling the pending read
+func (w *dirWatcher) close() error {
+	close(w.done)
+	syscall.CancelIoEx(w.handle, nil)
+	return syscall.CloseHandle(w.handle)
+}
@@ -0,0 +1,336 @@
+package query
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sean1832/go-search/search"
+)
+
+// Parse parses a query, evaluating durations in it relative to now
+func Parse(text string) (*Query, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	p := &parser{tokens: tokens, now: time.Now()}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s in query", p.tokens[p.pos])
+	}
+	return &Query{Expr: expr}, nil
+}
+
+// token is a parenthesis, a keyword or a term
+type token struct {
+	text   string
+	quoted bool // holds quotes, so it is never a keyword
+}
+
+func (t token) String() string {
+	return strconv.Quote(t.text)
+}
+
+// tokenize splits a query into parentheses and words, quotes keeping
+// spaces and parentheses inside words
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(text); {
+		switch c := text[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{text: string(c)})
+			i++
+		default:
+			var word strings.Builder
+			quoted := false
+			for i < len(text) && !strings.ContainsRune(" \t\n()", rune(text[i])) {
+				if q := text[i]; q == '"' || q == '\'' {
+					end := strings.IndexByte(text[i+1:], q)
+					if end < 0 {
+						return nil, fmt.Errorf("unterminated quote in query")
+					}
+					word.WriteString(text[i+1 : i+1+end])
+					i += end + 2
+					quoted = true
+					continue
+				}
+				word.WriteByte(text[i])
+				i++
+			}
+			tokens = append(tokens, token{text: word.String(), quoted: quoted})
+		}
+	}
+	return tokens, nil
+}
+
+// parser is a recursive descent parser over the tokens of a query:
+//
+//	or   = and { OR and }
+//	and  = not { [AND] not }
+//	not  = NOT not | "(" or ")" | term
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time
+}
+
+// keyword reports whether the next token is the keyword, consuming it
+func (p *parser) keyword(name string) bool {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, name) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) or() (Expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Expr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Terms side by side are ANDed, up to an OR or a closing parenthesis
+		if !p.keyword("AND") {
+			if p.pos == len(p.tokens) || p.tokens[p.pos].text == ")" && !p.tokens[p.pos].quoted ||
+				!p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, "OR") {
+				return left, nil
+			}
+		}
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) not() (Expr, error) {
+	if p.keyword("NOT") {
+		expr, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+	if p.pos == len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	if !tok.quoted && tok.text == "(" {
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos == len(p.tokens) || p.tokens[p.pos].text != ")" {
+			return nil, fmt.Errorf("missing ) in query")
+		}
+		p.pos++
+		return expr, nil
+	}
+	if !tok.quoted && (tok.text == ")" || isKeyword(tok.text)) {
+		return nil, fmt.Errorf("unexpected %s in query", tok)
+	}
+	return p.term(tok.text)
+}
+
+// isKeyword reports whether a word is one of the operators
+func isKeyword(word string) bool {
+	return strings.EqualFold(word, "AND") || strings.EqualFold(word, "OR") || strings.EqualFold(word, "NOT")
+}
+
+// Comparison operators, longest first so that >= isn't read as >
+var operators = []string{"!=", ">=", "<=", ":", "=", ">", "<"}
+
+// term parses a field, an operator and a value into a Term
+func (p *parser) term(text string) (*Term, error) {
+	end := 0
+	for end < len(text) && text[end] >= 'a' && text[end] <= 'z' {
+		end++
+	}
+	t := &Term{Field: text[:end]}
+	for _, op := range operators {
+		if strings.HasPrefix(text[end:], op) {
+			t.Op, t.Value = op, text[end+len(op):]
+			break
+		}
+	}
+	if t.Field == "" || t.Op == "" {
+		return nil, fmt.Errorf("invalid term %q in query, expected a field, an operator and a value", text)
+	}
+
+	var err error
+	switch t.Field {
+	case "name", "path":
+		err = t.compileGlob()
+	case "ext":
+		err = t.compileExt()
+	case "type":
+		err = t.compileType()
+	case "size":
+		err = t.compileSize()
+	case "mtime":
+		err = t.compileMtime(p.now)
+	default:
+		err = fmt.Errorf("unknown field: %s (expected name, path, ext, type, size or mtime)", t.Field)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s in query", err)
+	}
+	return t, nil
+}
+
+// compileGlob compiles a name or path term
+func (t *Term) compileGlob() error {
+	if t.Op != ":" && t.Op != "=" && t.Op != "!=" {
+		return fmt.Errorf("%s only supports :, = and !=", t.Field)
+	}
+	isPath := t.Field == "path"
+	re, err := globRegexp(t.Value)
+	if err != nil {
+		return fmt.Errorf("invalid glob: %s", t.Value)
+	}
+	negate := t.Op == "!="
+	t.test = func(e *entry) (bool, error) {
+		target := filepath.Base(e.path)
+		if isPath {
+			target = filepath.ToSlash(e.path)
+		}
+		return re.MatchString(target) != negate, nil
+	}
+	return nil
+}
+
+// compileExt compiles an ext term
+func (t *Term) compileExt() error {
+	if t.Op != ":" && t.Op != "=" && t.Op != "!=" {
+		return fmt.Errorf("ext only supports :, = and !=")
+	}
+	ext := "." + strings.TrimPrefix(t.Value, ".")
+	negate := t.Op == "!="
+	t.test = func(e *entry) (bool, error) {
+		return strings.EqualFold(filepath.Ext(e.path), ext) != negate, nil
+	}
+	return nil
+}
+
+// compileType compiles a type term from the kinds of search.ParseTypeSet
+func (t *Term) compileType() error {
+	if t.Op != ":" && t.Op != "=" && t.Op != "!=" {
+		return fmt.Errorf("type only supports :, = and !=")
+	}
+	set, err := search.ParseTypeSet(t.Value)
+	if err != nil {
+		return err
+	}
+	kinds := set.Kinds()
+	if kinds != set {
+		return fmt.Errorf("type only supports the kinds f, d, l, s and p")
+	}
+	negate := t.Op == "!="
+	t.test = func(e *entry) (bool, error) {
+		var kind search.TypeSet
+		switch mode := e.d.Type(); {
+		case mode.IsDir():
+			kind = search.TypeDirKind
+		case mode&fs.ModeSymlink != 0:
+			kind = search.TypeSymlinkKind
+		case mode&fs.ModeSocket != 0:
+			kind = search.TypeSocketKind
+		case mode&fs.ModeNamedPipe != 0:
+			kind = search.TypePipeKind
+		case mode.IsRegular():
+			kind = search.TypeFileKind
+		}
+		return (kinds&kind != 0) != negate, nil
+	}
+	return nil
+}
+
+// compileSize compiles a size term
+func (t *Term) compileSize() error {
+	op := t.Op
+	if op == ":" {
+		op = "="
+	}
+	size, err := search.ParseSize(t.Value)
+	if err != nil {
+		return err
+	}
+	t.test = func(e *entry) (bool, error) {
+		info, err := e.stat()
+		if err != nil {
+			return false, err
+		}
+		return compare(op, info.Size(), size), nil
+	}
+	return nil
+}
+
+// compileMtime compiles an mtime term. Durations compare the age of the
+// entry, so the operator is turned around to compare points in time.
+func (t *Term) compileMtime(now time.Time) error {
+	op := t.Op
+	if op != ">" && op != "<" && op != ">=" && op != "<=" {
+		return fmt.Errorf("mtime only supports >, <, >= and <=")
+	}
+	at, err := search.ParseTimeSpec(t.Value, now)
+	if err != nil {
+		return err
+	}
+	if !strings.ContainsAny(t.Value, "-:") {
+		op = map[string]string{">": "<", "<": ">", ">=": "<=", "<=": ">="}[op]
+	}
+	t.test = func(e *entry) (bool, error) {
+		info, err := e.stat()
+		if err != nil {
+			return false, err
+		}
+		return compare(op, info.ModTime().UnixNano(), at.UnixNano()), nil
+	}
+	return nil
+}
+
+// compare applies a comparison operator
+func compare(op string, a, b int64) bool {
+	switch op {
+	case ">":
+		return a > b
+	case "<":
+		return a < b
+	case ">=":
+		return a >= b
+	case "<=":
+		return a <= b
+	case "!=":
+		return a != b
+	}
+	return a == b
+}
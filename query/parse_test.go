@@ -0,0 +1,198 @@
+package query
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"name:*.go", "name:*.go"},
+		{"name:a name:b", "(name:a AND name:b)"},
+		{"name:a AND name:b AND name:c", "((name:a AND name:b) AND name:c)"},
+		{"name:a OR name:b name:c", "(name:a OR (name:b AND name:c))"},
+		{"name:a name:b OR name:c", "((name:a AND name:b) OR name:c)"},
+		{"NOT name:a AND name:b", "(NOT name:a AND name:b)"},
+		{"NOT NOT name:a", "NOT NOT name:a"},
+		{"(name:a OR name:b) name:c", "((name:a OR name:b) AND name:c)"},
+		{"name:a OR NOT (name:b OR name:c)", "(name:a OR NOT (name:b OR name:c))"},
+		{"name:a or name:b and not name:c", "(name:a OR (name:b AND NOT name:c))"},
+		{"size>=1k", "size>=1k"},
+		{"mtime<30d", "mtime<30d"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+		if got := q.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuoting(t *testing.T) {
+	tests := []struct {
+		query string
+		value string // of the single term
+		want  string
+	}{
+		{`name:"a b"`, "a b", `name:"a b"`},
+		{`name:'a b'`, "a b", `name:"a b"`},
+		{`name:'say "hi"'`, `say "hi"`, `name:'say "hi"'`},
+		{`name:"(x)"`, "(x)", `name:"(x)"`},
+		{`name:a"b c"d`, "ab cd", `name:"ab cd"`},
+		{`"name:OR"`, "OR", "name:OR"},
+		{`name:""`, "", `name:""`},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+		term, ok := q.Expr.(*Term)
+		if !ok {
+			t.Errorf("Parse(%q) = %s, want a single term", tt.query, q)
+			continue
+		}
+		if term.Value != tt.value {
+			t.Errorf("Parse(%q) value = %q, want %q", tt.query, term.Value, tt.value)
+		}
+		if got := q.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+		// The string form must parse back to the same query
+		back, err := Parse(q.String())
+		if err != nil || back.String() != q.String() {
+			t.Errorf("Parse(%q) does not read back: %v, %v", q.String(), back, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "empty query"},
+		{"   ", "empty query"},
+		{"name:a AND", "unexpected end of query"},
+		{"NOT", "unexpected end of query"},
+		{"(name:a", "missing ) in query"},
+		{"name:a)", `unexpected ")" in query`},
+		{"OR name:a", `unexpected "OR" in query`},
+		{"name:a OR OR name:b", `unexpected "OR" in query`},
+		{`name:"x`, "unterminated quote in query"},
+		{"size", `invalid term "size"`},
+		{"FOO:x", `invalid term "FOO:x"`},
+		{"color:red", "unknown field: color"},
+		{"name>x", "name only supports :, = and !="},
+		{"ext<go", "ext only supports :, = and !="},
+		{"type:q", "q"},
+		{"type:x", "type only supports the kinds f, d, l, s and p"},
+		{"size>abc", "invalid size: abc"},
+		{"mtime:1d", "mtime only supports >, <, >= and <="},
+		{"mtime<soon", "soon"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error containing %q", tt.query, tt.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	now := time.Now()
+	fsys := fstest.MapFS{
+		"src/main.go":      {Data: make([]byte, 2048), ModTime: now.Add(-time.Hour)},
+		"src/README.md":    {Data: []byte("hi"), ModTime: now.Add(-48 * time.Hour)},
+		"src/cache/a.log":  {Data: make([]byte, 20), ModTime: now.Add(-time.Hour)},
+		"src/old file.txt": {ModTime: now.Add(-60 * 24 * time.Hour)},
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"name:*.go", []string{"src/main.go"}},
+		{"name:readme.*", []string{"src/README.md"}},
+		{"name!=*.go type:f", []string{"src/README.md", "src/cache/a.log", "src/old file.txt"}},
+		{"path:*/cache/*", []string{"src/cache/a.log"}},
+		{"ext:go OR ext:.md", []string{"src/README.md", "src/main.go"}},
+		{"type:d", []string{"src", "src/cache"}},
+		{"size>1k", []string{"src/main.go"}},
+		{"size<=20 type:f NOT ext:txt", []string{"src/README.md", "src/cache/a.log"}},
+		{"mtime<1d type:f", []string{"src/cache/a.log", "src/main.go"}},
+		{"mtime>30d type:f", []string{"src/old file.txt"}},
+		{`name:"old file.txt"`, []string{"src/old file.txt"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+		var got []string
+		err = fs.WalkDir(fsys, "src", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ok, err := q.Match(path, d)
+			if ok {
+				got = append(got, path)
+			}
+			return err
+		})
+		if err != nil {
+			t.Errorf("query %q: %v", tt.query, err)
+			continue
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("query %q matched %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		glob  string
+		name  string
+		match bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "MAIN.GO", true},
+		{"*.go", "main.go.bak", false},
+		{"*", "a/b", true},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+		{"[abc].txt", "b.txt", true},
+		{"[!abc].txt", "b.txt", false},
+		{"[!abc].txt", "d.txt", true},
+		{"[a-c]*", "cat", true},
+		{"a[b", "a[b", true},
+		{"a.b", "axb", false},
+		{"(x)+", "(x)+", true},
+	}
+	for _, tt := range tests {
+		re, err := globRegexp(tt.glob)
+		if err != nil {
+			t.Errorf("globRegexp(%q): %v", tt.glob, err)
+			continue
+		}
+		if got := re.MatchString(tt.name); got != tt.match {
+			t.Errorf("globRegexp(%q) matching %q = %v, want %v", tt.glob, tt.name, got, tt.match)
+		}
+	}
+}
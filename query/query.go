@@ -0,0 +1,166 @@
+// Package query parses filter expressions combining the conditions of a
+// search, such as
+//
+//	name:*.log AND size>10M AND mtime<30d AND NOT path:*/cache/*
+//
+// into a tree of Expr that a Query evaluates against entries. A Query is a
+// search.Filter, so it plugs into a search with search.WithFilter.
+//
+// Terms are a field, an operator and a value, without spaces in between
+// unless the value is quoted:
+//
+//	name:GLOB, name!=GLOB  base name, case-insensitively
+//	path:GLOB, path!=GLOB  path as searched, * matching / too like find -path
+//	ext:EXT                extension, with or without the dot
+//	type:TYPES             kinds of entry: f, d, l, s or p, comma separated
+//	size OP N[kMG]         size, OP being =, >, <, >= or <=
+//	mtime OP SPEC          age for a duration (mtime<30d is newer than 30
+//	                       days), or modification time for a date
+//	                       (mtime>2024-01-01 is modified after it)
+//
+// Terms combine with AND, OR, NOT and parentheses, AND binding tighter than
+// OR. Terms side by side are ANDed.
+package query
+
+import (
+	"io/fs"
+	"regexp"
+	"strings"
+)
+
+// Expr is a node of a parsed query
+type Expr interface {
+	// String returns the node in the query syntax, fully parenthesized
+	String() string
+	match(e *entry) (bool, error)
+}
+
+// And matches entries both sides match
+type And struct {
+	Left, Right Expr
+}
+
+// Or matches entries either side matches
+type Or struct {
+	Left, Right Expr
+}
+
+// Not matches entries the expression doesn't
+type Not struct {
+	Expr Expr
+}
+
+// Term is a single condition on a field of entries
+type Term struct {
+	Field string // name, path, ext, type, size or mtime
+	Op    string // :, =, !=, >, <, >= or <=
+	Value string
+	test  func(e *entry) (bool, error)
+}
+
+func (a *And) String() string  { return "(" + a.Left.String() + " AND " + a.Right.String() + ")" }
+func (o *Or) String() string   { return "(" + o.Left.String() + " OR " + o.Right.String() + ")" }
+func (n *Not) String() string  { return "NOT " + n.Expr.String() }
+func (t *Term) String() string { return t.Field + t.Op + quote(t.Value) }
+
+func (a *And) match(e *entry) (bool, error) {
+	ok, err := a.Left.match(e)
+	if !ok || err != nil {
+		return false, err
+	}
+	return a.Right.match(e)
+}
+
+func (o *Or) match(e *entry) (bool, error) {
+	ok, err := o.Left.match(e)
+	if ok || err != nil {
+		return ok, err
+	}
+	return o.Right.match(e)
+}
+
+func (n *Not) match(e *entry) (bool, error) {
+	ok, err := n.Expr.match(e)
+	return !ok && err == nil, err
+}
+
+func (t *Term) match(e *entry) (bool, error) {
+	return t.test(e)
+}
+
+// quote quotes a value when it wouldn't read back as a single word
+func quote(value string) string {
+	switch {
+	case value != "" && !strings.ContainsAny(value, " \t\n()\"'"):
+		return value
+	case strings.Contains(value, `"`):
+		return "'" + value + "'"
+	}
+	return `"` + value + `"`
+}
+
+// Query is a parsed query, matching the entries its expression matches
+type Query struct {
+	Expr Expr
+}
+
+// Match evaluates the query for a path, implementing search.Filter. The
+// entry is only stat-ed when a term needs its size or mtime.
+func (q *Query) Match(path string, d fs.DirEntry) (bool, error) {
+	return q.Expr.match(&entry{path: path, d: d})
+}
+
+// String returns the query in the query syntax
+func (q *Query) String() string {
+	return q.Expr.String()
+}
+
+// entry is what terms are evaluated against, with its info read once
+type entry struct {
+	path string
+	d    fs.DirEntry
+	info fs.FileInfo
+}
+
+// stat returns the info of the entry
+func (e *entry) stat() (fs.FileInfo, error) {
+	if e.info == nil {
+		info, err := e.d.Info()
+		if err != nil {
+			return nil, err
+		}
+		e.info = info
+	}
+	return e.info, nil
+}
+
+// globRegexp compiles a glob into a case-insensitive regexp matching whole
+// strings, * and ? matching slashes too
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,118 @@
+package search
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Extended attributes holding the POSIX ACLs and SELinux context on Linux
+const (
+	aclAccessXattr  = "system.posix_acl_access"
+	aclDefaultXattr = "system.posix_acl_default"
+	seContextXattr  = "security.selinux"
+)
+
+// Tags of the entries of a POSIX ACL, from linux/posix_acl.h
+const (
+	aclUserObj  = 0x01
+	aclUser     = 0x02
+	aclGroupObj = 0x04
+	aclGroup    = 0x08
+	aclMask     = 0x10
+	aclOther    = 0x20
+)
+
+// ReadACL returns the POSIX ACL of the file at path in the short text
+// form of setfacl, such as "user::rw-,user:1000:r--,group::r--,mask::r--,
+// other::r--", followed by the default ACL of a directory, its entries
+// prefixed with "default:". Files with no more than their mode bits have
+// none.
+func ReadACL(path string) (string, error) {
+	if err := checkXattrSupport(); err != nil {
+		return "", err
+	}
+	var entries []string
+	for _, xattr := range []string{aclAccessXattr, aclDefaultXattr} {
+		value, err := getXattr(path, xattr)
+		if errors.Is(err, errXattrMissing) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		acl, err := parseACL(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", path, err)
+		}
+		for _, entry := range acl {
+			if xattr == aclDefaultXattr {
+				entry = "default:" + entry
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return strings.Join(entries, ","), nil
+}
+
+// parseACL decodes the extended attribute form of an ACL: a version,
+// then an entry of tag, permissions and id for each rule, little endian
+func parseACL(value []byte) ([]string, error) {
+	if len(value) < 4 || binary.LittleEndian.Uint32(value) != 2 || (len(value)-4)%8 != 0 {
+		return nil, errors.New("invalid POSIX ACL")
+	}
+	var entries []string
+	for rest := value[4:]; len(rest) > 0; rest = rest[8:] {
+		tag := binary.LittleEndian.Uint16(rest)
+		perm := binary.LittleEndian.Uint16(rest[2:])
+		id := strconv.FormatUint(uint64(binary.LittleEndian.Uint32(rest[4:])), 10)
+		var entry string
+		switch tag {
+		case aclUserObj:
+			entry = "user:"
+		case aclUser:
+			entry = "user:" + id
+		case aclGroupObj:
+			entry = "group:"
+		case aclGroup:
+			entry = "group:" + id
+		case aclMask:
+			entry = "mask:"
+		case aclOther:
+			entry = "other:"
+		default:
+			return nil, fmt.Errorf("invalid POSIX ACL tag: %#x", tag)
+		}
+		entries = append(entries, entry+":"+aclPerms(perm))
+	}
+	return entries, nil
+}
+
+// aclPerms formats the permission bits of an ACL entry as rwx
+func aclPerms(perm uint16) string {
+	b := []byte("---")
+	for i, c := range "rwx" {
+		if perm&(4>>i) != 0 {
+			b[i] = byte(c)
+		}
+	}
+	return string(b)
+}
+
+// ReadSecContext returns the SELinux security context of the file at
+// path, such as "system_u:object_r:user_home_t:s0", or "" without one
+func ReadSecContext(path string) (string, error) {
+	if err := checkXattrSupport(); err != nil {
+		return "", err
+	}
+	value, err := getXattr(path, seContextXattr)
+	if errors.Is(err, errXattrMissing) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(value), "\x00"), nil
+}
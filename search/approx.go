@@ -0,0 +1,70 @@
+package search
+
+import (
+	"strings"
+)
+
+// approxMatcher matches names within an edit distance of the pattern
+type approxMatcher struct {
+	pattern         []rune
+	isCaseSensitive bool
+	maxDistance     int
+}
+
+// NewApproxMatcher creates a matcher accepting names that turn into pattern
+// with at most maxDistance insertions, deletions or substitutions of a
+// character, such as confg.yaml for config.yaml. Closer names score higher.
+func NewApproxMatcher(pattern string, isCaseSensitive bool, maxDistance int) (Matcher, error) {
+	if !isCaseSensitive {
+		pattern = strings.ToLower(pattern)
+	}
+	return &approxMatcher{pattern: []rune(pattern), isCaseSensitive: isCaseSensitive, maxDistance: maxDistance}, nil
+}
+
+func (m *approxMatcher) Match(name string) bool {
+	_, ok := m.Score(name)
+	return ok
+}
+
+// Score rates a name by how many edits it is short of the limit, so an
+// exact match scores maxDistance and the farthest name accepted 0
+func (m *approxMatcher) Score(name string) (int, bool) {
+	if !m.isCaseSensitive {
+		name = strings.ToLower(name)
+	}
+	distance, ok := boundedDistance(m.pattern, []rune(name), m.maxDistance)
+	return m.maxDistance - distance, ok
+}
+
+// boundedDistance computes the Levenshtein distance between a and b,
+// giving up as soon as it must exceed limit
+func boundedDistance(a, b []rune, limit int) (int, bool) {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return 0, false
+	}
+	// Two rows of the table of distances between prefixes
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		best := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			best = min(best, cur[j])
+		}
+		// Distances never shrink down the table
+		if best > limit {
+			return 0, false
+		}
+		prev, cur = cur, prev
+	}
+	distance := prev[len(b)]
+	return distance, distance <= limit
+}
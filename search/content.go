@@ -0,0 +1,110 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// Number of leading bytes inspected when deciding if a file is binary
+const binarySniffLen = 8000
+
+// Longest line the content scanner will hold in memory
+const maxLineLen = 1024 * 1024
+
+// ContentMatch is a single line of a file that matched the content pattern
+type ContentMatch struct {
+	Path string
+	Line int
+	Text string
+}
+
+// NewContentPattern compiles the regular expression used for content search
+func NewContentPattern(pattern string, isCaseSensitive bool) (*regexp.Regexp, error) {
+	if !isCaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// ScanContent scans the streamed files for lines matching re using a fixed
+// pool of workers. Matches are streamed on the returned channel, which is
+// closed once every file has been scanned. Files that can't be read are
+// passed to onError, which may be nil.
+func ScanContent(files <-chan Match, re *regexp.Regexp, workers int, onError func(path string, err error)) <-chan ContentMatch {
+	return ScanContentThrottled(files, re, workers, nil, onError)
+}
+
+// ScanContentThrottled is ScanContent reading the files at the rate of the
+// throttle, in bytes per second
+func ScanContentThrottled(files <-chan Match, re *regexp.Regexp, workers int, throttle *Throttle, onError func(path string, err error)) <-chan ContentMatch {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make(chan ContentMatch)
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				if err := scanFile(path, re, throttle, results); err != nil && onError != nil {
+					onError(path, err)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for file := range files {
+			jobs <- file.Path
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// scanFile streams a single file line by line, sending every matching line
+func scanFile(path string, re *regexp.Regexp, throttle *Throttle, results chan<- ContentMatch) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(throttle.Reader(file), 64*1024)
+	if isBinary(reader) {
+		return nil // Binary files are silently skipped
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineLen)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if re.Match(line) {
+			results <- ContentMatch{Path: path, Line: lineNum, Text: string(line)}
+		}
+	}
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		return fmt.Errorf("line %d too long", lineNum+1)
+	}
+	return scanner.Err()
+}
+
+// isBinary reports whether the head of the buffered file contains a NUL byte
+func isBinary(reader *bufio.Reader) bool {
+	// Peek returns whatever is available when the file is shorter
+	head, _ := reader.Peek(binarySniffLen)
+	return bytes.IndexByte(head, 0) != -1
+}
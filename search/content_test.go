@@ -0,0 +1,115 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// scanAll runs ScanContent over the files and collects what it reports
+func scanAll(t *testing.T, paths []string, pattern string, caseSensitive bool) ([]ContentMatch, map[string]error) {
+	t.Helper()
+	re, err := NewContentPattern(pattern, caseSensitive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(chan Match)
+	go func() {
+		for _, path := range paths {
+			files <- Match{Path: path}
+		}
+		close(files)
+	}()
+	var mu sync.Mutex
+	errs := map[string]error{}
+	var matches []ContentMatch
+	for m := range ScanContent(files, re, 4, func(path string, err error) {
+		mu.Lock()
+		errs[path] = err
+		mu.Unlock()
+	}) {
+		matches = append(matches, m)
+	}
+	slices.SortFunc(matches, func(a, b ContentMatch) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return a.Line - b.Line
+	})
+	return matches, errs
+}
+
+func TestScanContent(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":   "first line\nTODO: fix\nlast line\n",
+		"b.txt":   "todo without a newline",
+		"crlf.go": "package x\r\n// TODO later\r\n",
+		"none.md": "nothing to see\n",
+		"bin.dat": "TODO\x00binary",
+		"empty":   "",
+	}
+	var paths []string
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	matches, errs := scanAll(t, paths, "todo", false)
+	if len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	want := []ContentMatch{
+		{filepath.Join(dir, "a.txt"), 2, "TODO: fix"},
+		{filepath.Join(dir, "b.txt"), 1, "todo without a newline"},
+		{filepath.Join(dir, "crlf.go"), 2, "// TODO later"}, // without the CR
+	}
+	if !slices.Equal(matches, want) {
+		t.Errorf("matches = %+v, want %+v", matches, want)
+	}
+
+	matches, _ = scanAll(t, paths, "TODO", true)
+	if len(matches) != 2 {
+		t.Errorf("case-sensitive search found %+v, want the two upper case lines", matches)
+	}
+}
+
+func TestScanContentBinaryAfterText(t *testing.T) {
+	// Only the head of a file is sniffed, a NUL past it doesn't count
+	path := filepath.Join(t.TempDir(), "late.txt")
+	data := "match\n" + strings.Repeat("x", binarySniffLen) + "\x00\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	matches, _ := scanAll(t, []string{path}, "match", false)
+	if len(matches) != 1 || matches[0].Line != 1 {
+		t.Errorf("matches = %+v, want line 1", matches)
+	}
+}
+
+func TestScanContentErrors(t *testing.T) {
+	dir := t.TempDir()
+	long := filepath.Join(dir, "long.txt")
+	data := "ok\n" + strings.Repeat("a", maxLineLen+1) + "\n"
+	if err := os.WriteFile(long, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+
+	matches, errs := scanAll(t, []string{long, missing}, "a|ok", false)
+	if len(matches) != 1 || matches[0].Text != "ok" {
+		t.Errorf("matches = %+v, want the line before the long one", matches)
+	}
+	if err := errs[long]; err == nil || !strings.Contains(err.Error(), "line 2 too long") {
+		t.Errorf("error for the long line = %v", err)
+	}
+	if err := errs[missing]; !os.IsNotExist(err) {
+		t.Errorf("error for the missing file = %v, want it not to exist", err)
+	}
+}
@@ -0,0 +1,149 @@
+package search
+
+import (
+	"hash"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Kinds of Difference between two trees
+const (
+	DiffAdded   = "added"   // only in the second tree
+	DiffRemoved = "removed" // only in the first tree
+	DiffChanged = "changed" // in both, with another type, size or content
+)
+
+// Difference is a path that differs between two trees, relative to their
+// roots. Old is the entry in the first tree and New the one in the
+// second, the missing one being left empty.
+type Difference struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Old  Match  `json:"-"`
+	New  Match  `json:"-"`
+}
+
+// DiffTrees compares the entries found under two roots by their paths
+// relative to them. Entries of either tree alone are added or removed, a
+// directory found on one side only standing for everything below it.
+// Entries in both are changed when their types or sizes differ, or when
+// newHash isn't nil, files of the same size whose checksums differ, which
+// are computed by a fixed pool of workers. Files that can't be read are
+// passed to onError, which may be nil, and left out.
+//
+// Differences are ordered by path.
+func DiffTrees(oldRoot string, oldMatches []Match, newRoot string, newMatches []Match, newHash func() hash.Hash, workers int, onError func(path string, err error)) []Difference {
+	if workers < 1 {
+		workers = 1
+	}
+	before := relativeMatches(oldRoot, oldMatches)
+	after := relativeMatches(newRoot, newMatches)
+
+	var diffs []Difference
+	var compare []Difference // same type and size, to be hashed
+	for rel, o := range before {
+		n, ok := after[rel]
+		switch {
+		case !ok:
+			diffs = append(diffs, Difference{Path: rel, Kind: DiffRemoved, Old: o})
+		case o.Type != n.Type || o.Type == TypeFile && o.Size != n.Size:
+			diffs = append(diffs, Difference{Path: rel, Kind: DiffChanged, Old: o, New: n})
+		case o.Type == TypeFile && newHash != nil:
+			compare = append(compare, Difference{Path: rel, Kind: DiffChanged, Old: o, New: n})
+		}
+	}
+	for rel, n := range after {
+		if _, ok := before[rel]; !ok {
+			diffs = append(diffs, Difference{Path: rel, Kind: DiffAdded, New: n})
+		}
+	}
+
+	// Hash both sides of the candidates in parallel
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan Difference)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range jobs {
+				if !sameContents(&d, newHash, onError) {
+					mu.Lock()
+					diffs = append(diffs, d)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, d := range compare {
+		jobs <- d
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return withoutNestedDiffs(diffs)
+}
+
+// relativeMatches keys matches by their slash separated path relative to
+// the root, leaving out the root itself
+func relativeMatches(root string, matches []Match) map[string]Match {
+	byPath := make(map[string]Match, len(matches))
+	for _, m := range matches {
+		rel, err := filepath.Rel(root, m.Path)
+		if err != nil || rel == "." {
+			continue
+		}
+		m.loadInfo()
+		byPath[filepath.ToSlash(rel)] = m
+	}
+	return byPath
+}
+
+// sameContents reports whether both files of a difference have the same
+// checksum, setting their hashes. Files that can't be read are reported
+// and count as the same.
+func sameContents(d *Difference, newHash func() hash.Hash, onError func(path string, err error)) bool {
+	for _, m := range []*Match{&d.Old, &d.New} {
+		sum, err := checksumFile(m.fsPath(), newHash, nil)
+		if err != nil {
+			if onError != nil {
+				onError(m.Path, err)
+			}
+			return true
+		}
+		m.Hash = sum
+	}
+	return d.Old.Hash == d.New.Hash
+}
+
+// withoutNestedDiffs drops the differences below a directory that is on
+// one side only, or replaced by another type of entry
+func withoutNestedDiffs(diffs []Difference) []Difference {
+	whole := make(map[string]bool)
+	for _, d := range diffs {
+		if d.Old.IsDir() || d.New.IsDir() {
+			whole[d.Path] = true
+		}
+	}
+	out := diffs[:0]
+	for _, d := range diffs {
+		if !insideAny(d.Path, whole) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// insideAny reports whether a slash separated path is below one of the
+// directories
+func insideAny(rel string, dirs map[string]bool) bool {
+	for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+		if dirs[dir] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package search
+
+import "errors"
+
+// LocalDrives returns the roots of the local drives, which only Windows has
+func LocalDrives() ([]string, error) {
+	return nil, errors.New("drives can only be listed on Windows")
+}
+
+// IsFileInUse reports false: only Windows refuses to open files another
+// process keeps open
+func IsFileInUse(err error) bool {
+	return false
+}
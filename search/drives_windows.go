@@ -0,0 +1,65 @@
+//go:build windows
+
+package search
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procGetLogicalDrives = kernel32.NewProc("GetLogicalDrives")
+	procGetDriveTypeW    = kernel32.NewProc("GetDriveTypeW")
+)
+
+// Drive types returned by GetDriveTypeW that are local and writable
+const (
+	driveRemovable = 2
+	driveFixed     = 3
+	driveRAMDisk   = 6
+)
+
+// ERROR_SHARING_VIOLATION, returned when opening a file another process
+// holds open without sharing it
+const errorSharingViolation syscall.Errno = 32
+
+// LocalDrives returns the roots of the local drives that are ready, such
+// as C:\, leaving out network shares and optical drives
+func LocalDrives() ([]string, error) {
+	mask, _, err := procGetLogicalDrives.Call()
+	if mask == 0 {
+		return nil, err
+	}
+	var drives []string
+	for i := range 26 {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		root := string(rune('A'+i)) + `:\`
+		rootp, err := syscall.UTF16PtrFromString(root)
+		if err != nil {
+			continue
+		}
+		kind, _, _ := procGetDriveTypeW.Call(uintptr(unsafe.Pointer(rootp)))
+		switch kind {
+		case driveRemovable, driveFixed, driveRAMDisk:
+		default:
+			continue
+		}
+		// Card readers without a card are listed but can't be read
+		if _, err := os.Stat(root); err != nil {
+			continue
+		}
+		drives = append(drives, root)
+	}
+	return drives, nil
+}
+
+// IsFileInUse reports whether err comes from a file another process keeps
+// locked, such as pagefile.sys, which can't be read while the system runs
+func IsFileInUse(err error) bool {
+	return errors.Is(err, errorSharingViolation)
+}
@@ -0,0 +1,59 @@
+package search
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+)
+
+// DiskUsage passes the streamed matches through with the Size of every
+// directory set to the total size of the files and links below it.
+// Directories are sized by a fixed pool of
+// workers, so the order of the matches is not kept. Entries that can't be
+// read are passed to onError, which may be nil, and left out of the total.
+func DiskUsage(matches <-chan Match, workers int, onError func(path string, err error)) <-chan Match {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make(chan Match)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for match := range matches {
+				if match.IsDir() {
+					match.Size = treeSize(match.fsPath(), onError)
+				}
+				results <- match
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// treeSize returns the total size of the entries below dir other than
+// directories, not following symbolic links
+func treeSize(dir string, onError func(path string, err error)) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			var info fs.FileInfo
+			if info, err = d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		if err != nil && onError != nil {
+			onError(path, err)
+		}
+		return nil
+	})
+	return total
+}
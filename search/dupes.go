@@ -0,0 +1,104 @@
+package search
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// FindDuplicates groups files with identical contents. Files are first
+// grouped by size, so only files sharing a size with another are hashed
+// (SHA-256), using a fixed pool of workers. Empty files and anything but
+// regular files are ignored. Files that can't be read are passed to
+// onError, which may be nil.
+//
+// Groups are ordered by size, largest first, and the files in each group
+// by path.
+func FindDuplicates(files []Match, workers int, onError func(path string, err error)) [][]Match {
+	if workers < 1 {
+		workers = 1
+	}
+
+	bySize := make(map[int64][]Match)
+	for _, file := range files {
+		file.loadInfo()
+		if file.Type == TypeFile && file.Size > 0 {
+			bySize[file.Size] = append(bySize[file.Size], file)
+		}
+	}
+	var candidates []Match
+	for _, group := range bySize {
+		if len(group) > 1 {
+			candidates = append(candidates, group...)
+		}
+	}
+
+	// Hash the candidates in parallel, keyed by size and digest
+	type key struct {
+		size int64
+		sum  [sha256.Size]byte
+	}
+	byHash := make(map[key][]Match)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan Match)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				sum, err := hashFile(file.Path)
+				if err != nil {
+					if onError != nil {
+						onError(file.Path, err)
+					}
+					continue
+				}
+				mu.Lock()
+				k := key{file.Size, sum}
+				byHash[k] = append(byHash[k], file)
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, file := range candidates {
+		jobs <- file
+	}
+	close(jobs)
+	wg.Wait()
+
+	var groups [][]Match
+	for _, group := range byHash {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].Path < group[j].Path })
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i][0].Size != groups[j][0].Size {
+			return groups[i][0].Size > groups[j][0].Size
+		}
+		return groups[i][0].Path < groups[j][0].Path
+	})
+	return groups
+}
+
+// hashFile returns the SHA-256 digest of a file's contents
+func hashFile(path string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	file, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
@@ -0,0 +1,60 @@
+package search
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+	"syscall"
+)
+
+// WalkError describes a path skipped because of an error, in a form fit
+// for machine-readable output
+type WalkError struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`          // the error without the path, e.g. "permission denied"
+	Errno  int    `json:"errno,omitempty"` // the system error number, when there is one
+	Err    error  `json:"-"`
+}
+
+// NewWalkError describes the error that made the search skip path
+func NewWalkError(path string, err error) WalkError {
+	e := WalkError{Path: path, Reason: err.Error(), Err: err}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		e.Reason = pathErr.Err.Error()
+	}
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		e.Errno = int(errno)
+	}
+	return e
+}
+
+func (e WalkError) Error() string {
+	return e.Path + ": " + e.Reason
+}
+
+func (e WalkError) Unwrap() error {
+	return e.Err
+}
+
+// skippedPaths collects the errors of a run as they are reported
+type skippedPaths struct {
+	mu     sync.Mutex
+	errors []WalkError
+}
+
+func (p *skippedPaths) add(e WalkError) {
+	p.mu.Lock()
+	p.errors = append(p.errors, e)
+	p.mu.Unlock()
+}
+
+// Errors returns the paths the run skipped because of an error so far, in
+// the order they were reported
+func (r *Run) Errors() []WalkError {
+	p := r.s.skipped
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]WalkError(nil), p.errors...)
+}
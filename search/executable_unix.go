@@ -0,0 +1,10 @@
+//go:build !windows
+
+package search
+
+import "io/fs"
+
+// isExecutable reports whether any execute permission bit is set
+func isExecutable(path string, mode fs.FileMode) bool {
+	return mode.Perm()&0o111 != 0
+}
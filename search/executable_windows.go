@@ -0,0 +1,28 @@
+//go:build windows
+
+package search
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isExecutable reports whether the file extension is listed in PATHEXT
+func isExecutable(path string, mode fs.FileMode) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return false
+	}
+	pathExt := os.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = ".com;.exe;.bat;.cmd"
+	}
+	for _, candidate := range strings.Split(strings.ToLower(pathExt), ";") {
+		if candidate == ext {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package search
+
+// getFileID is unsupported on this platform, leaving loop detection to the
+// symlink depth limit
+func getFileID(path string) (fileID, bool) {
+	return fileID{}, false
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+package search
+
+import (
+	"syscall"
+)
+
+// getFileID returns the volume serial number and file index of the file at path
+func getFileID(path string) (fileID, bool) {
+	info, ok := fileInformation(path)
+	if !ok {
+		return fileID{}, false
+	}
+	return idFromInformation(info), true
+}
+
+// fileInformation opens the file at path to read its handle information
+func fileInformation(path string) (syscall.ByHandleFileInformation, bool) {
+	var info syscall.ByHandleFileInformation
+	pathp, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return info, false
+	}
+	// Backup semantics are required to open a handle to a directory
+	handle, err := syscall.CreateFile(pathp, 0,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return info, false
+	}
+	defer syscall.CloseHandle(handle)
+
+	if err := syscall.GetFileInformationByHandle(handle, &info); err != nil {
+		return info, false
+	}
+	return info, true
+}
+
+// idFromInformation builds a fileID from handle information
+func idFromInformation(info syscall.ByHandleFileInformation) fileID {
+	return fileID{
+		device: uint64(info.VolumeSerialNumber),
+		inode:  uint64(info.FileIndexHigh)<<32 | uint64(info.FileIndexLow),
+	}
+}
@@ -0,0 +1,26 @@
+//go:build darwin || freebsd || netbsd
+
+package search
+
+import (
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time from an entry's stat data
+func accessTime(sys any) (time.Time, bool) {
+	stat, ok := sys.(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(stat.Atimespec.Unix()), true
+}
+
+// changeTime returns the last status change time from an entry's stat data
+func changeTime(sys any) (time.Time, bool) {
+	stat, ok := sys.(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(stat.Ctimespec.Unix()), true
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !openbsd && !dragonfly && !solaris && !illumos && !darwin && !freebsd && !netbsd && !windows
+
+package search
+
+import "time"
+
+// accessTime is unsupported on this platform
+func accessTime(sys any) (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// changeTime is unsupported on this platform
+func changeTime(sys any) (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package search
+
+import (
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time from an entry's file attributes
+func accessTime(sys any) (time.Time, bool) {
+	data, ok := sys.(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, data.LastAccessTime.Nanoseconds()), true
+}
+
+// changeTime is unavailable: Windows doesn't report a status change time
+// in file attributes
+func changeTime(sys any) (time.Time, bool) {
+	return time.Time{}, false
+}
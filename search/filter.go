@@ -0,0 +1,123 @@
+package search
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// Filter is a custom rule entries must pass to be returned, for checks the
+// built-in filters don't cover. It is only asked about entries matching the
+// pattern and the other filters, possibly from several goroutines at once.
+// Entries for which it returns an error are reported and left out.
+type Filter interface {
+	Match(path string, d fs.DirEntry) (bool, error)
+}
+
+// FilterFunc adapts a function to a Filter
+type FilterFunc func(path string, d fs.DirEntry) (bool, error)
+
+// Match calls f
+func (f FilterFunc) Match(path string, d fs.DirEntry) (bool, error) {
+	return f(path, d)
+}
+
+// passesCustomFilters reports whether a match passes every Filter given
+// with WithFilter
+func (s *Searcher) passesCustomFilters(match Match) bool {
+	for _, filter := range s.filters {
+		ok, err := filter.Match(match.fsPath(), match.DirEntry)
+		if err != nil {
+			s.reportError(match.Path, err)
+			return false
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// CommandFilter is a Filter answered by a long-running command. Each entry
+// is written to its stdin as a JSON line such as
+//
+//	{"path":"src/main.go","type":"file"}
+//
+// to which it answers with a line of its own on stdout: {"match":true} to
+// keep the entry, {"match":false} to leave it out, or {"error":"..."} when
+// it can't tell.
+type CommandFilter struct {
+	name  string
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	dec   *json.Decoder
+
+	mu sync.Mutex // one entry is asked about at a time
+}
+
+// filterRequest is the line a CommandFilter is sent for an entry
+type filterRequest struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// filterResponse is the line a CommandFilter answers with
+type filterResponse struct {
+	Match bool   `json:"match"`
+	Error string `json:"error,omitempty"`
+}
+
+// StartCommandFilter starts the command of a CommandFilter, which writes
+// its diagnostics to the stderr of the process
+func StartCommandFilter(name string, args ...string) (*CommandFilter, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("filter %s: %w", name, err)
+	}
+	return &CommandFilter{name: name, cmd: cmd, stdin: stdin, dec: json.NewDecoder(bufio.NewReader(stdout))}, nil
+}
+
+// Match asks the command about an entry
+func (f *CommandFilter) Match(path string, d fs.DirEntry) (bool, error) {
+	line, err := json.Marshal(filterRequest{Path: path, Type: entryType(d.Type())})
+	if err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.stdin.Write(append(line, '\n')); err != nil {
+		return false, fmt.Errorf("filter %s: %w", f.name, err)
+	}
+	var response filterResponse
+	if err := f.dec.Decode(&response); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = errors.New("the command exited")
+		}
+		return false, fmt.Errorf("filter %s: %w", f.name, err)
+	}
+	if response.Error != "" {
+		return false, fmt.Errorf("filter %s: %s", f.name, response.Error)
+	}
+	return response.Match, nil
+}
+
+// Close closes the stdin of the command and waits for it to exit
+func (f *CommandFilter) Close() error {
+	f.stdin.Close()
+	return f.cmd.Wait()
+}
@@ -0,0 +1,121 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Multipliers for the size suffixes accepted by --size
+var sizeUnits = map[string]int64{
+	"b": 1,
+	"k": 1024,
+	"m": 1024 * 1024,
+	"g": 1024 * 1024 * 1024,
+}
+
+// SizeFilter restricts matches by file size
+type SizeFilter struct {
+	op    byte // '+' larger than, '-' smaller than, '=' exactly
+	bytes int64
+}
+
+// ParseSizeFilter parses expressions such as "+10M", "-500k" or "4096"
+func ParseSizeFilter(expr string) (SizeFilter, error) {
+	filter := SizeFilter{op: '='}
+	value := expr
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		filter.op = value[0]
+		value = value[1:]
+	}
+	bytes, err := ParseSize(value)
+	if err != nil {
+		return filter, fmt.Errorf("invalid size: %s", expr)
+	}
+	filter.bytes = bytes
+	return filter, nil
+}
+
+// ParseSize parses a number of bytes with an optional b, k, M or G suffix,
+// such as "500k" or "256M"
+func ParseSize(value string) (int64, error) {
+	digits, unit := value, int64(1)
+	if n := len(value); n > 0 {
+		if multiplier, ok := sizeUnits[strings.ToLower(value[n-1:])]; ok {
+			digits, unit = value[:n-1], multiplier
+		}
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n < 0 || n > math.MaxInt64/unit {
+		return 0, fmt.Errorf("invalid size: %s", value)
+	}
+	return n * unit, nil
+}
+
+// Match reports whether size satisfies the filter
+func (f SizeFilter) Match(size int64) bool {
+	switch f.op {
+	case '+':
+		return size > f.bytes
+	case '-':
+		return size < f.bytes
+	default:
+		return size == f.bytes
+	}
+}
+
+// passesFilters reports whether a match satisfies every metadata filter
+func (s *Searcher) passesFilters(match Match) bool {
+	if len(s.sizeFilters) > 0 {
+		// Sizes are only meaningful for regular files
+		if match.Type != TypeFile {
+			return false
+		}
+		for _, filter := range s.sizeFilters {
+			if !filter.Match(match.Size) {
+				return false
+			}
+		}
+	}
+	for _, filter := range s.mtimeFilters {
+		if !filter.Match(match.ModTime) {
+			return false
+		}
+	}
+	// Entries whose stat data lacks the time can't be shown to satisfy
+	// the filter
+	if len(s.ctimeFilters) > 0 {
+		ctime, ok := changeTime(match.sys)
+		if !ok {
+			return false
+		}
+		for _, filter := range s.ctimeFilters {
+			if !filter.Match(ctime) {
+				return false
+			}
+		}
+	}
+	if len(s.atimeFilters) > 0 {
+		atime, ok := accessTime(match.sys)
+		if !ok {
+			return false
+		}
+		for _, filter := range s.atimeFilters {
+			if !filter.Match(atime) {
+				return false
+			}
+		}
+	}
+	for _, filter := range s.permFilters {
+		if !filter.Match(match.Mode) {
+			return false
+		}
+	}
+	for _, filter := range s.ownerFilters {
+		if !filter.match(match.sys) {
+			return false
+		}
+	}
+	return true
+}
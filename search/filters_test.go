@@ -0,0 +1,76 @@
+package search
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"4096", 4096, false},
+		{"10b", 10, false},
+		{"500k", 500 * 1024, false},
+		{"500K", 500 * 1024, false},
+		{"256M", 256 << 20, false},
+		{"2g", 2 << 30, false},
+		{"8589934591G", 8589934591 << 30, false},
+		{"8589934592G", 0, true}, // doesn't fit in an int64
+		{"9223372036854775807", math.MaxInt64, false},
+		{"9223372036854775808", 0, true},
+		{"", 0, true},
+		{"k", 0, true},
+		{"-1", 0, true},
+		{"+1", 1, false},
+		{"1.5M", 0, true},
+		{"10T", 0, true},
+		{"5kk", 0, true},
+		{" 5", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSize(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSizeFilter(t *testing.T) {
+	tests := []struct {
+		expr  string
+		size  int64
+		match bool
+	}{
+		{"+1k", 1025, true},
+		{"+1k", 1024, false},
+		{"-1k", 1023, true},
+		{"-1k", 1024, false},
+		{"1k", 1024, true},
+		{"1k", 1025, false},
+		{"0", 0, true},
+		{"+0", 0, false},
+	}
+	for _, tt := range tests {
+		filter, err := ParseSizeFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseSizeFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := filter.Match(tt.size); got != tt.match {
+			t.Errorf("ParseSizeFilter(%q) matching %d = %v, want %v", tt.expr, tt.size, got, tt.match)
+		}
+	}
+	for _, expr := range []string{"", "+", "--1k", "+-1", "1x", "+1.5k"} {
+		if _, err := ParseSizeFilter(expr); err == nil {
+			t.Errorf("ParseSizeFilter(%q) succeeded, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+//go:build ignore
+
+// gen_normalize writes normalize_tables.go from the UnicodeData.txt and
+// CompositionExclusions.txt files of the Unicode Character Database,
+// found in the directory given as the only argument:
+//
+//	go run gen_normalize.go /path/to/ucd
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: go run gen_normalize.go <ucd directory>")
+	}
+	dir := os.Args[1]
+
+	classes := make(map[rune]uint8)
+	decompositions := make(map[rune][]rune)
+	readFields(filepath.Join(dir, "UnicodeData.txt"), func(fields []string) {
+		r := parseRune(fields[0])
+		if class, _ := strconv.Atoi(fields[3]); class != 0 {
+			classes[r] = uint8(class)
+		}
+		// Tagged decompositions are compatibility ones, which NFC and NFD leave alone
+		if fields[5] != "" && !strings.HasPrefix(fields[5], "<") {
+			for _, code := range strings.Fields(fields[5]) {
+				decompositions[r] = append(decompositions[r], parseRune(code))
+			}
+		}
+	})
+
+	// Full composition exclusions: the listed ones, singletons and
+	// decompositions not starting with a starter
+	excluded := make(map[rune]bool)
+	readFields(filepath.Join(dir, "CompositionExclusions.txt"), func(fields []string) {
+		excluded[parseRune(fields[0])] = true
+	})
+	for r, d := range decompositions {
+		if len(d) == 1 || classes[r] != 0 || classes[d[0]] != 0 {
+			excluded[r] = true
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by gen_normalize.go from the Unicode Character Database; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package search")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// Canonical combining classes of the runes whose class isn't 0")
+	fmt.Fprintln(&buf, "var combiningClasses = map[rune]uint8{")
+	writeEntries(&buf, sortedKeys(classes), func(r rune) string {
+		return fmt.Sprintf("0x%04X: %d", r, classes[r])
+	})
+	fmt.Fprintln(&buf, "}")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// Canonical decompositions, one level deep")
+	fmt.Fprintln(&buf, "var canonicalDecompositions = map[rune]string{")
+	writeEntries(&buf, sortedKeys(decompositions), func(r rune) string {
+		var quoted strings.Builder
+		for _, d := range decompositions[r] {
+			if d > 0xFFFF {
+				fmt.Fprintf(&quoted, `\U%08X`, d)
+			} else {
+				fmt.Fprintf(&quoted, `\u%04X`, d)
+			}
+		}
+		return fmt.Sprintf("0x%04X: \"%s\"", r, quoted.String())
+	})
+	fmt.Fprintln(&buf, "}")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// Runes with a decomposition of two runes that NFC doesn't compose back")
+	fmt.Fprintln(&buf, "var compositionExclusions = map[rune]bool{")
+	var pairs []rune
+	for r := range excluded {
+		if len(decompositions[r]) == 2 {
+			pairs = append(pairs, r)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i] < pairs[j] })
+	writeEntries(&buf, pairs, func(r rune) string { return fmt.Sprintf("0x%04X: true", r) })
+	fmt.Fprintln(&buf, "}")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("normalize_tables.go", source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readFields calls fn with the semicolon separated fields of every line of
+// a UCD file, comments left out
+func readFields(path string, fn func(fields []string)) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		fn(fields)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parseRune(code string) rune {
+	n, err := strconv.ParseUint(code, 16, 32)
+	if err != nil {
+		log.Fatalf("invalid code point %q", code)
+	}
+	return rune(n)
+}
+
+func sortedKeys[V any](m map[rune]V) []rune {
+	keys := make([]rune, 0, len(m))
+	for r := range m {
+		keys = append(keys, r)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// writeEntries writes map entries several to a line
+func writeEntries(buf *bytes.Buffer, keys []rune, entry func(r rune) string) {
+	const perLine = 6
+	for i, r := range keys {
+		if i%perLine == 0 {
+			buf.WriteString("\t")
+		}
+		buf.WriteString(entry(r) + ",")
+		if i%perLine == perLine-1 || i == len(keys)-1 {
+			buf.WriteString("\n")
+		} else {
+			buf.WriteString(" ")
+		}
+	}
+}
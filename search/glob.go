@@ -0,0 +1,133 @@
+package search
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrBadGlob is returned for malformed glob patterns
+var ErrBadGlob = errors.New("syntax error in glob pattern")
+
+// compileGlob translates a glob pattern into an anchored regular expression.
+// On top of filepath.Match syntax (*, ?, [classes]) it supports "**" path
+// segments matching any number of directories and {a,b} brace alternatives.
+// Paths are matched in slash separated form. With capture set, runs of
+// literal text and character classes are wrapped in groups, so the parts
+// of a name they matched can be located.
+func compileGlob(pattern string, isCaseSensitive, capture bool) (*regexp.Regexp, error) {
+	var buf strings.Builder
+	if !isCaseSensitive {
+		buf.WriteString("(?i)")
+	}
+	buf.WriteString("^")
+
+	// Backslash is the path separator on Windows, so it can't be an escape
+	escapes := filepath.Separator != '\\'
+	if !escapes {
+		pattern = strings.ReplaceAll(pattern, `\`, "/")
+	}
+
+	// literal writes text matching itself, opening a group for each run;
+	// wildcard closes the run before anything else is written
+	inLiteral := false
+	literal := func(text string) {
+		if capture && !inLiteral {
+			buf.WriteString("(")
+			inLiteral = true
+		}
+		buf.WriteString(text)
+	}
+	wildcard := func(text string) {
+		if inLiteral {
+			buf.WriteString(")")
+			inLiteral = false
+		}
+		buf.WriteString(text)
+	}
+
+	braces := 0
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			atStart := i == 0 || pattern[i-1] == '/'
+			switch rest := pattern[i+2:]; {
+			case atStart && strings.HasPrefix(rest, "/"):
+				wildcard("(?:.*/)?") // "**/" matches zero or more directories
+				i += 2
+			case atStart && rest == "":
+				wildcard(".*") // trailing "/**" matches everything inside
+				i++
+			default:
+				wildcard("[^/]*") // "**" inside a segment acts like "*"
+				i++
+			}
+		case c == '*':
+			wildcard("[^/]*")
+		case c == '?':
+			wildcard("[^/]")
+		case c == '[':
+			end, class, err := globClass(pattern, i)
+			if err != nil {
+				return nil, err
+			}
+			wildcard("")
+			literal(class)
+			wildcard("") // each class is a group of its own
+			i = end
+		case c == '{':
+			braces++
+			wildcard("(?:")
+		case c == '}' && braces > 0:
+			braces--
+			wildcard(")")
+		case c == ',' && braces > 0:
+			wildcard("|")
+		case c == '\\' && escapes:
+			if i+1 >= len(pattern) {
+				return nil, ErrBadGlob
+			}
+			i++
+			literal(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			literal(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if braces > 0 {
+		return nil, ErrBadGlob
+	}
+	wildcard("$")
+	return regexp.Compile(buf.String())
+}
+
+// globClass translates the character class starting at pattern[start],
+// returning the index of its closing bracket and the regexp equivalent
+func globClass(pattern string, start int) (int, string, error) {
+	var buf strings.Builder
+	buf.WriteString("[")
+	i := start + 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		buf.WriteString("^")
+		i++
+	}
+	first := true
+	for ; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == ']' && !first:
+			buf.WriteString("]")
+			return i, buf.String(), nil
+		case c == '\\' && filepath.Separator != '\\' && i+1 < len(pattern):
+			i++
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case c == '-':
+			buf.WriteString("-")
+		default:
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+		first = false
+	}
+	return 0, "", ErrBadGlob
+}
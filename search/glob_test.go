@@ -0,0 +1,122 @@
+package search
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern       string
+		caseSensitive bool
+		name          string
+		match         bool
+	}{
+		{"*.go", false, "main.go", true},
+		{"*.go", false, "MAIN.GO", true},
+		{"*.go", true, "MAIN.GO", false},
+		{"*.go", false, "main.go.bak", false},
+		{"*.go", false, "cmd/main.go", false},
+		{"?.txt", false, "a.txt", true},
+		{"?.txt", false, "ab.txt", false},
+		{"?", false, "/", false},
+		{"[abc].txt", false, "b.txt", true},
+		{"[!abc].txt", false, "b.txt", false},
+		{"[^abc].txt", false, "d.txt", true},
+		{"[a-c]at", false, "bat", true},
+		{"[]]", false, "]", true},
+		{"a.b", false, "axb", false},
+		{"(x)+", false, "(x)+", true},
+		{"**/*.go", false, "main.go", true},
+		{"**/*.go", false, "a/b/main.go", true},
+		{"src/**", false, "src/a/b", true},
+		{"src/**", false, "srcx/a", false},
+		{"a/**/b", false, "a/b", true},
+		{"a/**/b", false, "a/x/y/b", true},
+		{"a**b", false, "a/b", false},
+		{"a**b", false, "axxb", true},
+		{"*.{go,md}", false, "readme.md", true},
+		{"*.{go,md}", false, "main.go", true},
+		{"*.{go,md}", false, "main.rs", false},
+		{"{a,b{c,d}}", false, "bd", true},
+		{",", false, ",", true},
+		{"}", false, "}", true},
+	}
+	for _, tt := range tests {
+		re, err := compileGlob(tt.pattern, tt.caseSensitive, false)
+		if err != nil {
+			t.Errorf("compileGlob(%q): %v", tt.pattern, err)
+			continue
+		}
+		if got := re.MatchString(tt.name); got != tt.match {
+			t.Errorf("compileGlob(%q) matching %q = %v, want %v", tt.pattern, tt.name, got, tt.match)
+		}
+	}
+}
+
+func TestCompileGlobErrors(t *testing.T) {
+	patterns := []string{"[abc", "*.{go,md", "{"}
+	if filepath.Separator != '\\' {
+		patterns = append(patterns, `abc\`)
+	}
+	for _, pattern := range patterns {
+		if _, err := compileGlob(pattern, false, false); !errors.Is(err, ErrBadGlob) {
+			t.Errorf("compileGlob(%q) error = %v, want ErrBadGlob", pattern, err)
+		}
+	}
+}
+
+func TestCompileGlobEscapes(t *testing.T) {
+	if filepath.Separator == '\\' {
+		t.Skip("backslash is the path separator")
+	}
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{`\*`, "*", true},
+		{`\*`, "a", false},
+		{`\[a]`, "[a]", true},
+		{`a\{b,c}`, "a{b,c}", true},
+	}
+	for _, tt := range tests {
+		re, err := compileGlob(tt.pattern, false, false)
+		if err != nil {
+			t.Errorf("compileGlob(%q): %v", tt.pattern, err)
+			continue
+		}
+		if got := re.MatchString(tt.name); got != tt.match {
+			t.Errorf("compileGlob(%q) matching %q = %v, want %v", tt.pattern, tt.name, got, tt.match)
+		}
+	}
+}
+
+func TestCompileGlobCapture(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		groups  []string
+	}{
+		{"*test*", "unittests.go", []string{"test"}},
+		{"ab*cd", "abxxcd", []string{"ab", "cd"}},
+		{"[ab]x", "bx", []string{"b", "x"}},
+	}
+	for _, tt := range tests {
+		re, err := compileGlob(tt.pattern, false, true)
+		if err != nil {
+			t.Errorf("compileGlob(%q): %v", tt.pattern, err)
+			continue
+		}
+		m := re.FindStringSubmatch(tt.name)
+		if m == nil {
+			t.Errorf("compileGlob(%q) doesn't match %q", tt.pattern, tt.name)
+			continue
+		}
+		if got := m[1:]; !slices.Equal(got, tt.groups) {
+			t.Errorf("compileGlob(%q) groups for %q = %q, want %q", tt.pattern, tt.name, got, tt.groups)
+		}
+	}
+}
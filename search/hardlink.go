@@ -0,0 +1,48 @@
+package search
+
+import "sync"
+
+// Files with several hard links tracked at most by --unique-inodes; files
+// beyond this are reported without checking for other links
+const maxTrackedInodes = 1 << 20
+
+// inodeSet remembers the files with several hard links reported so far, so
+// that each is reported only once. A file is forgotten as soon as all its
+// links have been seen, so only files with links yet to be found take up
+// memory.
+type inodeSet struct {
+	mu        sync.Mutex
+	remaining map[fileID]uint64 // links not seen yet
+}
+
+func newInodeSet() *inodeSet {
+	return &inodeSet{remaining: make(map[fileID]uint64)}
+}
+
+// first reports whether the match is the first link to its file seen.
+// Directories and files with a single link always are.
+func (s *inodeSet) first(match Match) bool {
+	if match.IsDir() {
+		return true
+	}
+	id, links, ok := hardLinkID(match)
+	if !ok || links < 2 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	remaining, seen := s.remaining[id]
+	switch {
+	case !seen:
+		if len(s.remaining) < maxTrackedInodes {
+			s.remaining[id] = links - 1
+		}
+		return true
+	case remaining <= 1:
+		delete(s.remaining, id)
+	default:
+		s.remaining[id] = remaining - 1
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package search
+
+// hardLinkID is unsupported on this platform, so every link is reported
+func hardLinkID(match Match) (fileID, uint64, bool) {
+	return fileID{}, 0, false
+}
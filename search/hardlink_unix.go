@@ -0,0 +1,14 @@
+//go:build unix
+
+package search
+
+import "syscall"
+
+// hardLinkID returns the device and inode of a match and its link count
+func hardLinkID(match Match) (fileID, uint64, bool) {
+	stat, ok := match.sys.(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, 0, false
+	}
+	return fileID{device: uint64(stat.Dev), inode: uint64(stat.Ino)}, uint64(stat.Nlink), true
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package search
+
+// hardLinkID returns the volume serial number and file index of a match
+// and its link count. The stat data doesn't include them on Windows, so
+// the file is opened.
+func hardLinkID(match Match) (fileID, uint64, bool) {
+	if match.Type != TypeFile {
+		return fileID{}, 0, false
+	}
+	info, ok := fileInformation(match.fsPath())
+	if !ok {
+		return fileID{}, 0, false
+	}
+	return idFromInformation(info), uint64(info.NumberOfLinks), true
+}
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+// Hash algorithms known to HashFunc
+var hashFuncs = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"md5":    md5.New,
+	"xxh64":  func() hash.Hash { return newXXH64() },
+}
+
+// HashFunc returns the constructor of the hash algorithm named sha256, md5
+// or xxh64, for WithHash
+func HashFunc(name string) (func() hash.Hash, error) {
+	newHash, ok := hashFuncs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash algorithm: %s (expected sha256, md5 or xxh64)", name)
+	}
+	return newHash, nil
+}
+
+// checksumFile returns the hex encoded checksum of a file's content, read
+// at the rate of the throttle
+func checksumFile(path string, newHash func() hash.Hash, throttle *Throttle) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := newHash()
+	if _, err := io.Copy(h, throttle.Reader(file)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
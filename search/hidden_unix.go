@@ -0,0 +1,13 @@
+//go:build !windows
+
+package search
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// isHidden reports whether the entry is a dotfile
+func isHidden(d fs.DirEntry) bool {
+	return strings.HasPrefix(d.Name(), ".")
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package search
+
+import (
+	"io/fs"
+	"strings"
+	"syscall"
+)
+
+// isHidden reports whether the entry is a dotfile or has the hidden
+// attribute set
+func isHidden(d fs.DirEntry) bool {
+	if strings.HasPrefix(d.Name(), ".") {
+		return true
+	}
+	// Entries read from the Master File Table carry their attributes
+	if e, ok := d.(interface{ fileAttributes() uint32 }); ok {
+		return e.fileAttributes()&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	if attrs, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return attrs.FileAttributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+	}
+	return false
+}
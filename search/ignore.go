@@ -0,0 +1,250 @@
+package search
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Per-directory files whose rules are honored while walking. The
+// .searchignore files hold rules for this tool alone.
+var ignoreFileNames = []string{".gitignore", ".ignore", ".searchignore"}
+
+// ignoreRule is a single parsed line of a gitignore-style file
+type ignoreRule struct {
+	segments []string // pattern split on "/"
+	negate   bool     // "!pattern" re-includes a previously ignored path
+	dirOnly  bool     // "pattern/" only matches directories
+	anchored bool     // pattern contains a "/" and is relative to its file
+}
+
+// parseIgnoreRules parses the lines of a gitignore-style file
+func parseIgnoreRules(lines []string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range lines {
+		line = trimIgnoreLine(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// A slash at the beginning or middle anchors the pattern
+		rule.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		rule.segments = strings.Split(line, "/")
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// trimIgnoreLine strips the line ending and unescaped trailing spaces
+func trimIgnoreLine(line string) string {
+	line = strings.TrimRight(line, "\r")
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-2] + " "
+	}
+	return line
+}
+
+// match reports whether the rule applies to rel, a slash separated path
+// relative to the directory holding the ignore file
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	if !r.anchored {
+		// Patterns without a slash match the name at any depth
+		matched, _ := path.Match(r.segments[0], parts[len(parts)-1])
+		return matched
+	}
+	return matchSegments(r.segments, parts)
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				return true // trailing "/**" matches everything inside
+			}
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], parts[0]); !matched {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// ignoreFrame holds the rules loaded from one directory
+type ignoreFrame struct {
+	dir   string
+	rules []ignoreRule
+}
+
+// ignoreStack holds the ignore rules in effect in a directory: its own
+// frame and those of its ancestors. Stacks are never modified, so the
+// directories being walked in parallel can share their parents' stacks.
+// The nil stack holds no rules.
+type ignoreStack struct {
+	frame  ignoreFrame
+	parent *ignoreStack
+}
+
+// newIgnoreStack creates a stack for root seeded with the user's global git
+// excludes, when ignore files are used, followed by the extra rules, which
+// apply relative to root
+func newIgnoreStack(root string, useIgnoreFiles bool, extra []ignoreRule) *ignoreStack {
+	var rules []ignoreRule
+	if useIgnoreFiles {
+		if path := globalExcludesFile(); path != "" {
+			rules = readIgnoreFile(path)
+		}
+	}
+	rules = append(rules, extra...)
+	if len(rules) == 0 {
+		return nil
+	}
+	return &ignoreStack{frame: ignoreFrame{dir: root, rules: rules}}
+}
+
+// Push returns the stack for dir, a child of the stack's directory, with
+// the ignore files found in dir loaded
+func (s *ignoreStack) Push(dir string) *ignoreStack {
+	var rules []ignoreRule
+	rules = append(rules, readIgnoreFile(filepath.Join(dir, ".git", "info", "exclude"))...)
+	for _, name := range ignoreFileNames {
+		rules = append(rules, readIgnoreFile(filepath.Join(dir, name))...)
+	}
+	if len(rules) == 0 {
+		return s
+	}
+	return &ignoreStack{frame: ignoreFrame{dir: dir, rules: rules}, parent: s}
+}
+
+// IsIgnored reports whether path, an entry of the stack's directory,
+// should be skipped
+func (s *ignoreStack) IsIgnored(path string, isDir bool) bool {
+	// Deeper files take precedence, and later rules win within a file
+	for ; s != nil; s = s.parent {
+		rel, ok := relativeTo(s.frame.dir, path)
+		if !ok {
+			continue
+		}
+		rules := s.frame.rules
+		for j := len(rules) - 1; j >= 0; j-- {
+			if rules[j].match(rel, isDir) {
+				return !rules[j].negate
+			}
+		}
+	}
+	return false
+}
+
+// relativeTo returns path relative to dir in slash form, if path is inside dir
+func relativeTo(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// readIgnoreFile parses an ignore file, returning nothing if it can't be read
+func readIgnoreFile(path string) []ignoreRule {
+	rules, _ := loadIgnoreFile(path)
+	return rules
+}
+
+// loadIgnoreFile parses an ignore file
+func loadIgnoreFile(path string) ([]ignoreRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return parseIgnoreRules(lines), nil
+}
+
+// globalExcludesFile locates git's core.excludesFile, falling back to the
+// default location under the XDG config directory
+func globalExcludesFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if path := gitConfigExcludesFile(filepath.Join(home, ".gitconfig")); path != "" {
+		if strings.HasPrefix(path, "~/") {
+			path = filepath.Join(home, path[2:])
+		}
+		return path
+	}
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "git", "ignore")
+}
+
+// gitConfigExcludesFile reads core.excludesFile from a git config file
+func gitConfigExcludesFile(configPath string) string {
+	file, err := os.Open(configPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	inCore := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inCore = strings.EqualFold(strings.Trim(line, "[] "), "core")
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if inCore && ok && strings.EqualFold(strings.TrimSpace(key), "excludesfile") {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,261 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeTree creates the files under root, a name ending in / being an
+// empty directory
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if name[len(name)-1] == '/' {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// searchPaths returns the sorted slash paths, relative to root, that a
+// search of root for pattern finds, leaving out the root itself
+func searchPaths(t *testing.T, root, pattern string, opts ...Option) []string {
+	t.Helper()
+	s, err := New(pattern, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err := s.Search(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, m := range matches {
+		rel, err := filepath.Rel(root, m.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rel != "." {
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// isolateGitConfig points the global git excludes at an empty home
+func isolateGitConfig(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	return home
+}
+
+func TestParseIgnoreRules(t *testing.T) {
+	rules := parseIgnoreRules([]string{
+		"# comment",
+		"",
+		"*.log",
+		"!keep.log",
+		`\!bang`,
+		`\#hash`,
+		"build/",
+		"/root-only",
+		"docs/*.md",
+		"trailing   ",
+		`space\ `,
+		"crlf\r",
+		"!",
+		"/",
+	})
+	want := []ignoreRule{
+		{segments: []string{"*.log"}},
+		{segments: []string{"keep.log"}, negate: true},
+		{segments: []string{"!bang"}},
+		{segments: []string{"#hash"}},
+		{segments: []string{"build"}, dirOnly: true},
+		{segments: []string{"root-only"}, anchored: true},
+		{segments: []string{"docs", "*.md"}, anchored: true},
+		{segments: []string{"trailing"}},
+		{segments: []string{"space "}},
+		{segments: []string{"crlf"}},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("parsed %d rules, want %d: %+v", len(rules), len(want), rules)
+	}
+	for i, rule := range rules {
+		w := want[i]
+		if !slices.Equal(rule.segments, w.segments) || rule.negate != w.negate || rule.dirOnly != w.dirOnly || rule.anchored != w.anchored {
+			t.Errorf("rule %d = %+v, want %+v", i, rule, w)
+		}
+	}
+}
+
+func TestIgnoreRuleMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		isDir   bool
+		want    bool
+	}{
+		{"*.log", "a.log", false, true},
+		{"*.log", "deep/down/a.log", false, true},
+		{"*.log", "a.log/x", false, false},
+		{"build/", "build", true, true},
+		{"build/", "build", false, false},
+		{"build/", "src/build", true, true},
+		{"/root-only", "root-only", false, true},
+		{"/root-only", "sub/root-only", false, false},
+		{"docs/*.md", "docs/a.md", false, true},
+		{"docs/*.md", "docs/sub/a.md", false, false},
+		{"docs/*.md", "x/docs/a.md", false, false},
+		{"**/cache", "cache", true, true},
+		{"**/cache", "a/b/cache", true, true},
+		{"a/**/b", "a/b", false, true},
+		{"a/**/b", "a/x/y/b", false, true},
+		{"a/**/b", "a/x/y/c", false, false},
+		{"logs/**", "logs/x/y", false, true},
+		{"[abc].txt", "b.txt", false, true},
+		{"?.txt", "ab.txt", false, false},
+	}
+	for _, tt := range tests {
+		rules := parseIgnoreRules([]string{tt.pattern})
+		if len(rules) != 1 {
+			t.Fatalf("%q parsed to %d rules", tt.pattern, len(rules))
+		}
+		if got := rules[0].match(tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("%q matching %q (dir %v) = %v, want %v", tt.pattern, tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestIgnoreFilesWhileWalking(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":           "*.log\n!keep.log\nbuild/\n/top.txt\n",
+		".ignore":              "*.tmp\n",
+		".searchignore":        "secret*\n",
+		".git/info/exclude":    "local.txt\n",
+		"top.txt":              "",
+		"keep.log":             "",
+		"drop.log":             "",
+		"local.txt":            "",
+		"secret.key":           "",
+		"scratch.tmp":          "",
+		"main.go":              "",
+		"build/out.bin":        "",
+		"src/top.txt":          "",
+		"src/app.log":          "",
+		"src/.gitignore":       "!app.log\n*.go\n",
+		"src/main.go":          "",
+		"src/nested/.ignore":   "!*.go\n",
+		"src/nested/inner.go":  "",
+		"src/nested/inner.tmp": "",
+		"src/build/x":          "",
+		"vendor/.gitignore":    "*\n!.gitignore\n",
+		"vendor/lib.go":        "",
+	})
+
+	got := searchPaths(t, root, "*", WithIgnoreFiles(true), WithJobs(4))
+	want := []string{
+		"keep.log",
+		"main.go",
+		"src",
+		"src/app.log", // re-included below the root
+		"src/nested",
+		"src/nested/inner.go", // re-included deeper still
+		"src/top.txt",         // the root rule was anchored
+		"vendor",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("found %q, want %q", got, want)
+	}
+
+	// Without ignore files, only hidden entries are left out
+	got = searchPaths(t, root, "*", WithIgnoreFiles(false))
+	if len(got) != 20 {
+		t.Errorf("without ignore files found %d paths, want 20: %q", len(got), got)
+	}
+}
+
+func TestIgnoredDirectoryIsNotEntered(t *testing.T) {
+	// A file can't be re-included once its directory is ignored, as in git
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":     "out/\n!out/keep.txt\n",
+		"out/keep.txt":   "",
+		"out/.gitignore": "!*\n",
+		"in.txt":         "",
+	})
+	if got := searchPaths(t, root, "*", WithIgnoreFiles(true)); !slices.Equal(got, []string{"in.txt"}) {
+		t.Errorf("found %q, want only in.txt", got)
+	}
+}
+
+func TestGlobalExcludes(t *testing.T) {
+	home := isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.swp":  "",
+		"a.txt":  "",
+		"b.orig": "",
+	})
+
+	// The default location, under the XDG config directory
+	writeTree(t, home, map[string]string{".config/git/ignore": "*.swp\n"})
+	if got := searchPaths(t, root, "*", WithIgnoreFiles(true)); !slices.Equal(got, []string{"a.txt", "b.orig"}) {
+		t.Errorf("with the default excludes file found %q", got)
+	}
+
+	// core.excludesFile takes its place
+	writeTree(t, home, map[string]string{
+		".gitconfig": "[user]\n\texcludesfile = wrong\n[core]\n\texcludesFile = \"~/excludes\"\n",
+		"excludes":   "*.orig\n",
+	})
+	if got := searchPaths(t, root, "*", WithIgnoreFiles(true)); !slices.Equal(got, []string{"a.swp", "a.txt"}) {
+		t.Errorf("with core.excludesFile found %q", got)
+	}
+	if got := searchPaths(t, root, "*", WithIgnoreFiles(false)); len(got) != 3 {
+		t.Errorf("without ignore files found %q", got)
+	}
+}
+
+func TestExtraIgnoreFile(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	rules := filepath.Join(t.TempDir(), "rules")
+	writeTree(t, root, map[string]string{
+		".gitignore": "*.log\n",
+		"a.log":      "",
+		"a.txt":      "",
+		"sub/a.txt":  "",
+		"sub/b.txt":  "",
+	})
+	if err := os.WriteFile(rules, []byte("/a.txt\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The rules apply relative to the root, even without the ignore files
+	got := searchPaths(t, root, "*", WithIgnoreFiles(false), WithIgnoreFile(rules))
+	if want := []string{"a.log", "sub", "sub/a.txt", "sub/b.txt"}; !slices.Equal(got, want) {
+		t.Errorf("found %q, want %q", got, want)
+	}
+	got = searchPaths(t, root, "*", WithIgnoreFiles(true), WithIgnoreFile(rules))
+	if want := []string{"sub", "sub/a.txt", "sub/b.txt"}; !slices.Equal(got, want) {
+		t.Errorf("with ignore files found %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,234 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Number of leading bytes read to find a shebang or modeline
+const langSniffLen = 1024
+
+// Number of leading lines looked at for a modeline
+const modelineLines = 5
+
+// language describes how the source files of a programming language are
+// recognized
+type language struct {
+	extensions   []string // lowercase, without the dot
+	interpreters []string // named by a shebang, without any version suffix
+	modes        []string // names used by vim and emacs modelines
+}
+
+// Languages known to DetectLanguage
+var languages = map[string]language{
+	"c":          {extensions: []string{"c", "h"}},
+	"cpp":        {extensions: []string{"cc", "cpp", "cxx", "c++", "hh", "hpp", "hxx"}, modes: []string{"c++"}},
+	"go":         {extensions: []string{"go"}},
+	"java":       {extensions: []string{"java"}},
+	"javascript": {extensions: []string{"js", "mjs", "cjs", "jsx"}, interpreters: []string{"node", "nodejs"}, modes: []string{"js"}},
+	"lua":        {extensions: []string{"lua"}, interpreters: []string{"lua", "luajit"}},
+	"perl":       {extensions: []string{"pl", "pm"}, interpreters: []string{"perl"}},
+	"php":        {extensions: []string{"php"}, interpreters: []string{"php"}},
+	"python":     {extensions: []string{"py", "pyw", "pyi"}, interpreters: []string{"python", "pypy"}},
+	"ruby":       {extensions: []string{"rb"}, interpreters: []string{"ruby"}},
+	"rust":       {extensions: []string{"rs"}},
+	"shell": {
+		extensions:   []string{"sh", "bash", "zsh", "ksh"},
+		interpreters: []string{"sh", "bash", "zsh", "ksh", "dash", "ash"},
+		modes:        []string{"sh", "bash", "zsh", "shell-script"},
+	},
+	"typescript": {extensions: []string{"ts", "tsx", "mts", "cts"}, interpreters: []string{"ts-node", "tsx"}, modes: []string{"ts"}},
+}
+
+// Languages returns the names of the languages DetectLanguage knows, sorted
+func Languages() []string {
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// languageOfName returns the language of a file name by its extension
+func languageOfName(name string) string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	if ext == "" {
+		return ""
+	}
+	for lang, l := range languages {
+		for _, e := range l.extensions {
+			if e == ext {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// DetectLanguage returns the programming language of a source file, or ""
+// when it isn't recognized. Files are recognized by their extension, and
+// otherwise by a shebang such as #!/usr/bin/env python3 or by a vim or emacs
+// modeline in their first lines.
+func DetectLanguage(name string) (string, error) {
+	if lang := languageOfName(name); lang != "" {
+		return lang, nil
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, langSniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return sniffLanguage(head[:n]), nil
+}
+
+// sniffLanguage finds the language named by the shebang or a modeline
+// at the start of a file's content
+func sniffLanguage(head []byte) string {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return "" // binary
+	}
+	lines := strings.SplitN(string(head), "\n", modelineLines+1)
+	if len(lines) > modelineLines {
+		lines = lines[:modelineLines]
+	}
+	if lang := shebangLanguage(lines[0]); lang != "" {
+		return lang
+	}
+	for _, line := range lines {
+		if mode := modelineMode(line); mode != "" {
+			return languageOfMode(mode)
+		}
+	}
+	return ""
+}
+
+// shebangLanguage returns the language of the interpreter a shebang line
+// runs, looking through env and its options
+func shebangLanguage(line string) string {
+	rest, ok := strings.CutPrefix(line, "#!")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "-") || strings.Contains(field, "=") {
+				continue
+			}
+			interpreter = filepath.Base(field)
+			break
+		}
+	}
+	// python3.12 is run by python, lua5.4 by lua
+	interpreter = strings.TrimRight(interpreter, "0123456789.")
+	for lang, l := range languages {
+		for _, name := range l.interpreters {
+			if name == interpreter {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// modelineMode returns the file type set by a vim modeline, such as
+// "# vim: set ft=python:", or the mode of an emacs one, such as
+// "# -*- mode: python -*-"
+func modelineMode(line string) string {
+	if _, rest, ok := strings.Cut(line, "-*-"); ok {
+		if body, _, ok := strings.Cut(rest, "-*-"); ok {
+			// -*- python -*- names the mode alone
+			if !strings.Contains(body, ":") {
+				return strings.ToLower(strings.TrimSpace(body))
+			}
+			for _, part := range strings.Split(body, ";") {
+				key, value, _ := strings.Cut(part, ":")
+				if strings.EqualFold(strings.TrimSpace(key), "mode") {
+					return strings.ToLower(strings.TrimSpace(value))
+				}
+			}
+		}
+	}
+	for _, marker := range []string{"vim:", "vi:", "ex:"} {
+		i := strings.Index(line, marker)
+		if i < 0 || i > 0 && line[i-1] != ' ' && line[i-1] != '\t' {
+			continue
+		}
+		settings := strings.FieldsFunc(line[i+len(marker):], func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ':'
+		})
+		for _, setting := range settings {
+			key, value, _ := strings.Cut(setting, "=")
+			switch key {
+			case "ft", "filetype", "syntax":
+				return strings.ToLower(value)
+			}
+		}
+	}
+	return ""
+}
+
+// languageOfMode maps a modeline file type or mode to a language
+func languageOfMode(mode string) string {
+	if _, ok := languages[mode]; ok {
+		return mode
+	}
+	for lang, l := range languages {
+		for _, name := range l.modes {
+			if name == mode {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// ParseLanguage parses the name of a language DetectLanguage knows,
+// case-insensitively
+func ParseLanguage(name string) (string, error) {
+	lang := strings.ToLower(name)
+	if _, ok := languages[lang]; !ok {
+		return "", fmt.Errorf("unknown language: %s (expected %s)", name, strings.Join(Languages(), ", "))
+	}
+	return lang, nil
+}
+
+// passesLanguages reports whether a match is a source file in any of the
+// languages. Files without a known extension are read for a shebang or
+// modeline.
+func (s *Searcher) passesLanguages(match Match) bool {
+	if len(s.languages) == 0 {
+		return true
+	}
+	if match.Type != TypeFile {
+		return false
+	}
+	lang, err := DetectLanguage(match.fsPath())
+	if err != nil {
+		s.reportError(match.Path, err)
+		return false
+	}
+	for _, name := range s.languages {
+		if name == lang {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package search
+
+import "io/fs"
+
+// asLink returns the entry as is: only Windows has links that aren't
+// reported as symlinks
+func asLink(path string, d fs.DirEntry) fs.DirEntry {
+	return d
+}
+
+// isSystemLink returns false: only Windows has system junctions
+func isSystemLink(path string) bool {
+	return false
+}
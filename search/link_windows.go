@@ -0,0 +1,82 @@
+//go:build windows
+
+package search
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// Bit of reparse tags whose reparse point stands in for another named
+// entity, set for symlinks and junctions alike
+const reparseTagNameSurrogate = 0x20000000
+
+// asLink presents junctions, and other reparse points standing in for
+// another path, as symlinks. Go reports them as irregular files, neither
+// directories nor links, so they would neither be followed with --follow
+// nor found with -t l.
+func asLink(path string, d fs.DirEntry) fs.DirEntry {
+	if d.Type()&fs.ModeIrregular == 0 || !isNameSurrogate(path) {
+		return d
+	}
+	return linkEntry{d}
+}
+
+// isNameSurrogate reports whether path is a name surrogate reparse point,
+// whose tag only the directory listing reports
+func isNameSurrogate(path string) bool {
+	data, ok := findData(path)
+	return ok && data.FileAttributes&syscall.FILE_ATTRIBUTE_REPARSE_POINT != 0 &&
+		data.Reserved0&reparseTagNameSurrogate != 0
+}
+
+// isSystemLink reports whether path is a reparse point with the system
+// attribute, such as the Documents and Settings junction kept for old
+// programs, which denies listing and leads back into the tree anyway
+func isSystemLink(path string) bool {
+	data, ok := findData(path)
+	return ok && data.FileAttributes&syscall.FILE_ATTRIBUTE_REPARSE_POINT != 0 &&
+		data.FileAttributes&syscall.FILE_ATTRIBUTE_SYSTEM != 0
+}
+
+// findData returns the directory listing data of path
+func findData(path string) (syscall.Win32finddata, bool) {
+	var data syscall.Win32finddata
+	pathp, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return data, false
+	}
+	handle, err := syscall.FindFirstFile(pathp, &data)
+	if err != nil {
+		return data, false
+	}
+	syscall.FindClose(handle)
+	return data, true
+}
+
+// linkEntry is a directory entry reported as a symlink
+type linkEntry struct {
+	fs.DirEntry
+}
+
+func (e linkEntry) IsDir() bool       { return false }
+func (e linkEntry) Type() fs.FileMode { return fs.ModeSymlink }
+
+func (e linkEntry) Info() (fs.FileInfo, error) {
+	info, err := e.DirEntry.Info()
+	if err != nil {
+		return nil, err
+	}
+	return linkInfo{info}, nil
+}
+
+// linkInfo is file info reported as a symlink's
+type linkInfo struct {
+	fs.FileInfo
+}
+
+func (i linkInfo) IsDir() bool { return false }
+
+func (i linkInfo) Mode() fs.FileMode {
+	return i.FileInfo.Mode()&^fs.ModeType | fs.ModeSymlink
+}
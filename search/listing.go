@@ -0,0 +1,42 @@
+package search
+
+import (
+	"io/fs"
+	"os"
+	"time"
+)
+
+// ListingCache keeps directory listings between searches. A listing is
+// only valid while the directory's mtime is the one it was stored with,
+// since adding, removing or renaming an entry updates it.
+type ListingCache interface {
+	// Listing returns the entries stored for dir with the given mtime
+	Listing(dir string, modTime time.Time) ([]fs.DirEntry, bool)
+	// Store records the entries of dir, read while it had the given mtime
+	Store(dir string, modTime time.Time, entries []fs.DirEntry)
+}
+
+// Listings of directories modified this recently aren't stored: a change
+// made within the same mtime tick as the read would go unnoticed
+const racyListingWindow = 2 * time.Second
+
+// readEntries lists a directory, from the listing cache when it holds an
+// up to date copy
+func (w *walker) readEntries(dir string) ([]fs.DirEntry, error) {
+	if w.cache == nil {
+		return os.ReadDir(dir)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return os.ReadDir(dir) // reports the error
+	}
+	if entries, ok := w.cache.Listing(dir, info.ModTime()); ok {
+		w.stats.cachedDirs.Add(1)
+		return entries, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err == nil && time.Since(info.ModTime()) > racyListingWindow {
+		w.cache.Store(dir, info.ModTime(), entries)
+	}
+	return entries, err
+}
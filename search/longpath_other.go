@@ -0,0 +1,8 @@
+//go:build !windows
+
+package search
+
+// extendedPath returns path unchanged, as only Windows limits path lengths
+func extendedPath(path string) string {
+	return path
+}
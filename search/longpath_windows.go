@@ -0,0 +1,24 @@
+//go:build windows
+
+package search
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// extendedPath returns the extended-length form of path, prefixed with
+// \\?\, which lifts the 260 character MAX_PATH limit of the Windows APIs
+func extendedPath(path string) string {
+	if strings.HasPrefix(path, `\\?\`) || strings.HasPrefix(path, `\\.\`) {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + abs[2:] // \\server\share
+	}
+	return `\\?\` + abs
+}
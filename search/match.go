@@ -0,0 +1,126 @@
+package search
+
+import (
+	"io/fs"
+	"sync"
+	"time"
+)
+
+// Entry types reported in Match.Type
+const (
+	TypeFile    = "file"
+	TypeDir     = "dir"
+	TypeSymlink = "symlink"
+	TypeOther   = "other"
+)
+
+// Match is a single path found by the search. It keeps the directory entry
+// the walk read, and the file info once loaded, so consumers don't have to
+// stat the path again. Size, ModTime and the permission bits of Mode are
+// copied out of the info when the search loaded it, which it does with
+// WithInfo or when its filters need it; Info loads it otherwise.
+type Match struct {
+	Path     string            `json:"path"`
+	Type     string            `json:"type"`
+	Size     int64             `json:"size"`
+	ModTime  time.Time         `json:"mtime"`
+	Score    int               `json:"score,omitempty"`
+	Hash     string            `json:"hash,omitempty"`     // hex checksum of a file's content, with WithHash
+	Captures map[string]string `json:"captures,omitempty"` // text of the named groups of a regex pattern
+	Patterns []string          `json:"patterns,omitempty"` // patterns matched, with WithPatternTags
+	Xattrs   map[string]string `json:"xattrs,omitempty"`   // extended attributes, with WithXattrs
+	ACL      string            `json:"acl,omitempty"`      // POSIX ACL in short text form, with WithACL
+	SELinux  string            `json:"selinux,omitempty"`  // security context, with WithSecContext
+	Depth    int               `json:"-"`                  // levels below the search root
+	Mode     fs.FileMode       `json:"-"`
+	Root     string            `json:"-"` // root the match was found under, as given to the search
+	DirEntry fs.DirEntry       `json:"-"` // entry as read from its directory
+	info     *lazyInfo         // stat data, read once, shared by copies of the match
+	sys      any               // platform specific stat data, for the owner filters
+	osPath   string            // path used to access the entry, if not Path
+}
+
+// Info returns the file info of the match, which follows symlinks when the
+// search did. It is read from DirEntry on first use, unless the search
+// already did, and kept for later calls on the match and its copies.
+func (m Match) Info() (fs.FileInfo, error) {
+	if m.info != nil {
+		return m.info.load()
+	}
+	if m.DirEntry == nil {
+		return nil, fs.ErrInvalid
+	}
+	return m.DirEntry.Info()
+}
+
+// lazyInfo reads the file info of an entry the first time it is asked for
+type lazyInfo struct {
+	once sync.Once
+	d    fs.DirEntry
+	info fs.FileInfo
+	err  error
+}
+
+func (l *lazyInfo) load() (fs.FileInfo, error) {
+	l.once.Do(func() { l.info, l.err = l.d.Info() })
+	return l.info, l.err
+}
+
+// loadInfo reads the file info of the match if needed, filling the fields
+// copied from it
+func (m *Match) loadInfo() {
+	if info, err := m.Info(); err == nil {
+		m.Size = info.Size()
+		m.ModTime = info.ModTime()
+		m.Mode = info.Mode()
+		m.sys = info.Sys()
+	}
+}
+
+// fullMode returns the mode of the match with its permission bits, which
+// Mode only has once the info was loaded
+func (m Match) fullMode() fs.FileMode {
+	if info, err := m.Info(); err == nil {
+		return info.Mode()
+	}
+	return m.Mode
+}
+
+// IsDir reports whether the match is a directory
+func (m Match) IsDir() bool {
+	return m.Type == TypeDir
+}
+
+// IsExecutable reports whether the match is an executable file
+func (m Match) IsExecutable() bool {
+	return m.Type == TypeFile && isExecutable(m.fsPath(), m.fullMode())
+}
+
+// fsPath returns the path to access the match with, which on Windows may
+// be an extended-length path
+func (m Match) fsPath() string {
+	if m.osPath != "" {
+		return m.osPath
+	}
+	return m.Path
+}
+
+// newMatch builds a match for a walked entry, without stat-ing it: Mode
+// only has the type bits until the info is loaded
+func newMatch(path string, d fs.DirEntry) Match {
+	return Match{Path: path, Type: entryType(d.Type()), Mode: d.Type(), DirEntry: d, info: &lazyInfo{d: d}}
+}
+
+// entryType maps file mode type bits to a Match type
+func entryType(mode fs.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return TypeDir
+	case mode&fs.ModeSymlink != 0:
+		return TypeSymlink
+	case mode.IsRegular():
+		return TypeFile
+	default:
+		return TypeOther
+	}
+}
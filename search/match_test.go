@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMatchInfo(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "f.txt")
+	if err := os.WriteFile(path, []byte("12345"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		filled bool // Size and ModTime set by the search
+	}{
+		{"lazy", nil, false},
+		{"with info", []Option{WithInfo(true)}, true},
+		{"size filter", []Option{WithSizeFilter(SizeFilter{op: '+', bytes: 1})}, true},
+		{"without info", []Option{WithInfo(false)}, false},
+	}
+	for _, tt := range tests {
+		s, err := New("f.txt", tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matches, err := s.Search(context.Background(), root)
+		if err != nil || len(matches) != 1 {
+			t.Fatalf("%s: found %v, %v", tt.name, matches, err)
+		}
+		m := matches[0]
+		if filled := m.Size == 5 && m.ModTime.Equal(mtime); filled != tt.filled {
+			t.Errorf("%s: Size %d, ModTime %v filled = %v, want %v", tt.name, m.Size, m.ModTime, filled, tt.filled)
+		}
+		// Info is always there, loaded once for every copy of the match
+		info, err := m.Info()
+		if err != nil || info.Size() != 5 || !info.ModTime().Equal(mtime) {
+			t.Errorf("%s: Info() = %v, %v", tt.name, info, err)
+		}
+		copied := m
+		if again, _ := copied.Info(); again != info {
+			t.Errorf("%s: a copy of the match stat-ed it again", tt.name)
+		}
+		if m.Mode.Type() != 0 || m.IsExecutable() {
+			t.Errorf("%s: mode %v, executable %v", tt.name, m.Mode, m.IsExecutable())
+		}
+
+		SortMatches(matches, SortBySize, false)
+		if matches[0].Size != 5 {
+			t.Errorf("%s: sorting by size left Size %d", tt.name, matches[0].Size)
+		}
+	}
+}
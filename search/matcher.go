@@ -0,0 +1,554 @@
+package search
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Matcher decides whether an entry name matches the search pattern
+type Matcher interface {
+	Match(name string) bool
+}
+
+// PathMatcher is implemented by matchers that match the slash separated
+// path relative to the search root instead of the base name
+type PathMatcher interface {
+	MatchesPath() bool
+}
+
+// Spanner is implemented by matchers that can locate the matched text,
+// returning byte offset ranges within the name
+type Spanner interface {
+	Spans(name string) [][2]int
+}
+
+// Capturer is implemented by matchers that can extract the text matched
+// by named groups of the pattern
+type Capturer interface {
+	Captures(name string) map[string]string
+}
+
+// globMatcher matches names using glob syntax with ** and {a,b} support
+type globMatcher struct {
+	re       *regexp.Regexp
+	spans    *regexp.Regexp // re with groups around literal text, for Spans
+	fullPath bool
+}
+
+// NewGlobMatcher creates a matcher for glob patterns. Patterns containing
+// a path separator are matched against the path relative to the root.
+func NewGlobMatcher(pattern string, isCaseSensitive bool) (Matcher, error) {
+	re, err := compileGlob(pattern, isCaseSensitive, false)
+	if err != nil {
+		return nil, err
+	}
+	spans, err := compileGlob(pattern, isCaseSensitive, true)
+	if err != nil {
+		return nil, err
+	}
+	fullPath := strings.ContainsRune(pattern, '/') || strings.ContainsRune(pattern, filepath.Separator)
+	return &globMatcher{re: re, spans: spans, fullPath: fullPath}, nil
+}
+
+func (m *globMatcher) Match(name string) bool {
+	return m.re.MatchString(name)
+}
+
+func (m *globMatcher) MatchesPath() bool {
+	return m.fullPath
+}
+
+// Spans locates the text matched by the literal parts and character
+// classes of the pattern, leaving out what the wildcards matched. Path
+// patterns aren't matched against the base name, so have no spans.
+func (m *globMatcher) Spans(name string) [][2]int {
+	if m.fullPath {
+		return nil
+	}
+	return groupSpans(m.spans.FindStringSubmatchIndex(name))
+}
+
+// regexMatcher matches names using regular expressions
+type regexMatcher struct {
+	re *regexp.Regexp
+}
+
+// NewRegexMatcher creates a matcher for regular expression patterns
+func NewRegexMatcher(pattern string, isCaseSensitive bool) (Matcher, error) {
+	if !isCaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &regexMatcher{re: re}, nil
+}
+
+func (m *regexMatcher) Match(name string) bool {
+	return m.re.MatchString(name)
+}
+
+// Captures returns the text matched by the named groups of the first match
+// in name, or nil if the expression has no named groups or doesn't match
+func (m *regexMatcher) Captures(name string) map[string]string {
+	loc := m.re.FindStringSubmatchIndex(name)
+	if loc == nil {
+		return nil
+	}
+	var captures map[string]string
+	for i, group := range m.re.SubexpNames() {
+		if group == "" {
+			continue
+		}
+		if captures == nil {
+			captures = make(map[string]string)
+		}
+		// Groups that took no part in the match capture nothing
+		captures[group] = ""
+		if loc[2*i] >= 0 {
+			captures[group] = name[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return captures
+}
+
+// Spans locates every match of the expression in name. When the
+// expression has capturing groups only the text they matched is included.
+func (m *regexMatcher) Spans(name string) [][2]int {
+	var spans [][2]int
+	for _, loc := range m.re.FindAllStringSubmatchIndex(name, -1) {
+		if m.re.NumSubexp() > 0 {
+			spans = append(spans, groupSpans(loc)...)
+		} else {
+			spans = append(spans, [2]int{loc[0], loc[1]})
+		}
+	}
+	return spans
+}
+
+// groupSpans returns the non-empty ranges matched by the groups of a
+// submatch index, joining adjacent ones
+func groupSpans(loc []int) [][2]int {
+	var spans [][2]int
+	for i := 2; i+1 < len(loc); i += 2 {
+		start, end := loc[i], loc[i+1]
+		if start < 0 || end <= start {
+			continue
+		}
+		if n := len(spans); n > 0 && start <= spans[n-1][1] {
+			spans[n-1][1] = max(spans[n-1][1], end)
+			continue
+		}
+		spans = append(spans, [2]int{start, end})
+	}
+	return spans
+}
+
+// fixedMatcher matches names containing the pattern as a plain substring
+type fixedMatcher struct {
+	pattern         string
+	isCaseSensitive bool
+	re              *regexp.Regexp // the quoted pattern, to locate case-insensitive matches
+	fullPath        bool
+}
+
+// NewFixedMatcher creates a matcher for names containing pattern, with no
+// character treated specially. Patterns containing a path separator are
+// looked for in the path relative to the root.
+func NewFixedMatcher(pattern string, isCaseSensitive bool) (Matcher, error) {
+	// Relative paths are matched in slash separated form
+	pattern = filepath.ToSlash(pattern)
+	m := &fixedMatcher{pattern: pattern, isCaseSensitive: isCaseSensitive}
+	m.fullPath = strings.ContainsRune(pattern, '/')
+	quoted := regexp.QuoteMeta(pattern)
+	if !isCaseSensitive {
+		m.pattern = strings.ToLower(pattern)
+		quoted = "(?i)" + quoted
+	}
+	m.re = regexp.MustCompile(quoted)
+	return m, nil
+}
+
+func (m *fixedMatcher) Match(name string) bool {
+	if !m.isCaseSensitive {
+		name = strings.ToLower(name)
+	}
+	return strings.Contains(name, m.pattern)
+}
+
+func (m *fixedMatcher) MatchesPath() bool {
+	return m.fullPath
+}
+
+// Spans locates every occurrence of the pattern in a base name
+func (m *fixedMatcher) Spans(name string) [][2]int {
+	if m.fullPath || m.pattern == "" {
+		return nil
+	}
+	var spans [][2]int
+	if m.isCaseSensitive {
+		for offset := 0; ; {
+			i := strings.Index(name[offset:], m.pattern)
+			if i < 0 {
+				break
+			}
+			spans = append(spans, [2]int{offset + i, offset + i + len(m.pattern)})
+			offset += i + len(m.pattern)
+		}
+		return spans
+	}
+	for _, loc := range m.re.FindAllStringIndex(name, -1) {
+		spans = append(spans, [2]int{loc[0], loc[1]})
+	}
+	return spans
+}
+
+// multiMatcher combines the matchers of several patterns
+type multiMatcher struct {
+	matchers []Matcher
+	all      bool
+}
+
+// NewMultiMatcher combines matchers into one matching a name when any of
+// them does, or with all set when every one does. Matchers of base names
+// and of relative paths can be mixed.
+func NewMultiMatcher(matchers []Matcher, all bool) Matcher {
+	return &multiMatcher{matchers: matchers, all: all}
+}
+
+func (m *multiMatcher) Match(name string) bool {
+	for _, matcher := range m.matchers {
+		if matcher.Match(m.target(matcher, name)) != m.all {
+			return !m.all
+		}
+	}
+	return m.all
+}
+
+// MatchesPath asks for the relative path when any matcher needs it; the
+// others are then given its base name
+func (m *multiMatcher) MatchesPath() bool {
+	for _, matcher := range m.matchers {
+		if matchesPath(matcher) {
+			return true
+		}
+	}
+	return false
+}
+
+// target returns the part of name a single matcher looks at
+func (m *multiMatcher) target(matcher Matcher, name string) string {
+	if matchesPath(matcher) {
+		return name
+	}
+	return path.Base(name)
+}
+
+// Spans merges the spans of the base name matchers that match the name
+func (m *multiMatcher) Spans(name string) [][2]int {
+	var spans [][2]int
+	for _, matcher := range m.matchers {
+		if spanner, ok := matcher.(Spanner); ok && !matchesPath(matcher) && matcher.Match(name) {
+			spans = append(spans, spanner.Spans(name)...)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:0]
+	for _, span := range spans {
+		if n := len(merged); n > 0 && span[0] <= merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], span[1])
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+// Captures merges the named groups of the patterns that match the name,
+// earlier patterns winning for names used by several
+func (m *multiMatcher) Captures(name string) map[string]string {
+	var captures map[string]string
+	for _, matcher := range m.matchers {
+		capturer, ok := matcher.(Capturer)
+		if !ok {
+			continue
+		}
+		for group, text := range capturer.Captures(m.target(matcher, name)) {
+			if captures == nil {
+				captures = make(map[string]string)
+			}
+			if _, ok := captures[group]; !ok {
+				captures[group] = text
+			}
+		}
+	}
+	return captures
+}
+
+// matching returns the indexes of the matchers matching the name
+func (m *multiMatcher) matching(name string) []int {
+	var indexes []int
+	for i, matcher := range m.matchers {
+		if matcher.Match(m.target(matcher, name)) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// Score is the best score of the matching patterns, or the sum of all of
+// them when every pattern must match
+func (m *multiMatcher) Score(name string) (int, bool) {
+	if !m.Match(name) {
+		return 0, false
+	}
+	total := 0
+	for _, matcher := range m.matchers {
+		scorer, ok := matcher.(Scorer)
+		if !ok {
+			continue
+		}
+		score, ok := scorer.Score(m.target(matcher, name))
+		switch {
+		case m.all:
+			total += score
+		case ok:
+			total = max(total, score)
+		}
+	}
+	return total, true
+}
+
+// matchesPath reports whether a matcher is given relative paths
+func matchesPath(m Matcher) bool {
+	pm, ok := m.(PathMatcher)
+	return ok && pm.MatchesPath()
+}
+
+// fullPathMatcher hands a matcher the relative path instead of the base
+// name, for WithFullPath. Spans are left out, since they would locate text
+// in the path rather than the name.
+type fullPathMatcher struct {
+	Matcher
+}
+
+func (m fullPathMatcher) MatchesPath() bool {
+	return true
+}
+
+func (m fullPathMatcher) Captures(name string) map[string]string {
+	if capturer, ok := m.Matcher.(Capturer); ok {
+		return capturer.Captures(name)
+	}
+	return nil
+}
+
+// fullPathScorer is a fullPathMatcher around a Scorer, which stays one
+type fullPathScorer struct {
+	fullPathMatcher
+}
+
+func (m fullPathScorer) Score(name string) (int, bool) {
+	return m.Matcher.(Scorer).Score(name)
+}
+
+// newFullPathMatcher wraps a matcher to match relative paths
+func newFullPathMatcher(m Matcher) Matcher {
+	if _, ok := m.(Scorer); ok {
+		return fullPathScorer{fullPathMatcher{m}}
+	}
+	return fullPathMatcher{m}
+}
+
+// Scorer is implemented by matchers that rank how well a name matches
+type Scorer interface {
+	Score(name string) (int, bool)
+}
+
+// Fuzzy scoring weights, loosely following fzf's algorithm
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyGapStart         = -3
+	fuzzyGapExtension     = -1
+	fuzzyBonusBoundary    = 8
+	fuzzyBonusConsecutive = 4
+	fuzzyBonusFirstChar   = 2 // multiplier for a boundary bonus on the first char
+)
+
+// fuzzyMatcher matches names containing the pattern as a subsequence
+type fuzzyMatcher struct {
+	pattern         []rune
+	isCaseSensitive bool
+	threshold       int
+}
+
+// NewFuzzyMatcher creates a matcher accepting names scoring at least threshold
+func NewFuzzyMatcher(pattern string, isCaseSensitive bool, threshold int) (Matcher, error) {
+	if !isCaseSensitive {
+		pattern = strings.ToLower(pattern)
+	}
+	return &fuzzyMatcher{pattern: []rune(pattern), isCaseSensitive: isCaseSensitive, threshold: threshold}, nil
+}
+
+func (m *fuzzyMatcher) Match(name string) bool {
+	_, ok := m.Score(name)
+	return ok
+}
+
+// Score finds the shortest window of name containing the pattern in order
+// and scores it, rewarding consecutive runs and matches on word boundaries
+func (m *fuzzyMatcher) Score(name string) (int, bool) {
+	original := []rune(name)
+	text := m.fold(original)
+	if len(m.pattern) == 0 {
+		return 0, true
+	}
+	start, end, ok := m.window(text)
+	if !ok {
+		return 0, false
+	}
+
+	score := 0
+	p := 0
+	inGap, consecutive := false, false
+	for i := start; i <= end; i++ {
+		if p < len(m.pattern) && text[i] == m.pattern[p] {
+			points := fuzzyScoreMatch
+			bonus := boundaryBonus(original, i)
+			if p == 0 {
+				bonus *= fuzzyBonusFirstChar
+			}
+			if consecutive {
+				bonus += fuzzyBonusConsecutive
+			}
+			score += points + bonus
+			p++
+			inGap, consecutive = false, true
+			continue
+		}
+		if inGap {
+			score += fuzzyGapExtension
+		} else {
+			score += fuzzyGapStart
+		}
+		inGap, consecutive = true, false
+	}
+
+	return score, score >= m.threshold
+}
+
+// Spans locates the characters of name matched by the pattern within the
+// window Score rates
+func (m *fuzzyMatcher) Spans(name string) [][2]int {
+	if len(m.pattern) == 0 {
+		return nil
+	}
+	original := []rune(name)
+	text := m.fold(original)
+	start, end, ok := m.window(text)
+	if !ok || len(text) != len(original) {
+		return nil
+	}
+
+	var spans [][2]int
+	offset := 0
+	for i := range start {
+		offset += utf8.RuneLen(original[i])
+	}
+	for i, p := start, 0; i <= end; i++ {
+		size := utf8.RuneLen(original[i])
+		if p < len(m.pattern) && text[i] == m.pattern[p] {
+			if n := len(spans); n > 0 && spans[n-1][1] == offset {
+				spans[n-1][1] += size
+			} else {
+				spans = append(spans, [2]int{offset, offset + size})
+			}
+			p++
+		}
+		offset += size
+	}
+	return spans
+}
+
+// fold lowercases the name unless matching is case-sensitive
+func (m *fuzzyMatcher) fold(name []rune) []rune {
+	if m.isCaseSensitive {
+		return name
+	}
+	return []rune(strings.ToLower(string(name)))
+}
+
+// window returns the rune indexes of the shortest window of text ending at
+// the first complete match of the pattern
+func (m *fuzzyMatcher) window(text []rune) (int, int, bool) {
+	// Scan forward for the first complete match
+	p, end := 0, -1
+	for i, r := range text {
+		if r == m.pattern[p] {
+			p++
+			if p == len(m.pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, 0, false
+	}
+
+	// Scan backward from the end to find the tightest start
+	start := end
+	for p = len(m.pattern) - 1; start >= 0; start-- {
+		if text[start] == m.pattern[p] {
+			p--
+			if p < 0 {
+				break
+			}
+		}
+	}
+	return start, end, true
+}
+
+// boundaryBonus rewards characters that start a word within the name
+func boundaryBonus(name []rune, i int) int {
+	if i == 0 {
+		return fuzzyBonusBoundary
+	}
+	prev, cur := name[i-1], name[i]
+	switch {
+	case strings.ContainsRune("/\\_-. ", prev):
+		return fuzzyBonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return fuzzyBonusBoundary / 2
+	case !unicode.IsDigit(prev) && unicode.IsDigit(cur):
+		return fuzzyBonusBoundary / 2
+	}
+	return 0
+}
+
+// HasUppercase reports whether a pattern contains an uppercase letter,
+// which makes smart-case matching case-sensitive. In regular expressions
+// letters of escapes such as \S or \W don't count.
+func HasUppercase(pattern string, isRegex bool) bool {
+	escaped := false
+	for _, r := range pattern {
+		if escaped {
+			escaped = false
+			continue
+		}
+		if isRegex && r == '\\' {
+			escaped = true
+			continue
+		}
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package search
+
+import "errors"
+
+// ErrMFTUnavailable is returned by ScanMFT for roots whose Master File
+// Table can't be read, which are to be walked instead
+var ErrMFTUnavailable = errors.New("the master file table can't be read")
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package search
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+)
+
+// ScanMFT would read the NTFS Master File Table, which only Windows can
+func ScanMFT(ctx context.Context, root string, fn func(path string, d fs.DirEntry) error) error {
+	return fmt.Errorf("%w: it is only read on Windows", ErrMFTUnavailable)
+}
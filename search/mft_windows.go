@@ -0,0 +1,264 @@
+//go:build windows
+
+package search
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var procGetVolumeInformationW = kernel32.NewProc("GetVolumeInformationW")
+
+const (
+	fsctlEnumUSNData                = 0x000900b3
+	errorHandleEOF    syscall.Errno = 38
+	mftBufferSize                   = 1 << 16
+	mftRootIndex                    = 5         // record of the root directory of a volume
+	mftFirstUserIndex               = 24        // records before are the volume's metadata files
+	fileReferenceMask               = 1<<48 - 1 // record index, without the sequence number
+	usnRecordV2Size                 = 60        // fixed part of USN_RECORD_V2
+)
+
+// mftEnumData mirrors MFT_ENUM_DATA_V0
+type mftEnumData struct {
+	startFileReferenceNumber uint64
+	lowUSN                   int64
+	highUSN                  int64
+}
+
+// mftRecord is a file or directory of the Master File Table
+type mftRecord struct {
+	parent     uint64 // record index of the parent directory
+	name       string
+	attributes uint32
+}
+
+// ScanMFT calls fn for root and every entry below it, read from the NTFS
+// Master File Table of its volume instead of walking, which takes seconds
+// for a whole drive. Entries come in no particular order and their info is
+// only read when asked for. The table can only be read by administrators
+// and on local NTFS volumes; otherwise the error wraps ErrMFTUnavailable.
+func ScanMFT(ctx context.Context, root string, fn func(path string, d fs.DirEntry) error) error {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	volume := filepath.VolumeName(abs)
+	if len(volume) != 2 || volume[1] != ':' {
+		return fmt.Errorf("%w: %s is not on a local volume", ErrMFTUnavailable, root)
+	}
+	if name, err := fileSystemName(volume + `\`); err != nil || name != "NTFS" {
+		return fmt.Errorf("%w: %s is not on an NTFS volume", ErrMFTUnavailable, root)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return err
+	}
+	records, err := readMFT(ctx, volume)
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("%w: reading it requires administrator rights", ErrMFTUnavailable)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %v", ErrMFTUnavailable, err)
+	}
+
+	if err := fn(root, fs.FileInfoToDirEntry(info)); err != nil {
+		return err
+	}
+	// Names are compared case-insensitively, as NTFS does
+	prefix := strings.ToLower(strings.TrimSuffix(abs, `\`) + `\`)
+	dirs := map[uint64]string{mftRootIndex: volume + `\`}
+	hidden := make(map[uint64]bool) // whether directories are hidden or below a hidden one, up to root
+	for index, record := range records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		path, ok := recordPath(records, dirs, index)
+		if !ok || len(path) <= len(prefix) || strings.ToLower(path[:len(prefix)]) != prefix {
+			continue
+		}
+		entry := newMFTEntry(record, path)
+		// A walk never enters hidden directories, so what is below them is
+		// hidden as well
+		if hiddenDir(records, dirs, hidden, record.parent, len(prefix)) {
+			entry.attributes |= syscall.FILE_ATTRIBUTE_HIDDEN
+		}
+		if err := fn(filepath.Join(root, path[len(prefix):]), entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileSystemName returns the name of the file system of a volume, such as
+// NTFS or FAT32
+func fileSystemName(volume string) (string, error) {
+	volumep, err := syscall.UTF16PtrFromString(volume)
+	if err != nil {
+		return "", err
+	}
+	name := make([]uint16, syscall.MAX_PATH+1)
+	r, _, err := procGetVolumeInformationW.Call(uintptr(unsafe.Pointer(volumep)), 0, 0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&name[0])), uintptr(len(name)))
+	if r == 0 {
+		return "", err
+	}
+	return syscall.UTF16ToString(name), nil
+}
+
+// readMFT enumerates the records of a volume such as C: by their index
+func readMFT(ctx context.Context, volume string) (map[uint64]mftRecord, error) {
+	pathp, err := syscall.UTF16PtrFromString(`\\.\` + volume)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := syscall.CreateFile(pathp, syscall.GENERIC_READ, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE,
+		nil, syscall.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(handle)
+
+	records := make(map[uint64]mftRecord)
+	enum := mftEnumData{highUSN: math.MaxInt64}
+	buf := make([]byte, mftBufferSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var n uint32
+		err := syscall.DeviceIoControl(handle, fsctlEnumUSNData, (*byte)(unsafe.Pointer(&enum)),
+			uint32(unsafe.Sizeof(enum)), &buf[0], uint32(len(buf)), &n, nil)
+		if err == errorHandleEOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n <= 8 {
+			return records, nil
+		}
+		// The buffer starts with the reference to continue from, followed
+		// by USN_RECORD_V2 structures
+		enum.startFileReferenceNumber = binary.LittleEndian.Uint64(buf)
+		for rest := buf[8:n]; len(rest) >= usnRecordV2Size; {
+			length := int(binary.LittleEndian.Uint32(rest))
+			if length < usnRecordV2Size || length > len(rest) {
+				break
+			}
+			if major := binary.LittleEndian.Uint16(rest[4:]); major == 2 {
+				nameLength := int(binary.LittleEndian.Uint16(rest[56:]))
+				nameOffset := int(binary.LittleEndian.Uint16(rest[58:]))
+				if nameOffset+nameLength <= length {
+					name := make([]uint16, nameLength/2)
+					for i := range name {
+						name[i] = binary.LittleEndian.Uint16(rest[nameOffset+2*i:])
+					}
+					records[binary.LittleEndian.Uint64(rest[8:])&fileReferenceMask] = mftRecord{
+						parent:     binary.LittleEndian.Uint64(rest[16:]) & fileReferenceMask,
+						name:       string(utf16.Decode(name)),
+						attributes: binary.LittleEndian.Uint32(rest[52:]),
+					}
+				}
+			}
+			rest = rest[length:]
+		}
+	}
+}
+
+// recordPath builds the full path of a record from the names of its
+// ancestors, remembering those of directories. Records whose ancestors
+// don't lead to the root, and the metadata files such as $Extend and what
+// is below them, have no path.
+func recordPath(records map[uint64]mftRecord, dirs map[uint64]string, index uint64) (string, bool) {
+	if path, ok := dirs[index]; ok {
+		return path, index != mftRootIndex
+	}
+	var chain []uint64
+	path := ""
+	for current := index; ; {
+		if p, ok := dirs[current]; ok {
+			path = p
+			break
+		}
+		record, ok := records[current]
+		if !ok || current < mftFirstUserIndex || len(chain) > len(records) {
+			return "", false
+		}
+		chain = append(chain, current)
+		current = record.parent
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		record := records[chain[i]]
+		path = filepath.Join(path, record.name)
+		if record.attributes&syscall.FILE_ATTRIBUTE_DIRECTORY != 0 {
+			dirs[chain[i]] = path
+		}
+	}
+	return path, true
+}
+
+// hiddenDir reports whether a directory, or one of its ancestors with a
+// path longer than rootLength, the root left out, is hidden
+func hiddenDir(records map[uint64]mftRecord, dirs map[uint64]string, memo map[uint64]bool, index uint64, rootLength int) bool {
+	if len(dirs[index]) <= rootLength {
+		return false
+	}
+	if hidden, ok := memo[index]; ok {
+		return hidden
+	}
+	record := records[index]
+	hidden := record.attributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0 ||
+		hiddenDir(records, dirs, memo, record.parent, rootLength)
+	memo[index] = hidden
+	return hidden
+}
+
+// mftEntry is a directory entry read from the Master File Table, which has
+// the attributes but no stat data of the entry
+type mftEntry struct {
+	name       string
+	path       string
+	mode       fs.FileMode
+	attributes uint32
+}
+
+func newMFTEntry(record mftRecord, path string) *mftEntry {
+	e := &mftEntry{name: record.name, path: path, attributes: record.attributes}
+	if record.attributes&syscall.FILE_ATTRIBUTE_REPARSE_POINT != 0 && isNameSurrogate(extendedPath(path)) {
+		e.mode = fs.ModeSymlink
+	} else if record.attributes&syscall.FILE_ATTRIBUTE_DIRECTORY != 0 {
+		e.mode = fs.ModeDir
+	}
+	return e
+}
+
+func (e *mftEntry) Name() string           { return e.name }
+func (e *mftEntry) IsDir() bool            { return e.mode.IsDir() }
+func (e *mftEntry) Type() fs.FileMode      { return e.mode }
+func (e *mftEntry) fileAttributes() uint32 { return e.attributes }
+
+// Info stats the entry, reporting name surrogates as symlinks
+func (e *mftEntry) Info() (fs.FileInfo, error) {
+	info, err := os.Lstat(extendedPath(e.path))
+	if err != nil {
+		return nil, err
+	}
+	if e.mode&fs.ModeSymlink != 0 {
+		return linkInfo{info}, nil
+	}
+	return info, nil
+}
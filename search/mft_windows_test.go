@@ -0,0 +1,63 @@
+//go:build windows
+
+package search
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestRecordPath(t *testing.T) {
+	const dir = syscall.FILE_ATTRIBUTE_DIRECTORY
+	records := map[uint64]mftRecord{
+		mftRootIndex: {parent: mftRootIndex, name: ".", attributes: dir},
+		11:           {parent: mftRootIndex, name: "$Extend", attributes: dir},
+		30:           {parent: 11, name: "$Quota"},
+		40:           {parent: mftRootIndex, name: "Users", attributes: dir},
+		41:           {parent: 40, name: "me", attributes: dir | syscall.FILE_ATTRIBUTE_HIDDEN},
+		42:           {parent: 41, name: "notes.txt"},
+		43:           {parent: 99, name: "orphan.txt"}, // parent not in the table
+		50:           {parent: 51, name: "loop", attributes: dir},
+		51:           {parent: 50, name: "back", attributes: dir},
+	}
+	dirs := map[uint64]string{mftRootIndex: `C:\`}
+	tests := []struct {
+		index uint64
+		want  string // empty when the record has no path
+	}{
+		{42, `C:\Users\me\notes.txt`},
+		{41, `C:\Users\me`},
+		{mftRootIndex, ""}, // the root itself is reported apart
+		{30, ""},
+		{43, ""},
+		{50, ""},
+	}
+	for _, tt := range tests {
+		got, ok := recordPath(records, dirs, tt.index)
+		if tt.want == "" && ok {
+			t.Errorf("record %d has path %s, want none", tt.index, got)
+		} else if tt.want != "" && got != tt.want {
+			t.Errorf("record %d has path %q, %v, want %s", tt.index, got, ok, tt.want)
+		}
+	}
+	// Directories on the way are remembered, files aren't
+	if dirs[40] != `C:\Users` || dirs[41] != `C:\Users\me` {
+		t.Errorf("directories remembered: %q", dirs)
+	}
+	if _, ok := dirs[42]; ok {
+		t.Error("file remembered as a directory")
+	}
+
+	// Directories are hidden below a hidden ancestor, unless it's the root
+	// of the search or above it
+	memo := map[uint64]bool{}
+	if !hiddenDir(records, dirs, memo, 41, len(`C:\Users`)) {
+		t.Error(`C:\Users\me is not hidden`)
+	}
+	if hiddenDir(records, dirs, map[uint64]bool{}, 41, len(`C:\Users\me`)) {
+		t.Error(`C:\Users\me is hidden searching it`)
+	}
+	if hiddenDir(records, dirs, memo, 40, len(`C:\`)) {
+		t.Error(`C:\Users is hidden`)
+	}
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// Number of leading bytes sniffed to classify a file, all that
+// http.DetectContentType looks at
+const mimeSniffLen = 512
+
+// MimeFilter restricts matches to files whose sniffed content type matches
+// a pattern such as "image/png" or "image/*"
+type MimeFilter struct {
+	pattern string
+}
+
+// ParseMimeFilter parses a media type pattern. A type without a subtype,
+// such as "image", matches any subtype like "image/*".
+func ParseMimeFilter(expr string) (MimeFilter, error) {
+	pattern := strings.ToLower(strings.TrimSpace(expr))
+	if pattern == "" {
+		return MimeFilter{}, fmt.Errorf("invalid MIME type: %q", expr)
+	}
+	if !strings.Contains(pattern, "/") {
+		pattern += "/*"
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return MimeFilter{}, fmt.Errorf("invalid MIME type: %s", expr)
+	}
+	return MimeFilter{pattern: pattern}, nil
+}
+
+// Match reports whether a media type, without parameters, satisfies the
+// filter
+func (f MimeFilter) Match(mediaType string) bool {
+	ok, _ := path.Match(f.pattern, mediaType)
+	return ok
+}
+
+// DetectMimeType classifies a file by sniffing its first bytes, using the
+// signatures of http.DetectContentType. The parameters, such as the charset
+// of text, are left out. Files that aren't recognized are
+// application/octet-stream, whatever their extension.
+func DetectMimeType(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, mimeSniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return "application/octet-stream", nil
+	}
+	return mediaType, nil
+}
+
+// passesMimeFilters reports whether a match is a file whose content type
+// satisfies any of the MIME filters. It reads the file, so it comes after
+// the filters on metadata.
+func (s *Searcher) passesMimeFilters(match Match) bool {
+	if len(s.mimeFilters) == 0 {
+		return true
+	}
+	if match.Type != TypeFile {
+		return false
+	}
+	mediaType, err := DetectMimeType(match.fsPath())
+	if err != nil {
+		s.reportError(match.Path, err)
+		return false
+	}
+	for _, filter := range s.mimeFilters {
+		if filter.Match(mediaType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// normalize_tables.go is generated from the Unicode Character Database
+// files in a directory, see gen_normalize.go:
+//
+//go:generate go run gen_normalize.go ucd
+
+// Normalization is a Unicode normalization form applied to patterns and
+// names before matching, so that a name typed with a precomposed é matches
+// one stored as e followed by a combining accent, as macOS tends to
+type Normalization int
+
+const (
+	// NormalizeNFC composes characters, the form most systems store
+	NormalizeNFC Normalization = iota
+	// NormalizeNFD decomposes characters, the form HFS+ stores; it mostly
+	// matters for regexes, where é then counts as two characters
+	NormalizeNFD
+	// NormalizeNone matches names byte for byte as stored
+	NormalizeNone
+)
+
+// Apply returns s in the normalization form. Strings that aren't valid
+// UTF-8 are returned unchanged.
+func (n Normalization) Apply(s string) string {
+	if n == NormalizeNone || isASCII(s) || !utf8.ValidString(s) {
+		return s
+	}
+	runes := decompose(s)
+	if n == NormalizeNFC {
+		runes = compose(runes)
+	}
+	return string(runes)
+}
+
+// isASCII reports whether s is plain ASCII, which every form leaves alone
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Hangul syllables are composed and decomposed algorithmically
+const (
+	hangulBase   = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulCount  = hangulLCount * hangulNCount
+)
+
+// decompose returns the canonical decomposition of s in canonical order
+func decompose(s string) []rune {
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		runes = appendDecomposed(runes, r)
+	}
+	// Runs of combining marks are sorted by class, keeping the order of
+	// marks of equal class
+	for i := 1; i < len(runes); i++ {
+		class := combiningClasses[runes[i]]
+		if class == 0 {
+			continue
+		}
+		for j := i; j > 0; j-- {
+			prev := combiningClasses[runes[j-1]]
+			if prev == 0 || prev <= class {
+				break
+			}
+			runes[j-1], runes[j] = runes[j], runes[j-1]
+		}
+	}
+	return runes
+}
+
+// appendDecomposed appends the full canonical decomposition of r
+func appendDecomposed(runes []rune, r rune) []rune {
+	if s := r - hangulBase; s >= 0 && s < hangulCount {
+		runes = append(runes, hangulLBase+s/hangulNCount, hangulVBase+(s%hangulNCount)/hangulTCount)
+		if t := s % hangulTCount; t != 0 {
+			runes = append(runes, hangulTBase+t)
+		}
+		return runes
+	}
+	d, ok := canonicalDecompositions[r]
+	if !ok {
+		return append(runes, r)
+	}
+	for _, part := range d {
+		runes = appendDecomposed(runes, part)
+	}
+	return runes
+}
+
+// compose canonically composes decomposed runes in place
+func compose(runes []rune) []rune {
+	out := runes[:0]
+	starter := -1 // index in out of the last starter
+	var lastClass uint8
+	for _, r := range runes {
+		class := combiningClasses[r]
+		// A mark composes with the starter unless a mark of the same or a
+		// higher class, or another starter, comes between them
+		if starter >= 0 && (len(out)-1 == starter || lastClass != 0 && lastClass < class) {
+			if composed, ok := composePair(out[starter], r); ok {
+				out[starter] = composed
+				continue
+			}
+		}
+		if class == 0 {
+			starter = len(out)
+		}
+		lastClass = class
+		out = append(out, r)
+	}
+	return out
+}
+
+// Pairs composing into a precomposed rune, built from the decompositions
+// on first use
+var (
+	compositionsOnce sync.Once
+	compositions     map[[2]rune]rune
+)
+
+// composePair returns the rune a starter and a following rune compose into
+func composePair(a, b rune) (rune, bool) {
+	if l := a - hangulLBase; l >= 0 && l < hangulLCount {
+		if v := b - hangulVBase; v >= 0 && v < hangulVCount {
+			return hangulBase + (l*hangulVCount+v)*hangulTCount, true
+		}
+		return 0, false
+	}
+	if s := a - hangulBase; s >= 0 && s < hangulCount && s%hangulTCount == 0 {
+		if t := b - hangulTBase; t > 0 && t < hangulTCount {
+			return a + t, true
+		}
+		return 0, false
+	}
+
+	compositionsOnce.Do(func() {
+		compositions = make(map[[2]rune]rune)
+		for r, d := range canonicalDecompositions {
+			if compositionExclusions[r] || utf8.RuneCountInString(d) != 2 {
+				continue
+			}
+			first, size := utf8.DecodeRuneInString(d)
+			second, _ := utf8.DecodeRuneInString(d[size:])
+			compositions[[2]rune{first, second}] = r
+		}
+	})
+	composed, ok := compositions[[2]rune{a, b}]
+	return composed, ok
+}
+
+// ParseNormalization parses the name of a normalization form: nfc, nfd or
+// none
+func ParseNormalization(name string) (Normalization, error) {
+	switch strings.ToLower(name) {
+	case "nfc":
+		return NormalizeNFC, nil
+	case "nfd":
+		return NormalizeNFD, nil
+	case "none":
+		return NormalizeNone, nil
+	}
+	return 0, fmt.Errorf("unknown normalization: %s (expected nfc, nfd or none)", name)
+}
@@ -0,0 +1,80 @@
+package search
+
+import "testing"
+
+func TestNormalizationApply(t *testing.T) {
+	tests := []struct {
+		in       string
+		nfc, nfd string
+	}{
+		{"plain.txt", "plain.txt", "plain.txt"},
+		{"caf\u00e9", "caf\u00e9", "cafe\u0301"},
+		{"cafe\u0301", "caf\u00e9", "cafe\u0301"},
+		{"\u01d6", "\u01d6", "u\u0308\u0304"},              // decomposes twice
+		{"\u212b", "\u00c5", "A\u030a"},                    // angstrom sign is a singleton
+		{"\u2126", "\u03a9", "\u03a9"},                     // so is the ohm sign
+		{"a\u0301\u0323", "\u1ea1\u0301", "a\u0323\u0301"}, // marks sorted by class
+		{"a\u0301\u0301", "\u00e1\u0301", "a\u0301\u0301"}, // the second acute is blocked
+		{"\u0958", "\u0915\u093c", "\u0915\u093c"},         // excluded from composition
+		{"\ud55c", "\ud55c", "\u1112\u1161\u11ab"},         // Hangul LVT syllable
+		{"\u1112\u1161", "\ud558", "\u1112\u1161"},         // Hangul LV syllable
+		{"r\u00e9sum\u00e9/e\u0301", "r\u00e9sum\u00e9/\u00e9", "re\u0301sume\u0301/e\u0301"},
+		{"bad\xffe\u0301", "bad\xffe\u0301", "bad\xffe\u0301"}, // invalid UTF-8 is left alone
+	}
+	for _, tt := range tests {
+		if got := NormalizeNFC.Apply(tt.in); got != tt.nfc {
+			t.Errorf("NFC(%+q) = %+q, want %+q", tt.in, got, tt.nfc)
+		}
+		if got := NormalizeNFD.Apply(tt.in); got != tt.nfd {
+			t.Errorf("NFD(%+q) = %+q, want %+q", tt.in, got, tt.nfd)
+		}
+		if got := NormalizeNone.Apply(tt.in); got != tt.in {
+			t.Errorf("None(%+q) = %+q, want it unchanged", tt.in, got)
+		}
+	}
+}
+
+func TestNormalizationTables(t *testing.T) {
+	// Every precomposed rune must read back from its decomposition, except
+	// for the exclusions and singletons
+	for r, d := range canonicalDecompositions {
+		nfd := NormalizeNFD.Apply(string(r))
+		if NormalizeNFD.Apply(nfd) != nfd {
+			t.Errorf("NFD of %U is not stable", r)
+		}
+		if compositionExclusions[r] || len([]rune(d)) != 2 {
+			continue
+		}
+		if got := NormalizeNFC.Apply(nfd); got != string(r) {
+			t.Errorf("NFC(NFD(%U)) = %+q, want %+q", r, got, string(r))
+		}
+	}
+	for _, r := range []rune{0x0301, 0x0308, 0x0323, 0x093c} {
+		if combiningClasses[r] == 0 {
+			t.Errorf("%U has no combining class", r)
+		}
+	}
+	if combiningClasses['a'] != 0 {
+		t.Errorf("a has combining class %d, want 0", combiningClasses['a'])
+	}
+}
+
+func TestParseNormalization(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Normalization
+		wantErr bool
+	}{
+		{"nfc", NormalizeNFC, false},
+		{"NFD", NormalizeNFD, false},
+		{"none", NormalizeNone, false},
+		{"nfkc", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNormalization(tt.name)
+		if (err != nil) != tt.wantErr || err == nil && got != tt.want {
+			t.Errorf("ParseNormalization(%q) = %v, %v, want %v (error %v)", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
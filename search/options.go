@@ -0,0 +1,337 @@
+package search
+
+import "hash"
+
+// Option configures a Searcher
+type Option func(*Searcher)
+
+// WithCaseSensitive makes name matching case-sensitive
+func WithCaseSensitive(enabled bool) Option {
+	return func(s *Searcher) { s.isCaseSensitive = enabled }
+}
+
+// WithSmartCase makes matching case-sensitive only when the pattern
+// contains an uppercase letter. WithCaseSensitive(true) takes precedence.
+func WithSmartCase(enabled bool) Option {
+	return func(s *Searcher) { s.isSmartCase = enabled }
+}
+
+// WithRegex interprets the pattern as a regular expression instead of a glob
+func WithRegex(enabled bool) Option {
+	return func(s *Searcher) { s.isRegex = enabled }
+}
+
+// WithFixed matches names containing the pattern as a plain substring,
+// with no glob or regex metacharacters
+func WithFixed(enabled bool) Option {
+	return func(s *Searcher) { s.isFixed = enabled }
+}
+
+// WithFuzzy fuzzy matches names, keeping those scoring at least threshold
+func WithFuzzy(threshold int) Option {
+	return func(s *Searcher) {
+		s.isFuzzy = true
+		s.fuzzyThreshold = threshold
+	}
+}
+
+// WithApprox matches names within maxDistance edits of the pattern,
+// scoring closer names higher
+func WithApprox(maxDistance int) Option {
+	return func(s *Searcher) { s.approx = maxDistance }
+}
+
+// WithPatterns adds patterns to the one given to New. Entries matching any
+// of them are returned, or only those matching all of them with WithMatchAll.
+func WithPatterns(patterns ...string) Option {
+	return func(s *Searcher) { s.patterns = append(s.patterns, patterns...) }
+}
+
+// WithMatchAll requires every pattern to match instead of any of them
+func WithMatchAll(enabled bool) Option {
+	return func(s *Searcher) { s.matchAll = enabled }
+}
+
+// WithXattrFilter only returns entries with the extended attribute of the
+// filter. Every filter given must pass.
+func WithXattrFilter(filter XattrFilter) Option {
+	return func(s *Searcher) { s.xattrFilters = append(s.xattrFilters, filter) }
+}
+
+// WithXattrs sets the Xattrs of every match to its extended attributes
+func WithXattrs(enabled bool) Option {
+	return func(s *Searcher) { s.withXattrs = enabled }
+}
+
+// WithACL sets the ACL of every match to its POSIX ACL, on Linux
+func WithACL(enabled bool) Option {
+	return func(s *Searcher) { s.withACL = enabled }
+}
+
+// WithSecContext sets the SELinux of every match to its SELinux
+// security context, on Linux
+func WithSecContext(enabled bool) Option {
+	return func(s *Searcher) { s.withSecContext = enabled }
+}
+
+// WithEntryThrottle limits the rate at which walked entries are matched,
+// which in turn slows down the walk, for searches that shouldn't compete
+// with other work for the disk. Nil doesn't limit it.
+func WithEntryThrottle(throttle *Throttle) Option {
+	return func(s *Searcher) { s.entryThrottle = throttle }
+}
+
+// WithReadThrottle limits the rate at which the contents of files are read
+// for WithHash, in bytes. Nil doesn't limit it.
+func WithReadThrottle(throttle *Throttle) Option {
+	return func(s *Searcher) { s.readThrottle = throttle }
+}
+
+// WithFilter adds a custom filter entries must pass, asked about them last
+func WithFilter(filter Filter) Option {
+	return func(s *Searcher) { s.filters = append(s.filters, filter) }
+}
+
+// WithInvert returns the entries the patterns don't match instead, the
+// other filters applying as usual
+func WithInvert(enabled bool) Option {
+	return func(s *Searcher) { s.invert = enabled }
+}
+
+// WithPatternTags sets the Patterns of every match to the patterns it
+// matches, for telling apart the results of several patterns
+func WithPatternTags(enabled bool) Option {
+	return func(s *Searcher) { s.tagPatterns = enabled }
+}
+
+// WithFullPath matches every pattern against the slash separated path
+// relative to the search root, not only those containing a separator
+func WithFullPath(enabled bool) Option {
+	return func(s *Searcher) { s.fullPath = enabled }
+}
+
+// WithNormalization sets the Unicode normalization form patterns and names
+// are brought to before matching, NFC by default. Names are normalized
+// before being passed to a matcher set with WithMatcher too.
+func WithNormalization(form Normalization) Option {
+	return func(s *Searcher) { s.normalization = form }
+}
+
+// WithMatcher replaces the pattern based matcher with a custom one
+func WithMatcher(m Matcher) Option {
+	return func(s *Searcher) { s.matcher = m }
+}
+
+// WithTypes only returns entries of the given types, see TypeSet
+func WithTypes(types TypeSet) Option {
+	return func(s *Searcher) { s.types |= types }
+}
+
+// WithFileOnly returns files, in addition to any other types requested
+func WithFileOnly(enabled bool) Option {
+	return func(s *Searcher) {
+		if enabled {
+			s.types |= TypeFileKind
+		}
+	}
+}
+
+// WithDirOnly returns directories, in addition to any other types requested
+func WithDirOnly(enabled bool) Option {
+	return func(s *Searcher) {
+		if enabled {
+			s.types |= TypeDirKind
+		}
+	}
+}
+
+// WithJobs sets the number of goroutines reading directories in parallel,
+// and of those matching the entries found
+func WithJobs(n int) Option {
+	return func(s *Searcher) { s.jobs = n }
+}
+
+// WithIgnoreFiles controls whether .gitignore, .ignore, .searchignore and
+// global git excludes are honored, which they are by default
+func WithIgnoreFiles(enabled bool) Option {
+	return func(s *Searcher) { s.useIgnoreFiles = enabled }
+}
+
+// WithIgnoreFile adds gitignore-style files whose rules apply relative to
+// every root searched, ranking below the ignore files found while walking.
+// They are honored even when WithIgnoreFiles is disabled.
+func WithIgnoreFile(paths ...string) Option {
+	return func(s *Searcher) { s.ignoreFiles = append(s.ignoreFiles, paths...) }
+}
+
+// WithHidden includes hidden files and directories, which are skipped by
+// default. Hidden means a name starting with a dot, or on Windows also the
+// hidden attribute.
+func WithHidden(enabled bool) Option {
+	return func(s *Searcher) { s.showHidden = enabled }
+}
+
+// WithMaxDepth limits how many levels below a root the walk descends;
+// a negative depth means no limit
+func WithMaxDepth(depth int) Option {
+	return func(s *Searcher) { s.maxDepth = depth }
+}
+
+// WithMinDepth only returns entries at least depth levels below a root
+func WithMinDepth(depth int) Option {
+	return func(s *Searcher) { s.minDepth = depth }
+}
+
+// WithExcludes leaves entries whose name matches any of the globs out of
+// the results. Matching directories are still walked, see WithPrunes.
+func WithExcludes(globs ...string) Option {
+	return func(s *Searcher) { s.excludes = append(s.excludes, globs...) }
+}
+
+// WithPrunes doesn't descend into directories whose name matches any of
+// the globs, leaving them and everything below them out of the results
+func WithPrunes(globs ...string) Option {
+	return func(s *Searcher) { s.prunes = append(s.prunes, globs...) }
+}
+
+// WithSizeFilter only returns files whose size satisfies the filter
+func WithSizeFilter(filter SizeFilter) Option {
+	return func(s *Searcher) { s.sizeFilters = append(s.sizeFilters, filter) }
+}
+
+// WithModTimeFilter only returns entries whose mtime satisfies the filter
+func WithModTimeFilter(filter TimeFilter) Option {
+	return func(s *Searcher) { s.mtimeFilters = append(s.mtimeFilters, filter) }
+}
+
+// WithChangeTimeFilter only returns entries whose ctime, the last change of
+// their content or metadata, satisfies the filter. Entries are never
+// returned where the platform doesn't report a ctime, such as Windows.
+func WithChangeTimeFilter(filter TimeFilter) Option {
+	return func(s *Searcher) { s.ctimeFilters = append(s.ctimeFilters, filter) }
+}
+
+// WithAccessTimeFilter only returns entries whose atime satisfies the
+// filter. Many filesystems are mounted to update atime lazily, if at all.
+func WithAccessTimeFilter(filter TimeFilter) Option {
+	return func(s *Searcher) { s.atimeFilters = append(s.atimeFilters, filter) }
+}
+
+// WithPermFilter only returns entries whose permission bits satisfy the filter
+func WithPermFilter(filter PermFilter) Option {
+	return func(s *Searcher) { s.permFilters = append(s.permFilters, filter) }
+}
+
+// WithMimeFilter only returns files whose content type, sniffed from
+// their first bytes, satisfies the filter. Given several times, files
+// satisfying any of them are returned.
+func WithMimeFilter(filter MimeFilter) Option {
+	return func(s *Searcher) { s.mimeFilters = append(s.mimeFilters, filter) }
+}
+
+// WithPreset only returns files of the preset, by name or content type.
+// Given several times, files of any of the presets are returned.
+func WithPreset(preset Preset) Option {
+	return func(s *Searcher) { s.presets = append(s.presets, preset) }
+}
+
+// WithLanguage only returns source files in the language, one of
+// Languages, recognized as DetectLanguage does. Given several times, files
+// in any of the languages are returned.
+func WithLanguage(name string) Option {
+	return func(s *Searcher) { s.languages = append(s.languages, name) }
+}
+
+// WithInfo loads the file info of every match during the search, filling
+// Match.Size, Match.ModTime and the permission bits of Match.Mode. Without
+// it they are only filled when a filter needed the info, and Match.Info
+// loads it on demand.
+func WithInfo(load bool) Option {
+	return func(s *Searcher) { s.withInfo = load }
+}
+
+// WithHash sets Match.Hash of every matched file to its checksum with the
+// hash, such as sha256.New or one returned by HashFunc. Files are hashed by
+// the workers as they match; those that can't be read are reported and
+// left out.
+func WithHash(newHash func() hash.Hash) Option {
+	return func(s *Searcher) { s.newHash = newHash }
+}
+
+// WithOwnerFilter only returns entries owned by the filter's user or group
+func WithOwnerFilter(filter OwnerFilter) Option {
+	return func(s *Searcher) { s.ownerFilters = append(s.ownerFilters, filter) }
+}
+
+// WithBreadthFirst reads directories in the order they are found, level by
+// level, instead of finishing the subtree below a directory first. Shallow
+// matches then come first, at the cost of holding many more directories in
+// the queue.
+func WithBreadthFirst(enabled bool) Option {
+	return func(s *Searcher) { s.breadthFirst = enabled }
+}
+
+// WithFollowSymlinks follows symbolic links while walking
+func WithFollowSymlinks(enabled bool) Option {
+	return func(s *Searcher) { s.follow = enabled }
+}
+
+// WithMaxSymlinkDepth limits how many nested symbolic links are followed
+func WithMaxSymlinkDepth(depth int) Option {
+	return func(s *Searcher) { s.maxSymlinkDepth = depth }
+}
+
+// WithMaxDirEntries doesn't descend into directories below the roots that
+// hold more than n entries, such as huge cache folders. The directories are
+// still matched themselves. 0 means no limit.
+func WithMaxDirEntries(n int) Option {
+	return func(s *Searcher) { s.maxDirEntries = n }
+}
+
+// WithMaxResults stops the search once n matches have been found, 0 meaning
+// no limit
+func WithMaxResults(n int) Option {
+	return func(s *Searcher) { s.maxResults = n }
+}
+
+// WithOneFileSystem doesn't descend into directories on another
+// filesystem than their root, such as mount points on Unix (by device ID)
+// or mounted volumes on Windows (by volume serial number). The mount
+// points themselves are still reported.
+func WithOneFileSystem(enabled bool) Option {
+	return func(s *Searcher) { s.oneFileSystem = enabled }
+}
+
+// WithUniqueInodes reports a file reachable through several hard links
+// only once, at the first of its paths found. Since directories are walked
+// in parallel, which path that is may vary between runs.
+func WithUniqueInodes(enabled bool) Option {
+	return func(s *Searcher) { s.uniqueInodes = enabled }
+}
+
+// WithListingCache reads directory listings from the cache when their
+// mtime hasn't changed, and stores the listings it reads. Only names and
+// types come from the cache; everything else about a match is read from
+// disk as usual. The cache is called concurrently.
+func WithListingCache(cache ListingCache) Option {
+	return func(s *Searcher) { s.listingCache = cache }
+}
+
+// WithRawPaths reports paths in the extended-length form (\\?\C:\...) used
+// internally on Windows to reach paths longer than 260 characters, instead
+// of below the roots as given. It has no effect on other platforms.
+func WithRawPaths(enabled bool) Option {
+	return func(s *Searcher) { s.rawPaths = enabled }
+}
+
+// WithErrorHandler sets a function called for every path skipped because
+// of an error. It may be called concurrently.
+func WithErrorHandler(handler func(path string, err error)) Option {
+	return func(s *Searcher) { s.onError = handler }
+}
+
+// WithDirHandler sets a function called for every directory the walk
+// visits, for instance to report progress. It may be called concurrently.
+func WithDirHandler(handler func(path string)) Option {
+	return func(s *Searcher) { s.onDir = handler }
+}
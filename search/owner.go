@@ -0,0 +1,35 @@
+package search
+
+// OwnerFilter restricts matches to entries owned by a user, or by a group
+type OwnerFilter struct {
+	id    uint32
+	group bool
+	any   bool // ownership isn't available on this platform
+}
+
+// ParseOwnerFilter resolves a user name or numeric uid into a filter. On
+// platforms without Unix ownership the filter matches everything.
+func ParseOwnerFilter(name string) (OwnerFilter, error) {
+	return parseOwnerFilter(name, false)
+}
+
+// ParseGroupFilter resolves a group name or numeric gid into a filter. On
+// platforms without Unix ownership the filter matches everything.
+func ParseGroupFilter(name string) (OwnerFilter, error) {
+	return parseOwnerFilter(name, true)
+}
+
+// match reports whether an entry with the given stat data satisfies the filter
+func (f OwnerFilter) match(sys any) bool {
+	if f.any {
+		return true
+	}
+	uid, gid, ok := ownerIDs(sys)
+	if !ok {
+		return false
+	}
+	if f.group {
+		return gid == f.id
+	}
+	return uid == f.id
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package search
+
+// parseOwnerFilter returns a filter matching everything, as files have no
+// Unix owner on this platform
+func parseOwnerFilter(name string, group bool) (OwnerFilter, error) {
+	return OwnerFilter{any: true}, nil
+}
+
+// ownerIDs is unsupported on this platform
+func ownerIDs(sys any) (uid, gid uint32, ok bool) {
+	return 0, 0, false
+}
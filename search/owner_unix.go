@@ -0,0 +1,44 @@
+//go:build unix
+
+package search
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// parseOwnerFilter looks up a user or group, accepting numeric ids as is
+func parseOwnerFilter(name string, group bool) (OwnerFilter, error) {
+	id := name
+	if _, err := strconv.ParseUint(name, 10, 32); err != nil {
+		if group {
+			g, err := user.LookupGroup(name)
+			if err != nil {
+				return OwnerFilter{}, fmt.Errorf("unknown group: %s", name)
+			}
+			id = g.Gid
+		} else {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return OwnerFilter{}, fmt.Errorf("unknown user: %s", name)
+			}
+			id = u.Uid
+		}
+	}
+	n, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return OwnerFilter{}, fmt.Errorf("invalid id for %s: %s", name, id)
+	}
+	return OwnerFilter{id: uint32(n), group: group}, nil
+}
+
+// ownerIDs returns the uid and gid from an entry's stat data
+func ownerIDs(sys any) (uid, gid uint32, ok bool) {
+	stat, ok := sys.(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return uint32(stat.Uid), uint32(stat.Gid), true
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// PermFilter restricts matches by permission bits, like find -perm
+type PermFilter struct {
+	op   byte // '=' exactly these bits, '-' all of them, '/' any of them
+	bits uint32
+}
+
+// ParsePermFilter parses an octal ("0644") or symbolic ("u+x,g=rw") mode,
+// optionally prefixed by "-" to require all of its bits or "/" to require
+// any of them instead of the exact mode
+func ParsePermFilter(expr string) (PermFilter, error) {
+	filter := PermFilter{op: '='}
+	value := expr
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "/") {
+		filter.op = value[0]
+		value = value[1:]
+	}
+	if value == "" {
+		return filter, fmt.Errorf("invalid mode: %s", expr)
+	}
+
+	if value[0] >= '0' && value[0] <= '7' {
+		n, err := strconv.ParseUint(value, 8, 32)
+		if err != nil || n > 0o7777 {
+			return filter, fmt.Errorf("invalid mode: %s", expr)
+		}
+		filter.bits = uint32(n)
+		return filter, nil
+	}
+
+	bits, err := parseSymbolicMode(value)
+	if err != nil {
+		return filter, fmt.Errorf("invalid mode: %s", expr)
+	}
+	filter.bits = bits
+	return filter, nil
+}
+
+// parseSymbolicMode applies chmod style clauses such as "u+x" or "go=r" to
+// an empty mode. Without a u, g, o or a, a clause applies to everyone.
+func parseSymbolicMode(value string) (uint32, error) {
+	var mode uint32
+	for _, clause := range strings.Split(value, ",") {
+		i := strings.IndexAny(clause, "+-=")
+		if i < 0 {
+			return 0, fmt.Errorf("missing operator in %q", clause)
+		}
+
+		var who uint32
+		for _, c := range clause[:i] {
+			switch c {
+			case 'u':
+				who |= 0o4700
+			case 'g':
+				who |= 0o2070
+			case 'o':
+				who |= 0o1007
+			case 'a':
+				who |= 0o7777
+			default:
+				return 0, fmt.Errorf("invalid user class %q", c)
+			}
+		}
+		if who == 0 {
+			who = 0o7777
+		}
+
+		// Several operators may follow, as in "u=rw+x"
+		for rest := clause[i:]; rest != ""; {
+			op := rest[0]
+			end := strings.IndexAny(rest[1:], "+-=") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			var perms uint32
+			for _, c := range rest[1:end] {
+				switch c {
+				case 'r':
+					perms |= 0o444
+				case 'w':
+					perms |= 0o222
+				case 'x', 'X':
+					perms |= 0o111
+				case 's':
+					perms |= 0o6000
+				case 't':
+					perms |= 0o1000
+				default:
+					return 0, fmt.Errorf("invalid permission %q", c)
+				}
+			}
+			perms &= who
+			switch op {
+			case '+':
+				mode |= perms
+			case '-':
+				mode &^= perms
+			case '=':
+				mode = mode&^who | perms
+			}
+			rest = rest[end:]
+		}
+	}
+	return mode, nil
+}
+
+// Match reports whether a file mode satisfies the filter
+func (f PermFilter) Match(mode fs.FileMode) bool {
+	bits := unixPermBits(mode)
+	switch f.op {
+	case '-':
+		return bits&f.bits == f.bits
+	case '/':
+		// Like find, no bits at all matches anything
+		return f.bits == 0 || bits&f.bits != 0
+	default:
+		return bits == f.bits
+	}
+}
+
+// unixPermBits returns the mode's permission, setuid, setgid and sticky
+// bits in their traditional octal positions
+func unixPermBits(mode fs.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		bits |= 0o4000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		bits |= 0o2000
+	}
+	if mode&fs.ModeSticky != 0 {
+		bits |= 0o1000
+	}
+	return bits
+}
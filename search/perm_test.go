@@ -0,0 +1,83 @@
+package search
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestParsePermFilter(t *testing.T) {
+	tests := []struct {
+		expr    string
+		op      byte
+		bits    uint32
+		wantErr bool
+	}{
+		{"644", '=', 0o644, false},
+		{"0755", '=', 0o755, false},
+		{"-4000", '-', 0o4000, false},
+		{"/111", '/', 0o111, false},
+		{"7777", '=', 0o7777, false},
+		{"17777", 0, 0, true},
+		{"8", 0, 0, true},
+		{"648", 0, 0, true},
+		{"u+x", '=', 0o100, false},
+		{"+x", '=', 0o111, false},
+		{"a=r", '=', 0o444, false},
+		{"go=r", '=', 0o044, false},
+		{"u=rw,g=r,o=r", '=', 0o644, false},
+		{"u=rwx-w", '=', 0o500, false},
+		{"ug+rw,o-rwx", '=', 0o660, false},
+		{"u+s", '=', 0o4000, false},
+		{"g+s", '=', 0o2000, false},
+		{"o+t", '=', 0o1000, false},
+		{"-u+x,g+x", '-', 0o110, false},
+		{"/o+w", '/', 0o002, false},
+		{"", 0, 0, true},
+		{"-", 0, 0, true},
+		{"u", 0, 0, true},
+		{"z+x", 0, 0, true},
+		{"u+q", 0, 0, true},
+		{"u+x,", 0, 0, true},
+	}
+	for _, tt := range tests {
+		filter, err := ParsePermFilter(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePermFilter(%q) error = %v, want error %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (filter.op != tt.op || filter.bits != tt.bits) {
+			t.Errorf("ParsePermFilter(%q) = %c%04o, want %c%04o", tt.expr, filter.op, filter.bits, tt.op, tt.bits)
+		}
+	}
+}
+
+func TestPermFilterMatch(t *testing.T) {
+	tests := []struct {
+		expr  string
+		mode  fs.FileMode
+		match bool
+	}{
+		{"644", 0o644, true},
+		{"644", 0o755, false},
+		{"-111", 0o755, true},
+		{"-111", 0o744, false},
+		{"/111", 0o744, true},
+		{"/111", 0o644, false},
+		{"/000", 0o644, true},
+		{"-4000", 0o755 | fs.ModeSetuid, true},
+		{"-4000", 0o755, false},
+		{"2755", 0o755 | fs.ModeSetgid, true},
+		{"1777", 0o777 | fs.ModeSticky | fs.ModeDir, true},
+		{"u+x", 0o100, true},
+	}
+	for _, tt := range tests {
+		filter, err := ParsePermFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParsePermFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := filter.Match(tt.mode); got != tt.match {
+			t.Errorf("ParsePermFilter(%q) matching %v = %v, want %v", tt.expr, tt.mode, got, tt.match)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package search
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Preset is a named bundle of file kinds, such as images, matched by name
+// or by sniffed content type
+type Preset struct {
+	Name  string
+	globs []string // lowercase globs of base names, such as *.jpg
+	mimes []MimeFilter
+}
+
+// Extensions of the built-in presets
+var builtinPresets = map[string][]string{
+	"code": {
+		"go", "rs", "c", "h", "cc", "cpp", "cxx", "hh", "hpp", "cs", "java", "kt", "kts", "scala",
+		"swift", "m", "mm", "py", "rb", "php", "pl", "lua", "r", "js", "mjs", "cjs", "jsx", "ts",
+		"tsx", "vue", "svelte", "dart", "zig", "nim", "hs", "ml", "ex", "exs", "erl", "clj",
+		"sh", "bash", "zsh", "fish", "ps1", "sql",
+	},
+	"images": {
+		"jpg", "jpeg", "png", "gif", "bmp", "webp", "tif", "tiff", "svg", "ico", "heic", "heif",
+		"avif", "psd", "raw", "cr2", "nef", "arw", "dng",
+	},
+	"video": {
+		"mp4", "m4v", "mkv", "webm", "mov", "avi", "wmv", "flv", "mpg", "mpeg", "3gp", "ts", "mts",
+	},
+	"audio": {
+		"mp3", "wav", "flac", "aac", "m4a", "ogg", "oga", "opus", "wma", "aiff", "aif", "mid", "midi",
+	},
+	"docs": {
+		"pdf", "doc", "docx", "odt", "rtf", "txt", "md", "rst", "tex", "epub", "xls", "xlsx", "ods",
+		"csv", "ppt", "pptx", "odp",
+	},
+}
+
+// BuiltinPresets returns the names of the built-in presets, sorted
+func BuiltinPresets() []string {
+	names := make([]string, 0, len(builtinPresets))
+	for name := range builtinPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinPreset returns the built-in preset with the name
+func BuiltinPreset(name string) (Preset, bool) {
+	extensions, ok := builtinPresets[name]
+	if !ok {
+		return Preset{}, false
+	}
+	preset, _ := ParsePreset(name, extensions)
+	return preset, true
+}
+
+// ParsePreset builds a preset from its entries: MIME types such as
+// "image/*" (anything containing a /), globs such as "*.tar.gz", or bare
+// extensions such as "jpg" or ".jpg". Names are matched case-insensitively.
+func ParsePreset(name string, entries []string) (Preset, error) {
+	preset := Preset{Name: name}
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case strings.Contains(entry, "/"):
+			filter, err := ParseMimeFilter(entry)
+			if err != nil {
+				return Preset{}, fmt.Errorf("preset %s: %w", name, err)
+			}
+			preset.mimes = append(preset.mimes, filter)
+		default:
+			if !strings.ContainsAny(entry, "*?[") {
+				entry = "*." + strings.TrimPrefix(entry, ".")
+			}
+			if _, err := filepath.Match(entry, ""); err != nil {
+				return Preset{}, fmt.Errorf("preset %s: invalid glob: %s", name, entry)
+			}
+			preset.globs = append(preset.globs, entry)
+		}
+	}
+	return preset, nil
+}
+
+// matchesName reports whether a base name matches one of the globs
+func (p Preset) matchesName(name string) bool {
+	name = strings.ToLower(name)
+	for _, glob := range p.globs {
+		if matched, _ := filepath.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// passesPresets reports whether a match is a file belonging to any of the
+// presets. Names are tried first, so files are only read for presets with
+// MIME types when no name matches.
+func (s *Searcher) passesPresets(match Match) bool {
+	if len(s.presets) == 0 {
+		return true
+	}
+	if match.Type != TypeFile {
+		return false
+	}
+	name := filepath.Base(match.Path)
+	sniff := false
+	for _, preset := range s.presets {
+		if preset.matchesName(name) {
+			return true
+		}
+		sniff = sniff || len(preset.mimes) > 0
+	}
+	if !sniff {
+		return false
+	}
+	mediaType, err := DetectMimeType(match.fsPath())
+	if err != nil {
+		s.reportError(match.Path, err)
+		return false
+	}
+	for _, preset := range s.presets {
+		for _, filter := range preset.mimes {
+			if filter.Match(mediaType) {
+				return true
+			}
+		}
+	}
+	return false
+}
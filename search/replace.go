@@ -0,0 +1,198 @@
+package search
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Replacement rewrites the lines of files matching a content pattern
+type Replacement struct {
+	Pattern *regexp.Regexp
+	// Text replaces every match of the pattern, expanding $1 or ${name}
+	// as regexp.Regexp.ReplaceAllString does
+	Text string
+	// DryRun only reports the changes, leaving the files alone
+	DryRun bool
+	// BackupSuffix, when set, keeps the original of every rewritten file
+	// next to it, with the suffix appended to its name
+	BackupSuffix string
+	// Throttle, when set, limits the rate files are read at
+	Throttle *Throttle
+}
+
+// LineChange is a line of a file rewritten by a Replacement
+type LineChange struct {
+	Line int
+	Old  string
+	New  string
+}
+
+// FileEdit lists the changed lines of one file
+type FileEdit struct {
+	Path    string
+	Changes []LineChange
+}
+
+// ReplaceContent applies the replacement to the streamed files using a
+// fixed pool of workers, like ScanContent. Each file with matching lines
+// is rewritten to a temporary file next to it, which then replaces it, so
+// a file is never left half written. Edits are streamed on the returned
+// channel, which is closed once every file has been handled. Binary files
+// are skipped; files that can't be rewritten are passed to onError, which
+// may be nil.
+func ReplaceContent(files <-chan Match, r Replacement, workers int, onError func(path string, err error)) <-chan FileEdit {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make(chan FileEdit)
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				edit, err := r.apply(path)
+				if err != nil {
+					if onError != nil {
+						onError(path, err)
+					}
+					continue
+				}
+				if len(edit.Changes) > 0 {
+					results <- edit
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for file := range files {
+			jobs <- file.Path
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// apply rewrites one file, returning its changed lines
+func (r Replacement) apply(path string) (FileEdit, error) {
+	edit := FileEdit{Path: path}
+	// A followed symlink has its target rewritten, not replaced by a file
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return edit, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return edit, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return edit, err
+	}
+
+	reader := bufio.NewReaderSize(r.Throttle.Reader(file), 64*1024)
+	if isBinary(reader) {
+		return edit, nil // Binary files are silently skipped
+	}
+
+	var tmp *os.File
+	var w *bufio.Writer
+	if !r.DryRun {
+		tmp, err = os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+		if err != nil {
+			return edit, err
+		}
+		defer os.Remove(tmp.Name()) // fails once renamed into place
+		defer tmp.Close()
+		w = bufio.NewWriter(tmp)
+	}
+
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			// The line ending is kept out of the pattern's reach
+			text, ending := splitLineEnding(line)
+			if r.Pattern.MatchString(text) {
+				replaced := r.Pattern.ReplaceAllString(text, r.Text)
+				if replaced != text {
+					edit.Changes = append(edit.Changes, LineChange{Line: lineNum, Old: text, New: replaced})
+					line = replaced + ending
+				}
+			}
+			if w != nil {
+				if _, err := w.WriteString(line); err != nil {
+					return edit, err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return edit, err
+		}
+	}
+	if r.DryRun || len(edit.Changes) == 0 {
+		return edit, nil
+	}
+
+	if err := w.Flush(); err != nil {
+		return edit, err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return edit, err
+	}
+	if err := tmp.Close(); err != nil {
+		return edit, err
+	}
+	// Windows doesn't replace files that are still open
+	file.Close()
+	if r.BackupSuffix != "" {
+		if err := copyFile(path, path+r.BackupSuffix, info.Mode().Perm()); err != nil {
+			return edit, err
+		}
+	}
+	return edit, os.Rename(tmp.Name(), path)
+}
+
+// splitLineEnding splits a line read with its \n or \r\n ending
+func splitLineEnding(line string) (string, string) {
+	if strings.HasSuffix(line, "\r\n") {
+		return line[:len(line)-2], "\r\n"
+	}
+	if strings.HasSuffix(line, "\n") {
+		return line[:len(line)-1], "\n"
+	}
+	return line, ""
+}
+
+// copyFile copies the content of a file to a new or truncated file
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
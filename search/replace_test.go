@@ -0,0 +1,167 @@
+package search
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// replaceAll runs ReplaceContent over the files under dir and collects the
+// edits by base name along with the errors
+func replaceAll(t *testing.T, dir string, names []string, r Replacement) (map[string]FileEdit, map[string]error) {
+	t.Helper()
+	files := make(chan Match)
+	go func() {
+		for _, name := range names {
+			files <- Match{Path: filepath.Join(dir, name)}
+		}
+		close(files)
+	}()
+	var mu sync.Mutex
+	errs := map[string]error{}
+	edits := map[string]FileEdit{}
+	for edit := range ReplaceContent(files, r, 4, func(path string, err error) {
+		mu.Lock()
+		errs[filepath.Base(path)] = err
+		mu.Unlock()
+	}) {
+		edits[filepath.Base(edit.Path)] = edit
+	}
+	return edits, errs
+}
+
+// readTree returns the content of every file in dir by name
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = string(data)
+	}
+	return files
+}
+
+func TestReplaceContent(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":    "foo(1) and foo(2)\nno match\nfoo(3)",
+		"crlf.txt": "foo(x)\r\nkeep\r\n",
+		"none.txt": "nothing here\n",
+		"bin.dat":  "foo(1)\x00",
+	}
+	writeTree(t, dir, files)
+	if runtime.GOOS != "windows" {
+		if err := os.Chmod(filepath.Join(dir, "a.txt"), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := Replacement{Pattern: regexp.MustCompile(`foo\((\w)\)`), Text: "bar[$1]"}
+	names := []string{"a.txt", "crlf.txt", "none.txt", "bin.dat"}
+
+	// A dry run reports the changes, leaving the files alone
+	r.DryRun = true
+	edits, errs := replaceAll(t, dir, names, r)
+	if len(errs) > 0 {
+		t.Fatalf("dry run errors: %v", errs)
+	}
+	if len(edits) != 2 || len(edits["a.txt"].Changes) != 2 {
+		t.Errorf("dry run edits = %+v", edits)
+	}
+	if got := readTree(t, dir); !maps.Equal(got, files) {
+		t.Errorf("dry run changed the files: %q", got)
+	}
+
+	r.DryRun = false
+	edits, errs = replaceAll(t, dir, names, r)
+	if len(errs) > 0 {
+		t.Fatalf("errors: %v", errs)
+	}
+	want := []LineChange{{1, "foo(1) and foo(2)", "bar[1] and bar[2]"}, {3, "foo(3)", "bar[3]"}}
+	if !slices.Equal(edits["a.txt"].Changes, want) {
+		t.Errorf("changes = %+v, want %+v", edits["a.txt"].Changes, want)
+	}
+	// Line endings, binary and unmatched files are kept as they were, and
+	// no temporary file is left behind
+	wantFiles := map[string]string{
+		"a.txt":    "bar[1] and bar[2]\nno match\nbar[3]",
+		"crlf.txt": "bar[x]\r\nkeep\r\n",
+		"none.txt": files["none.txt"],
+		"bin.dat":  files["bin.dat"],
+	}
+	if got := readTree(t, dir); !maps.Equal(got, wantFiles) {
+		t.Errorf("files = %q, want %q", got, wantFiles)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Error(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0o640 {
+		t.Errorf("rewritten file has mode %v, want it kept", info.Mode())
+	}
+}
+
+func TestReplaceContentBackup(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "old\n", "b.txt": "other\n"})
+	r := Replacement{Pattern: regexp.MustCompile("old"), Text: "new", BackupSuffix: ".orig"}
+	if _, errs := replaceAll(t, dir, []string{"a.txt", "b.txt"}, r); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	// Only rewritten files are backed up
+	want := map[string]string{"a.txt": "new\n", "a.txt.orig": "old\n", "b.txt": "other\n"}
+	if got := readTree(t, dir); !maps.Equal(got, want) {
+		t.Errorf("files = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceContentSymlink(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"target.txt": "old\n"})
+	link := filepath.Join(dir, "link.txt")
+	if err := os.Symlink("target.txt", link); err != nil {
+		t.Skip("cannot create symbolic links:", err)
+	}
+	r := Replacement{Pattern: regexp.MustCompile("old"), Text: "new"}
+	if _, errs := replaceAll(t, dir, []string{"link.txt"}, r); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	// The target is rewritten through the link, which stays a link
+	if info, err := os.Lstat(link); err != nil {
+		t.Error(err)
+	} else if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("link replaced by a file of mode %v", info.Mode())
+	}
+	if got := readTree(t, dir)["target.txt"]; got != "new\n" {
+		t.Errorf("target = %q", got)
+	}
+}
+
+func TestReplaceContentErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "old\n", "sub/": ""})
+	r := Replacement{Pattern: regexp.MustCompile("old"), Text: "new"}
+	edits, errs := replaceAll(t, dir, []string{"missing.txt", "sub", "a.txt"}, r)
+	if !os.IsNotExist(errs["missing.txt"]) || errs["sub"] == nil {
+		t.Errorf("errors = %v, want the missing file and the directory", errs)
+	}
+	// The other files are still rewritten
+	if len(edits) != 1 || edits["a.txt"].Changes[0].New != "new" {
+		t.Errorf("edits = %+v", edits)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+	if got := readTree(t, filepath.Join(dir, "sub")); len(got) != 0 {
+		t.Errorf("files written in the directory: %q", got)
+	}
+}
@@ -0,0 +1,629 @@
+// Package search finds files and directories whose names match a pattern.
+//
+// A Searcher is configured once with functional options and can then be
+// run against any number of root directories:
+//
+//	s, err := search.New("*.go", search.WithFileOnly(true))
+//	if err != nil {
+//		return err
+//	}
+//	matches, err := s.Search(ctx, "./src")
+//
+// The package never prints; entries that can't be read are skipped,
+// reported through the handler set by WithErrorHandler. Each search can
+// also be started from a Run, which keeps the Stats of the search and the
+// WalkErrors of the paths it skipped.
+package search
+
+import (
+	"context"
+	"errors"
+	"hash"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Searcher walks directory trees looking for entries matching a pattern
+type Searcher struct {
+	patterns        []string
+	matchAll        bool
+	matcher         Matcher
+	isCaseSensitive bool
+	isSmartCase     bool
+	isRegex         bool
+	isFixed         bool
+	isFuzzy         bool
+	fuzzyThreshold  int
+	approx          int // maximum edit distance, -1 unless approximate matching
+	types           TypeSet
+	jobs            int
+	useIgnoreFiles  bool
+	ignoreFiles     []string     // extra ignore files given to WithIgnoreFile
+	ignoreRules     []ignoreRule // their rules, applied below every root
+	showHidden      bool
+	maxDepth        int
+	minDepth        int
+	excludes        []string
+	prunes          []string
+	sizeFilters     []SizeFilter
+	mtimeFilters    []TimeFilter
+	ctimeFilters    []TimeFilter
+	atimeFilters    []TimeFilter
+	ownerFilters    []OwnerFilter
+	permFilters     []PermFilter
+	mimeFilters     []MimeFilter
+	presets         []Preset
+	languages       []string
+	newHash         func() hash.Hash
+	follow          bool
+	breadthFirst    bool
+	maxSymlinkDepth int
+	maxDirEntries   int
+	maxResults      int
+	onError         func(path string, err error)
+	onDir           func(path string)
+	rawPaths        bool
+	oneFileSystem   bool
+	uniqueInodes    bool
+	listingCache    ListingCache
+	stats           *statsCounter // of the run the searcher belongs to
+	matchPath       bool          // match the relative path instead of the base name
+	fullPath        bool          // match every pattern against the relative path
+	normalization   Normalization
+	skipped         *skippedPaths // of the run, nil outside of one
+	tagPatterns     bool          // fill Match.Patterns
+	invert          bool          // return the entries the pattern doesn't match
+	filters         []Filter
+	xattrFilters    []XattrFilter
+	withXattrs      bool // fill Match.Xattrs
+	withACL         bool // fill Match.ACL
+	withSecContext  bool // fill Match.SecContext
+	entryThrottle   *Throttle
+	readThrottle    *Throttle // for hashing
+	withInfo        bool      // load the info of every match
+	loadInfo        bool      // load it, for WithInfo or the filters
+}
+
+// New creates a Searcher for pattern, glob syntax by default
+func New(pattern string, opts ...Option) (*Searcher, error) {
+	s := &Searcher{
+		patterns:        []string{pattern},
+		jobs:            runtime.NumCPU(),
+		useIgnoreFiles:  true,
+		maxDepth:        -1,
+		approx:          -1,
+		maxSymlinkDepth: DefaultMaxSymlinkDepth,
+		stats:           &statsCounter{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.isFuzzy && s.isRegex {
+		return nil, errors.New("fuzzy and regex matching are mutually exclusive")
+	}
+	if s.isFixed && (s.isFuzzy || s.isRegex) {
+		return nil, errors.New("fixed string matching excludes fuzzy and regex matching")
+	}
+	if s.approx >= 0 && (s.isFuzzy || s.isRegex || s.isFixed) {
+		return nil, errors.New("approximate matching excludes fuzzy, regex and fixed string matching")
+	}
+	if s.maxDepth >= 0 && s.minDepth > s.maxDepth {
+		return nil, errors.New("minimum depth cannot be greater than maximum depth")
+	}
+	for _, glob := range append(s.excludes, s.prunes...) {
+		if _, err := filepath.Match(glob, ""); err != nil {
+			return nil, err
+		}
+	}
+	for i, name := range s.languages {
+		var err error
+		if s.languages[i], err = ParseLanguage(name); err != nil {
+			return nil, err
+		}
+	}
+	if len(s.xattrFilters) > 0 || s.withXattrs || s.withACL || s.withSecContext {
+		if err := checkXattrSupport(); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range s.ignoreFiles {
+		rules, err := loadIgnoreFile(path)
+		if err != nil {
+			return nil, err
+		}
+		s.ignoreRules = append(s.ignoreRules, rules...)
+	}
+	if s.jobs < 1 {
+		s.jobs = 1
+	}
+	// Matches are only stat-ed during the search when something needs it
+	s.loadInfo = s.withInfo || s.uniqueInodes || len(s.sizeFilters)+len(s.mtimeFilters)+len(s.ctimeFilters)+
+		len(s.atimeFilters)+len(s.ownerFilters)+len(s.permFilters) > 0
+
+	if s.matcher == nil {
+		matchers := make([]Matcher, len(s.patterns))
+		for i, pattern := range s.patterns {
+			pattern = s.normalization.Apply(pattern)
+			var err error
+			if matchers[i], err = s.newMatcher(pattern); err != nil {
+				return nil, err
+			}
+			if s.fullPath {
+				matchers[i] = newFullPathMatcher(matchers[i])
+			}
+		}
+		s.matcher = matchers[0]
+		if len(matchers) > 1 {
+			s.matcher = NewMultiMatcher(matchers, s.matchAll)
+		}
+	}
+	// Path matchers see the path relative to the root, others the base name
+	if pm, ok := s.matcher.(PathMatcher); ok {
+		s.matchPath = pm.MatchesPath()
+	}
+	return s, nil
+}
+
+// Matcher returns the matcher used to test entry names
+func (s *Searcher) Matcher() Matcher {
+	return s.matcher
+}
+
+// newMatcher creates the matcher strategy selected by the options for one
+// pattern; smart case is decided for each pattern on its own
+func (s *Searcher) newMatcher(pattern string) (Matcher, error) {
+	isCaseSensitive := s.isCaseSensitive || s.isSmartCase && HasUppercase(pattern, s.isRegex)
+	if s.isFuzzy {
+		return NewFuzzyMatcher(pattern, isCaseSensitive, s.fuzzyThreshold)
+	}
+	if s.approx >= 0 {
+		return NewApproxMatcher(pattern, isCaseSensitive, s.approx)
+	}
+	if s.isRegex {
+		return NewRegexMatcher(pattern, isCaseSensitive)
+	}
+	if s.isFixed {
+		return NewFixedMatcher(pattern, isCaseSensitive)
+	}
+	return NewGlobMatcher(pattern, isCaseSensitive)
+}
+
+// walkEntry is a candidate entry handed from the walker to the workers
+type walkEntry struct {
+	root   string // root as given
+	path   string
+	osPath string // path used to access the entry, if not path
+	rel    string // slash separated path relative to the root
+	depth  int
+	d      fs.DirEntry
+}
+
+// Run is a search of a Searcher whose statistics and skipped paths are
+// kept apart from those of other searches. Its methods can be called more
+// than once, the work they do adding up in the run.
+type Run struct {
+	s *Searcher // copy of the searcher counting into the run
+}
+
+// NewRun creates a run of the searcher with its options
+func (s *Searcher) NewRun() *Run {
+	run := *s
+	run.stats = &statsCounter{}
+	run.skipped = &skippedPaths{}
+	return &Run{s: &run}
+}
+
+// Search collects every match under the roots into a slice, as a new run
+func (s *Searcher) Search(ctx context.Context, roots ...string) ([]Match, error) {
+	return s.NewRun().Search(ctx, roots...)
+}
+
+// Stream walks the roots and sends matches as soon as they are found, as a
+// new run. The match channel is closed when the walk ends, after which the
+// error channel yields the walk error (if any) and is closed.
+func (s *Searcher) Stream(ctx context.Context, roots ...string) (<-chan Match, <-chan error) {
+	return s.NewRun().Stream(ctx, roots...)
+}
+
+// Search collects every match under the roots into a slice
+func (r *Run) Search(ctx context.Context, roots ...string) ([]Match, error) {
+	var matches []Match
+	results, errc := r.Stream(ctx, roots...)
+	for match := range results {
+		matches = append(matches, match)
+	}
+	return matches, <-errc
+}
+
+// Stream walks the roots and sends matches as soon as they are found, as
+// Searcher.Stream does
+func (r *Run) Stream(ctx context.Context, roots ...string) (<-chan Match, <-chan error) {
+	s := r.s
+	results := make(chan Match)
+	errc := make(chan error, 1)
+
+	// The walk is cancelled early once enough matches have been found
+	walkCtx, stop := context.WithCancel(ctx)
+	var found atomic.Int64
+	var inodes *inodeSet
+	if s.uniqueInodes {
+		inodes = newInodeSet()
+	}
+
+	// Fixed pool of workers matching the entries produced by the walker
+	var wg sync.WaitGroup
+	entries := make(chan walkEntry, s.jobs)
+	for i := 0; i < s.jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range entries {
+				s.stats.startWork()
+				match, ok := s.evaluate(entry)
+				s.stats.endWork()
+				if !ok || inodes != nil && !inodes.first(match) {
+					continue
+				}
+				n := found.Add(1)
+				if s.maxResults > 0 && n > int64(s.maxResults) {
+					continue
+				}
+				select {
+				case results <- match:
+				case <-ctx.Done():
+				}
+				if n == int64(s.maxResults) {
+					stop()
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer stop()
+		var err error
+		w := newWalker(s)
+		for _, root := range UniqueRoots(roots) {
+			if err = s.walk(walkCtx, w, root, entries); err != nil {
+				break
+			}
+		}
+		// Stopping at the result limit is not an error
+		if err != nil && ctx.Err() == nil && walkCtx.Err() != nil {
+			err = nil
+		}
+		close(entries)
+		wg.Wait()
+		close(results)
+		if err != nil {
+			errc <- err
+		}
+		close(errc)
+	}()
+
+	return results, errc
+}
+
+// evaluate matches an entry's name and metadata against the search
+func (s *Searcher) evaluate(entry walkEntry) (Match, bool) {
+	s.entryThrottle.Wait(1)
+	s.stats.entries.Add(1)
+	target := filepath.Base(entry.path)
+	if s.matchPath {
+		target = entry.rel
+	}
+	target = s.normalization.Apply(target)
+	if s.matcher.Match(target) == s.invert {
+		return Match{}, false
+	}
+	match := newMatch(entry.path, entry.d)
+	match.osPath = entry.osPath
+	match.Root = entry.root
+	if s.loadInfo {
+		match.loadInfo()
+		s.stats.bytes.Add(match.Size)
+	}
+	match.Depth = entry.depth
+	if !s.types.matches(match) || !s.passesFilters(match) || !s.passesPresets(match) ||
+		!s.passesLanguages(match) || !s.passesMimeFilters(match) || !s.passesXattrFilters(match) ||
+		!s.passesCustomFilters(match) {
+		return Match{}, false
+	}
+	// An inverted match has nothing the pattern matched to score or capture
+	if !s.invert {
+		if scorer, ok := s.matcher.(Scorer); ok {
+			match.Score, _ = scorer.Score(target)
+		}
+		if capturer, ok := s.matcher.(Capturer); ok {
+			match.Captures = capturer.Captures(target)
+		}
+		if s.tagPatterns {
+			match.Patterns = s.matchingPatterns(target)
+		}
+	}
+	if s.withXattrs {
+		xattrs, err := ReadXattrs(match.fsPath())
+		if err != nil {
+			s.reportError(match.Path, err)
+		}
+		match.Xattrs = xattrs
+	}
+	if s.withACL {
+		acl, err := ReadACL(match.fsPath())
+		if err != nil {
+			s.reportError(match.Path, err)
+		}
+		match.ACL = acl
+	}
+	if s.withSecContext {
+		context, err := ReadSecContext(match.fsPath())
+		if err != nil {
+			s.reportError(match.Path, err)
+		}
+		match.SELinux = context
+	}
+	if s.newHash != nil && match.Type == TypeFile {
+		sum, err := checksumFile(match.fsPath(), s.newHash, s.readThrottle)
+		if err != nil {
+			s.reportError(match.Path, err)
+			return Match{}, false
+		}
+		match.Hash = sum
+	}
+	return match, true
+}
+
+// matchingPatterns returns the patterns, as given, that a matching name
+// matches: every one of them with WithMatchAll
+func (s *Searcher) matchingPatterns(target string) []string {
+	multi, ok := s.matcher.(*multiMatcher)
+	if !ok {
+		return s.patterns[:1]
+	}
+	var patterns []string
+	for _, i := range multi.matching(target) {
+		patterns = append(patterns, s.patterns[i])
+	}
+	return patterns
+}
+
+// Check reports whether an entry found without walking, for instance read
+// from an index, matches the search as if it had been walked from root.
+// With an empty root the path is judged as given, its depth being the
+// number of names in it. Ignore files are not consulted. Errors are only
+// passed to the error handler; Run.Check also records them.
+func (s *Searcher) Check(root, path string, d fs.DirEntry) (Match, bool) {
+	return s.check(root, path, d)
+}
+
+// Check reports whether an entry found without walking matches the search,
+// as Searcher.Check does, counting it in the run
+func (r *Run) Check(root, path string, d fs.DirEntry) (Match, bool) {
+	return r.s.check(root, path, d)
+}
+
+// check implements Check
+func (s *Searcher) check(root, path string, d fs.DirEntry) (Match, bool) {
+	var rel string
+	if root == "" {
+		rel = strings.TrimLeft(filepath.ToSlash(filepath.Clean(path)[len(filepath.VolumeName(path)):]), "/")
+		if rel == "" {
+			rel = "."
+		}
+	} else {
+		rel = relativePath(root, path)
+	}
+	depth := pathDepth(rel)
+	if s.maxDepth >= 0 && depth > s.maxDepth || depth < s.minDepth {
+		return Match{}, false
+	}
+	if !s.types.matchesKind(d.Type()) {
+		return Match{}, false
+	}
+	// A pruned or hidden directory hides everything below it
+	if rel != "." {
+		names := strings.Split(rel, "/")
+		for i, name := range names {
+			if name == ".." {
+				continue
+			}
+			if !s.showHidden && strings.HasPrefix(name, ".") {
+				return Match{}, false
+			}
+			if (i < len(names)-1 || d.IsDir()) && isExcluded(name, s.prunes) {
+				return Match{}, false
+			}
+		}
+		if isExcluded(names[len(names)-1], s.excludes) {
+			return Match{}, false
+		}
+		if !s.showHidden && isHidden(d) {
+			return Match{}, false
+		}
+	}
+	return s.evaluate(walkEntry{root: root, path: path, rel: rel, depth: depth, d: d})
+}
+
+// UniqueRoots drops duplicate roots and roots nested inside another root,
+// since walking the outer root already covers them
+func UniqueRoots(roots []string) []string {
+	var unique []string
+	absRoots := make([]string, len(roots))
+	for i, root := range roots {
+		if abs, err := filepath.Abs(root); err == nil {
+			absRoots[i] = abs
+		} else {
+			absRoots[i] = filepath.Clean(root)
+		}
+	}
+
+	for i, root := range roots {
+		covered := false
+		for j := range roots {
+			if i == j {
+				continue
+			}
+			_, inside := relativeTo(absRoots[j], absRoots[i])
+			// Of two identical roots, keep only the first
+			if inside || (absRoots[i] == absRoots[j] && j < i) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			unique = append(unique, root)
+		}
+	}
+	return unique
+}
+
+// reportError records a skipped path in the run and passes it to the
+// error handler, if any
+func (s *Searcher) reportError(path string, err error) {
+	s.stats.errors.Add(1)
+	if s.skipped != nil {
+		s.skipped.add(NewWalkError(path, err))
+	}
+	if s.onError != nil {
+		s.onError(path, err)
+	}
+}
+
+// walk traverses the tree under root, feeding candidate entries to the workers
+func (s *Searcher) walk(ctx context.Context, w *walker, root string, entries chan<- walkEntry) error {
+	// Paths are walked in extended-length form on Windows, see displayPath
+	osRoot := extendedPath(root)
+	ignores := newIgnoreStack(osRoot, s.useIgnoreFiles, s.ignoreRules)
+	rootID, hasRootID := fileID{}, false
+	if s.oneFileSystem {
+		rootID, hasRootID = getFileID(osRoot)
+	}
+
+	return w.Walk(osRoot, ignores, func(path string, d fs.DirEntry, ignores *ignoreStack, err error) (*ignoreStack, error) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		display := s.displayPath(root, osRoot, path)
+		if err != nil {
+			// Skip entries we can't read and carry on with the walk
+			s.reportError(display, err)
+			return nil, nil
+		}
+
+		// Don't descend into pruned directories at all
+		isRoot := path == osRoot
+		if !isRoot && d.IsDir() && isExcluded(d.Name(), s.prunes) {
+			return nil, filepath.SkipDir
+		}
+
+		// Skip hidden entries unless asked for, pruning hidden directories
+		if !isRoot && !s.showHidden && isHidden(d) {
+			return nil, skipDir(d)
+		}
+
+		// Honor ignore files, pruning ignored directories
+		if !isRoot && ignores.IsIgnored(path, d.IsDir()) {
+			return nil, skipDir(d)
+		}
+		if s.useIgnoreFiles && d.IsDir() {
+			ignores = ignores.Push(path)
+		}
+		if d.IsDir() {
+			s.stats.visitDir()
+			if s.onDir != nil {
+				s.onDir(display)
+			}
+		}
+
+		// Prune directories at the depth limit, still reporting the directory itself
+		rel := relativePath(osRoot, path)
+		depth := pathDepth(rel)
+		var skip error
+		if d.IsDir() && s.maxDepth >= 0 && depth >= s.maxDepth {
+			skip = filepath.SkipDir
+		}
+		// Likewise report mount points without descending into them
+		if hasRootID && d.IsDir() && !isRoot {
+			if id, ok := getFileID(path); ok && id.device != rootID.device {
+				skip = filepath.SkipDir
+			}
+		}
+		if depth < s.minDepth {
+			return ignores, skip
+		}
+
+		// Skip entries of kinds that weren't asked for before stat-ing them,
+		// and excluded names, whose directories are still walked
+		if !s.types.matchesKind(d.Type()) || !isRoot && isExcluded(d.Name(), s.excludes) {
+			return ignores, skip
+		}
+
+		entry := walkEntry{root: root, path: display, rel: rel, depth: depth, d: d}
+		if display != path {
+			entry.osPath = path
+		}
+		select {
+		case entries <- entry:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return ignores, skip
+	})
+}
+
+// displayPath maps a path walked from osRoot, the extended-length form of
+// root on Windows, back to a path below root as given, unless raw paths
+// were asked for
+func (s *Searcher) displayPath(root, osRoot, path string) string {
+	switch {
+	case s.rawPaths || osRoot == root:
+		return path
+	case path == osRoot:
+		return root
+	}
+	// The separator is dropped so that a drive-relative root such as C:
+	// keeps pointing below the current directory of the drive
+	return filepath.Join(root, strings.TrimPrefix(path[len(osRoot):], string(filepath.Separator)))
+}
+
+// skipDir returns the walk result leaving out an entry: filepath.SkipDir
+// for a directory so that its contents are skipped too, nil otherwise
+func skipDir(d fs.DirEntry) error {
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// isExcluded reports whether name matches any of the globs
+func isExcluded(name string, globs []string) bool {
+	for _, glob := range globs {
+		if matched, _ := filepath.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// relativePath returns path relative to root in slash separated form
+func relativePath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// pathDepth returns how many levels below the root a relative path is,
+// the root itself being 0
+func pathDepth(rel string) int {
+	if rel == "." {
+		return 0
+	}
+	return strings.Count(rel, "/") + 1
+}
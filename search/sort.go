@@ -0,0 +1,83 @@
+package search
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// SortKey selects the order in which SortMatches arranges matches
+type SortKey string
+
+// Supported sort keys
+const (
+	SortByName  SortKey = "name"
+	SortBySize  SortKey = "size"
+	SortByMtime SortKey = "mtime"
+	SortByDepth SortKey = "depth"
+	SortByScore SortKey = "score"
+)
+
+// ParseSortKey validates a sort key given by name
+func ParseSortKey(name string) (SortKey, error) {
+	switch key := SortKey(name); key {
+	case SortByName, SortBySize, SortByMtime, SortByDepth, SortByScore:
+		return key, nil
+	default:
+		return "", fmt.Errorf("unknown sort key: %s", name)
+	}
+}
+
+// SortMatches orders matches by key, ascending unless reverse is set.
+// Fuzzy scores sort best first. Ties are broken by path so the order is
+// always deterministic. Sorting by size or mtime loads the info of matches
+// that don't have it yet.
+func SortMatches(matches []Match, key SortKey, reverse bool) {
+	if key.needsInfo() {
+		for i := range matches {
+			matches[i].loadInfo()
+		}
+	}
+	less := matchLess(key, reverse)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return less(matches[i], matches[j])
+	})
+}
+
+// needsInfo reports whether sorting by the key reads the file info
+func (key SortKey) needsInfo() bool {
+	return key == SortBySize || key == SortByMtime
+}
+
+// matchLess returns the ordering SortMatches sorts by
+func matchLess(key SortKey, reverse bool) func(a, b Match) bool {
+	less := func(a, b Match) bool {
+		switch key {
+		case SortByName:
+			if nameA, nameB := filepath.Base(a.Path), filepath.Base(b.Path); nameA != nameB {
+				return nameA < nameB
+			}
+		case SortBySize:
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case SortByMtime:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.Before(b.ModTime)
+			}
+		case SortByDepth:
+			if a.Depth != b.Depth {
+				return a.Depth < b.Depth
+			}
+		case SortByScore:
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+		}
+		return a.Path < b.Path
+	}
+	if reverse {
+		return func(a, b Match) bool { return less(b, a) }
+	}
+	return less
+}
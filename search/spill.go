@@ -0,0 +1,236 @@
+package search
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+)
+
+// Rough memory taken by a match besides its strings, used to keep a
+// MatchSorter within its budget
+const matchOverhead = 256
+
+// MatchSorter sorts matches like SortMatches while holding at most about
+// budget bytes of them in memory. Once the budget is exceeded, the matches
+// held are sorted and written to a temporary file, and the sorted files are
+// merged when reading the result. Matches read back from a file keep their
+// exported fields only, so Info reads nothing for them.
+type MatchSorter struct {
+	less   func(a, b Match) bool
+	info   bool  // load the info of matches added, for the key
+	budget int64 // no limit when 0
+	held   []Match
+	size   int64
+	runs   []*os.File
+}
+
+// NewMatchSorter creates a sorter for key, spilling to disk past budget
+// bytes, or never when budget is 0
+func NewMatchSorter(key SortKey, reverse bool, budget int64) *MatchSorter {
+	return &MatchSorter{less: matchLess(key, reverse), info: key.needsInfo(), budget: budget}
+}
+
+// spilledMatch is the form a match is written to a run file in
+type spilledMatch struct {
+	Path     string
+	Type     string
+	Size     int64
+	ModTime  time.Time
+	Score    int
+	Hash     string
+	Captures map[string]string
+	Patterns []string
+	Xattrs   map[string]string
+	ACL      string
+	SELinux  string
+	Depth    int
+	Mode     fs.FileMode
+	Root     string
+}
+
+// Add adds a match, spilling the matches held to disk when over budget
+func (s *MatchSorter) Add(match Match) error {
+	if s.info {
+		match.loadInfo()
+	}
+	s.held = append(s.held, match)
+	s.size += matchOverhead + int64(len(match.Path)+len(match.Root)+len(match.Hash)+len(match.ACL)+len(match.SELinux))
+	for name, text := range match.Captures {
+		s.size += int64(len(name) + len(text))
+	}
+	for _, pattern := range match.Patterns {
+		s.size += int64(len(pattern))
+	}
+	for name, value := range match.Xattrs {
+		s.size += int64(len(name) + len(value))
+	}
+	if s.budget > 0 && s.size > s.budget {
+		return s.spill()
+	}
+	return nil
+}
+
+// spill writes the matches held, sorted, to a new run file
+func (s *MatchSorter) spill() error {
+	file, err := os.CreateTemp("", "search-sort-*")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, file)
+	s.sortHeld()
+	w := bufio.NewWriter(file)
+	enc := gob.NewEncoder(w)
+	for _, m := range s.held {
+		record := spilledMatch{
+			Path: m.Path, Type: m.Type, Size: m.Size, ModTime: m.ModTime, Score: m.Score,
+			Hash: m.Hash, Captures: m.Captures, Patterns: m.Patterns, Xattrs: m.Xattrs, ACL: m.ACL,
+			SELinux: m.SELinux, Depth: m.Depth,
+			Mode: m.Mode, Root: m.Root,
+		}
+		if err := enc.Encode(&record); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	clear(s.held)
+	s.held = s.held[:0]
+	s.size = 0
+	return nil
+}
+
+// sortHeld sorts the matches held in memory
+func (s *MatchSorter) sortHeld() {
+	sort.SliceStable(s.held, func(i, j int) bool { return s.less(s.held[i], s.held[j]) })
+}
+
+// Each calls fn with every match added, in order, until fn returns false
+func (s *MatchSorter) Each(fn func(Match) bool) error {
+	s.sortHeld()
+	if len(s.runs) == 0 {
+		for _, m := range s.held {
+			if !fn(m) {
+				break
+			}
+		}
+		return nil
+	}
+
+	merge := &runMerge{less: s.less}
+	for _, file := range s.runs {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		run := &fileRun{dec: gob.NewDecoder(bufio.NewReader(file))}
+		if err := merge.push(run); err != nil {
+			return err
+		}
+	}
+	if err := merge.push(&sliceRun{matches: s.held, i: -1}); err != nil {
+		return err
+	}
+	for merge.Len() > 0 {
+		run := merge.runs[0]
+		if !fn(run.current()) {
+			return nil
+		}
+		more, err := run.next()
+		if err != nil {
+			return err
+		}
+		if more {
+			heap.Fix(merge, 0)
+		} else {
+			heap.Pop(merge)
+		}
+	}
+	return nil
+}
+
+// Close removes the run files
+func (s *MatchSorter) Close() error {
+	var errs []error
+	for _, file := range s.runs {
+		file.Close()
+		errs = append(errs, os.Remove(file.Name()))
+	}
+	s.runs = nil
+	s.held = nil
+	return errors.Join(errs...)
+}
+
+// sortedRun is a sorted sequence of matches being merged
+type sortedRun interface {
+	current() Match
+	next() (bool, error) // moves to the next match, reporting if there is one
+}
+
+// sliceRun is the run of matches still held in memory
+type sliceRun struct {
+	matches []Match
+	i       int
+}
+
+func (r *sliceRun) current() Match { return r.matches[r.i] }
+
+func (r *sliceRun) next() (bool, error) {
+	r.i++
+	return r.i < len(r.matches), nil
+}
+
+// fileRun reads back a run spilled to a file
+type fileRun struct {
+	dec *gob.Decoder
+	cur Match
+}
+
+func (r *fileRun) current() Match { return r.cur }
+
+func (r *fileRun) next() (bool, error) {
+	var record spilledMatch
+	if err := r.dec.Decode(&record); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	r.cur = Match{
+		Path: record.Path, Type: record.Type, Size: record.Size, ModTime: record.ModTime, Score: record.Score,
+		Hash: record.Hash, Captures: record.Captures, Patterns: record.Patterns, Xattrs: record.Xattrs, ACL: record.ACL,
+		SELinux: record.SELinux, Depth: record.Depth, Mode: record.Mode, Root: record.Root,
+	}
+	return true, nil
+}
+
+// runMerge is a heap of runs ordered by their current match
+type runMerge struct {
+	less func(a, b Match) bool
+	runs []sortedRun
+}
+
+// push adds a run positioned at its first match, unless it is empty
+func (h *runMerge) push(run sortedRun) error {
+	if more, err := run.next(); err != nil || !more {
+		return err
+	}
+	heap.Push(h, run)
+	return nil
+}
+
+func (h *runMerge) Len() int           { return len(h.runs) }
+func (h *runMerge) Less(i, j int) bool { return h.less(h.runs[i].current(), h.runs[j].current()) }
+func (h *runMerge) Swap(i, j int)      { h.runs[i], h.runs[j] = h.runs[j], h.runs[i] }
+func (h *runMerge) Push(x any)         { h.runs = append(h.runs, x.(sortedRun)) }
+
+func (h *runMerge) Pop() any {
+	run := h.runs[len(h.runs)-1]
+	h.runs = h.runs[:len(h.runs)-1]
+	return run
+}
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Stats summarizes the work done by a Run so far
+type Stats struct {
+	Dirs           int64 // directories walked
+	CachedDirs     int64 // directories listed from the listing cache
+	Entries        int64 // entries examined by the workers
+	Errors         int64 // paths skipped because of an error
+	Bytes          int64 // total size of the entries stat-ed
+	PeakWorkers    int   // most workers busy at the same time
+	PeakGoroutines int   // most goroutines running, sampled once per directory
+}
+
+// statsCounter collects Stats while searches run concurrently
+type statsCounter struct {
+	dirs           atomic.Int64
+	cachedDirs     atomic.Int64
+	entries        atomic.Int64
+	errors         atomic.Int64
+	bytes          atomic.Int64
+	busy           atomic.Int64
+	peakWorkers    atomic.Int64
+	peakGoroutines atomic.Int64
+}
+
+// Stats returns a snapshot of the statistics of the run
+func (r *Run) Stats() Stats {
+	c := r.s.stats
+	return Stats{
+		Dirs:           c.dirs.Load(),
+		CachedDirs:     c.cachedDirs.Load(),
+		Entries:        c.entries.Load(),
+		Errors:         c.errors.Load(),
+		Bytes:          c.bytes.Load(),
+		PeakWorkers:    int(c.peakWorkers.Load()),
+		PeakGoroutines: int(c.peakGoroutines.Load()),
+	}
+}
+
+// visitDir counts a walked directory and samples the goroutine count
+func (c *statsCounter) visitDir() {
+	c.dirs.Add(1)
+	raisePeak(&c.peakGoroutines, int64(runtime.NumGoroutine()))
+}
+
+// startWork marks a worker busy, to be undone with endWork
+func (c *statsCounter) startWork() {
+	raisePeak(&c.peakWorkers, c.busy.Add(1))
+}
+
+// endWork marks a worker idle again
+func (c *statsCounter) endWork() {
+	c.busy.Add(-1)
+}
+
+// raisePeak stores n in peak if it is higher than the current value
+func raisePeak(peak *atomic.Int64, n int64) {
+	for {
+		old := peak.Load()
+		if n <= old || peak.CompareAndSwap(old, n) {
+			return
+		}
+	}
+}
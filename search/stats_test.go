@@ -0,0 +1,83 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunsKeepTheirOwnStats(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "sub/c.txt"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := New("*.txt", WithJobs(2), WithInfo(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first Stats
+	for i := 0; i < 3; i++ {
+		run := s.NewRun()
+		matches, err := run.Search(context.Background(), root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 3 {
+			t.Fatalf("run %d found %d matches, want 3", i, len(matches))
+		}
+		stats := run.Stats()
+		if i == 0 {
+			first = stats
+		} else if stats.Dirs != first.Dirs || stats.Entries != first.Entries || stats.Bytes != first.Bytes {
+			t.Errorf("run %d stats %+v, want %+v like the first run", i, stats, first)
+		}
+		if len(run.Errors()) != 0 {
+			t.Errorf("run %d errors: %v", i, run.Errors())
+		}
+	}
+	if first.Dirs != 2 || first.Entries < 4 || first.Bytes < 12 {
+		t.Errorf("stats %+v, want 2 dirs, at least 4 entries and 12 bytes", first)
+	}
+
+	// A run counts everything its methods do
+	run := s.NewRun()
+	for i := 0; i < 2; i++ {
+		if _, err := run.Search(context.Background(), root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := run.Stats().Entries; got != 2*first.Entries {
+		t.Errorf("two searches of a run examined %d entries, want %d", got, 2*first.Entries)
+	}
+}
+
+func TestRunsKeepTheirOwnErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	var handled []string
+	s, err := New("*", WithErrorHandler(func(path string, err error) { handled = append(handled, path) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		run := s.NewRun()
+		run.Search(context.Background(), missing)
+		errs := run.Errors()
+		if len(errs) != 1 || errs[0].Path != missing {
+			t.Errorf("run %d errors = %v, want one for %s", i, errs, missing)
+		}
+		if got := run.Stats().Errors; got != 1 {
+			t.Errorf("run %d counted %d errors, want 1", i, got)
+		}
+	}
+	if len(handled) != 2 {
+		t.Errorf("the handler saw %v, want the missing root twice", handled)
+	}
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Throttle limits the rate of some work, such as entries matched or bytes
+// read, to a number of units per second shared by every goroutine waiting
+// on it. A nil Throttle never waits.
+type Throttle struct {
+	mu      sync.Mutex
+	perUnit float64   // nanoseconds per unit
+	next    time.Time // when the next unit is due
+}
+
+// NewThrottle creates a throttle letting through perSecond units a second
+func NewThrottle(perSecond int64) *Throttle {
+	return &Throttle{perUnit: float64(time.Second) / float64(perSecond)}
+}
+
+// Wait blocks until n more units may go through. Time left unused while
+// nothing waits isn't saved up, so work resuming after a pause doesn't
+// burst past the rate.
+func (t *Throttle) Wait(n int) {
+	if t == nil || n <= 0 {
+		return
+	}
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(time.Duration(float64(n) * t.perUnit))
+	t.mu.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// Reader returns a reader whose reads wait on the throttle for each byte,
+// or r itself when t is nil
+func (t *Throttle) Reader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &throttledReader{r: r, t: t}
+}
+
+// throttledReader reads at the rate of a throttle
+type throttledReader struct {
+	r io.Reader
+	t *Throttle
+}
+
+// Largest read waited for at once, so that the rate holds over short spans
+const throttleChunk = 32 * 1024
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleChunk {
+		p = p[:throttleChunk]
+	}
+	n, err := r.r.Read(p)
+	r.t.Wait(n)
+	return n, err
+}
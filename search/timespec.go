@@ -0,0 +1,85 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration units accepted in time specs, beyond those of time.ParseDuration
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// Date layouts accepted in time specs
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseTimeSpec parses either a duration before now (e.g. "2d", "1h30m")
+// or an absolute date (e.g. "2024-01-01") into a point in time
+func ParseTimeSpec(spec string, now time.Time) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, spec, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	d, err := parseDuration(spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid duration or date: %s", spec)
+	}
+	return now.Add(-d), nil
+}
+
+// parseDuration parses a sequence of number and unit pairs such as "1d12h"
+func parseDuration(spec string) (time.Duration, error) {
+	if spec == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	var total time.Duration
+	rest := spec
+	for rest != "" {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		j := i
+		for j < len(rest) && (rest[j] < '0' || rest[j] > '9') {
+			j++
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", spec)
+		}
+		unit, ok := durationUnits[strings.ToLower(rest[i:j])]
+		if !ok {
+			return 0, fmt.Errorf("invalid duration unit in %s", spec)
+		}
+		total += time.Duration(n) * unit
+		rest = rest[j:]
+	}
+	return total, nil
+}
+
+// TimeFilter restricts matches to those on one side of a point in time
+type TimeFilter struct {
+	Newer bool // after At when true, before At otherwise
+	At    time.Time
+}
+
+// Match reports whether t satisfies the filter
+func (f TimeFilter) Match(t time.Time) bool {
+	if f.Newer {
+		return t.After(f.At)
+	}
+	return t.Before(f.At)
+}
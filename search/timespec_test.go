@@ -0,0 +1,64 @@
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeSpec(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		spec    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2d", now.Add(-48 * time.Hour), false},
+		{"1h30m", now.Add(-90 * time.Minute), false},
+		{"1d12h", now.Add(-36 * time.Hour), false},
+		{"2W", now.Add(-14 * 24 * time.Hour), false},
+		{"1y", now.Add(-365 * 24 * time.Hour), false},
+		{"45s", now.Add(-45 * time.Second), false},
+		{"0s", now, false},
+		{"2024-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), false},
+		{"2024-01-01 08:30:00", time.Date(2024, 1, 1, 8, 30, 0, 0, time.Local), false},
+		{"2024-01-01T08:30:00", time.Date(2024, 1, 1, 8, 30, 0, 0, time.Local), false},
+		{"2024-01-01T08:30:00Z", time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC), false},
+		{"", time.Time{}, true},
+		{"5", time.Time{}, true},
+		{"d", time.Time{}, true},
+		{"2x", time.Time{}, true},
+		{"1.5h", time.Time{}, true},
+		{"-2d", time.Time{}, true},
+		{"2024-13-01", time.Time{}, true},
+		{"2024/01/01", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeSpec(tt.spec, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimeSpec(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTimeSpec(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestTimeFilter(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		filter TimeFilter
+		t      time.Time
+		match  bool
+	}{
+		{TimeFilter{Newer: true, At: at}, at.Add(time.Second), true},
+		{TimeFilter{Newer: true, At: at}, at, false},
+		{TimeFilter{Newer: false, At: at}, at.Add(-time.Second), true},
+		{TimeFilter{Newer: false, At: at}, at, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(tt.t); got != tt.match {
+			t.Errorf("%+v matching %v = %v, want %v", tt.filter, tt.t, got, tt.match)
+		}
+	}
+}
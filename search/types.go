@@ -0,0 +1,142 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// TypeSet is a set of entry types to return. Kinds (file, dir, symlink,
+// socket, pipe) are alternatives: an entry of any listed kind matches.
+// Properties (executable, empty, broken) must hold in addition to the kind.
+type TypeSet uint
+
+// Entry kinds and properties that can be combined in a TypeSet
+const (
+	TypeFileKind TypeSet = 1 << iota
+	TypeDirKind
+	TypeSymlinkKind
+	TypeSocketKind
+	TypePipeKind
+	TypeExecutable
+	TypeEmpty
+	TypeBroken // a symlink whose target doesn't exist
+)
+
+// Bits of a TypeSet that identify kinds rather than properties
+const typeKinds = TypeFileKind | TypeDirKind | TypeSymlinkKind | TypeSocketKind | TypePipeKind
+
+// Names accepted by ParseTypeSet, by short letter and long name
+var typeNames = map[string]TypeSet{
+	"f": TypeFileKind, "file": TypeFileKind,
+	"d": TypeDirKind, "dir": TypeDirKind, "directory": TypeDirKind,
+	"l": TypeSymlinkKind, "symlink": TypeSymlinkKind,
+	"s": TypeSocketKind, "socket": TypeSocketKind,
+	"p": TypePipeKind, "pipe": TypePipeKind,
+	"x": TypeExecutable, "executable": TypeExecutable,
+	"e": TypeEmpty, "empty": TypeEmpty,
+	"b": TypeBroken, "broken": TypeBroken, "broken-symlink": TypeBroken,
+}
+
+// ParseTypeSet parses a comma separated list of types such as "f,l" or "d,e"
+func ParseTypeSet(spec string) (TypeSet, error) {
+	var set TypeSet
+	for _, name := range strings.Split(spec, ",") {
+		t, ok := typeNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return 0, fmt.Errorf("unknown type: %s", name)
+		}
+		set |= t
+	}
+	return set, nil
+}
+
+// Kinds returns only the kind bits of the set
+func (t TypeSet) Kinds() TypeSet {
+	return t & typeKinds
+}
+
+// matchesKind reports whether an entry of the given mode has one of the
+// kinds in the set, allowing all kinds when none are listed. With no kinds
+// an executable filter implies files, and a broken filter symlinks.
+func (t TypeSet) matchesKind(mode fs.FileMode) bool {
+	kinds := t.Kinds()
+	if kinds == 0 {
+		if t&(TypeExecutable|TypeBroken) == 0 {
+			return true
+		}
+		if t&TypeExecutable != 0 {
+			kinds |= TypeFileKind
+		}
+		if t&TypeBroken != 0 {
+			kinds |= TypeSymlinkKind
+		}
+	}
+	switch {
+	case mode.IsDir():
+		return kinds&TypeDirKind != 0
+	case mode&fs.ModeSymlink != 0:
+		return kinds&TypeSymlinkKind != 0
+	case mode&fs.ModeSocket != 0:
+		return kinds&TypeSocketKind != 0
+	case mode&fs.ModeNamedPipe != 0:
+		return kinds&TypePipeKind != 0
+	case mode.IsRegular():
+		return kinds&TypeFileKind != 0
+	}
+	return false
+}
+
+// matches reports whether a match satisfies both the kinds and properties
+func (t TypeSet) matches(match Match) bool {
+	if t == 0 {
+		return true
+	}
+	if !t.matchesKind(match.Mode) {
+		return false
+	}
+	if t&TypeExecutable != 0 && !match.IsExecutable() {
+		return false
+	}
+	if t&TypeEmpty != 0 && !isEmpty(match) {
+		return false
+	}
+	if t&TypeBroken != 0 && !isBrokenSymlink(match) {
+		return false
+	}
+	return true
+}
+
+// isBrokenSymlink reports whether a match is a symlink whose target
+// doesn't exist
+func isBrokenSymlink(match Match) bool {
+	if match.Type != TypeSymlink {
+		return false
+	}
+	if _, err := os.Readlink(match.fsPath()); err != nil {
+		return false
+	}
+	_, err := os.Stat(match.fsPath())
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// isEmpty reports whether a match is a zero-byte file or a directory
+// without entries
+func isEmpty(match Match) bool {
+	switch {
+	case match.Type == TypeFile:
+		info, err := match.Info()
+		return err == nil && info.Size() == 0
+	case match.IsDir():
+		dir, err := os.Open(match.fsPath())
+		if err != nil {
+			return false
+		}
+		defer dir.Close()
+		names, _ := dir.Readdirnames(1)
+		return len(names) == 0
+	}
+	return false
+}
@@ -0,0 +1,315 @@
+package search
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultMaxSymlinkDepth is the default limit on the number of symlinks
+// followed along a single path
+const DefaultMaxSymlinkDepth = 40
+
+// ErrSymlinkDepth is reported for links nested deeper than the symlink limit
+var ErrSymlinkDepth = errors.New("too many levels of symbolic links")
+
+// fileID identifies a file independently of the path used to reach it
+type fileID struct {
+	device uint64
+	inode  uint64
+}
+
+// walkFunc is called for every entry of the walk, like fs.WalkDirFunc, with
+// the ignore rules in effect in the entry's directory. For a directory it
+// returns the stack handed on to the entries below it. Calls are made
+// concurrently from several goroutines.
+type walkFunc func(path string, d fs.DirEntry, ignores *ignoreStack, err error) (*ignoreStack, error)
+
+// walker traverses a directory tree with several goroutines reading
+// directories in parallel, optionally following symbolic links while
+// guarding against cycles
+type walker struct {
+	jobs            int
+	follow          bool
+	breadthFirst    bool
+	maxSymlinkDepth int
+	maxDirEntries   int // directories with more entries aren't descended into, unless 0
+	onError         func(path string, err error)
+	cache           ListingCache
+	stats           *statsCounter
+
+	mu      sync.Mutex
+	visited map[fileID]bool
+}
+
+// newWalker creates a walker configured from the searcher
+func newWalker(s *Searcher) *walker {
+	return &walker{
+		jobs:            s.jobs,
+		follow:          s.follow,
+		breadthFirst:    s.breadthFirst,
+		maxSymlinkDepth: s.maxSymlinkDepth,
+		maxDirEntries:   s.maxDirEntries,
+		visited:         make(map[fileID]bool),
+		onError:         s.reportError,
+		cache:           s.listingCache,
+		stats:           s.stats,
+	}
+}
+
+// dirJob is a directory waiting to be read
+type dirJob struct {
+	path     string
+	symlinks int // links followed to reach the directory
+	ignores  *ignoreStack
+	root     bool
+}
+
+// Walk calls fn for root and every entry below it. Returning
+// filepath.SkipDir for a directory skips its contents and filepath.SkipAll
+// ends the walk; any other error ends it and is returned. Entries of a
+// directory are visited in order, but directories are read concurrently,
+// so the overall order is not deterministic.
+func (w *walker) Walk(root string, ignores *ignoreStack, fn walkFunc) error {
+	stat := os.Lstat
+	if w.follow {
+		stat = os.Stat
+	}
+	info, err := stat(root)
+	if err != nil {
+		_, err = fn(root, nil, ignores, err)
+		return skipToNil(err)
+	}
+	d := asLink(root, fs.FileInfoToDirEntry(info))
+	if ignores, err = fn(root, d, ignores, nil); err != nil || !d.IsDir() {
+		return skipToNil(err)
+	}
+
+	queue := newWorkQueue(max(w.jobs, 1), w.breadthFirst)
+	queue.push(0, dirJob{path: root, ignores: ignores, root: true})
+	var wg sync.WaitGroup
+	for i := range queue.deques {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for {
+				job, ok := queue.pop(worker)
+				if !ok {
+					return
+				}
+				if err := w.readDir(queue, worker, job, fn); err != nil {
+					queue.abort(skipToNil(err))
+				}
+				queue.finish()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return queue.err
+}
+
+// readDir visits the entries of a directory, queueing its subdirectories
+func (w *walker) readDir(queue *workQueue, worker int, job dirJob, fn walkFunc) error {
+	if w.follow {
+		w.markVisited(job.path)
+	}
+	// Huge directories below the root are left unread, as if pruned
+	if w.maxDirEntries > 0 && !job.root && hasMoreEntries(job.path, w.maxDirEntries) {
+		return nil
+	}
+	entries, err := w.readEntries(job.path)
+	if err != nil {
+		// Report the read error, as filepath.WalkDir does
+		if _, err = fn(job.path, nil, job.ignores, err); err != nil && err != filepath.SkipDir {
+			return err
+		}
+	}
+
+	var subdirs []dirJob
+	for _, entry := range entries {
+		child := filepath.Join(job.path, entry.Name())
+		entry = asLink(child, entry)
+		depth := job.symlinks
+		if w.follow && entry.Type()&fs.ModeSymlink != 0 {
+			entry, depth = w.resolve(child, entry, job.symlinks)
+		}
+		ignores, err := fn(child, entry, job.ignores, nil)
+		if err == filepath.SkipDir {
+			if entry.IsDir() {
+				continue
+			}
+			break // skip the remaining entries of the directory
+		}
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			subdirs = append(subdirs, dirJob{path: child, symlinks: depth, ignores: ignores})
+		}
+	}
+
+	if queue.fifo {
+		for _, subdir := range subdirs {
+			queue.push(worker, subdir)
+		}
+		return nil
+	}
+	// Queued last first, so this worker continues with the first one
+	for i := len(subdirs) - 1; i >= 0; i-- {
+		queue.push(worker, subdirs[i])
+	}
+	return nil
+}
+
+// hasMoreEntries reports whether a directory holds more than n entries,
+// reading no more names than it takes to tell
+func hasMoreEntries(path string, n int) bool {
+	dir, err := os.Open(path)
+	if err != nil {
+		return false // reading the directory reports the error
+	}
+	defer dir.Close()
+	names, _ := dir.Readdirnames(n + 1)
+	return len(names) > n
+}
+
+// resolve follows a symlink entry, returning the entry of its target.
+// Broken links, links to directories already walked and links beyond the
+// depth limit are returned unchanged so they are reported but not entered.
+func (w *walker) resolve(path string, entry fs.DirEntry, symlinks int) (fs.DirEntry, int) {
+	// System junctions on Windows are reported but never entered
+	if isSystemLink(path) {
+		return entry, symlinks
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return entry, symlinks
+	}
+	if !info.IsDir() {
+		return fs.FileInfoToDirEntry(info), symlinks
+	}
+	if symlinks+1 > w.maxSymlinkDepth {
+		w.onError(path, ErrSymlinkDepth)
+		return entry, symlinks
+	}
+	// Claim the directory now, so that two links to it read in parallel
+	// don't both enter it
+	if id, ok := getFileID(path); ok {
+		w.mu.Lock()
+		seen := w.visited[id]
+		w.visited[id] = true
+		w.mu.Unlock()
+		if seen {
+			return entry, symlinks // Symlink cycle or a directory seen before
+		}
+	}
+	return fs.FileInfoToDirEntry(info), symlinks + 1
+}
+
+// markVisited records a directory as walked
+func (w *walker) markVisited(path string) {
+	if id, ok := getFileID(path); ok {
+		w.mu.Lock()
+		w.visited[id] = true
+		w.mu.Unlock()
+	}
+}
+
+// skipToNil maps the errors that only end a walk early to nil
+func skipToNil(err error) error {
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// workQueue hands out directories to the walk workers. Each worker takes
+// the directories it queued itself from the back of its own deque, depth
+// first, and when it runs out steals the oldest directory, likely the
+// largest subtree, from the front of another worker's deque. A FIFO queue
+// instead shares a single queue, read in the order directories were found,
+// breadth first.
+type workQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	deques  [][]dirJob
+	fifo    bool
+	pending int // directories queued or being read
+	done    bool
+	err     error
+}
+
+func newWorkQueue(workers int, fifo bool) *workQueue {
+	q := &workQueue{deques: make([][]dirJob, workers), fifo: fifo}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push queues a directory on a worker's deque
+func (q *workQueue) push(worker int, job dirJob) {
+	q.mu.Lock()
+	if q.fifo {
+		worker = 0
+	}
+	q.deques[worker] = append(q.deques[worker], job)
+	q.pending++
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// pop waits for a directory for the worker, reporting false once the walk
+// is over
+func (q *workQueue) pop(worker int) (dirJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for !q.done {
+		if q.fifo {
+			if queue := q.deques[0]; len(queue) > 0 {
+				job := queue[0]
+				q.deques[0] = queue[1:]
+				return job, true
+			}
+			q.cond.Wait()
+			continue
+		}
+		if own := q.deques[worker]; len(own) > 0 {
+			job := own[len(own)-1]
+			q.deques[worker] = own[:len(own)-1]
+			return job, true
+		}
+		for i := 1; i < len(q.deques); i++ {
+			victim := (worker + i) % len(q.deques)
+			if other := q.deques[victim]; len(other) > 0 {
+				job := other[0]
+				q.deques[victim] = other[1:]
+				return job, true
+			}
+		}
+		q.cond.Wait()
+	}
+	return dirJob{}, false
+}
+
+// finish marks a popped directory as read, ending the walk after the last
+func (q *workQueue) finish() {
+	q.mu.Lock()
+	q.pending--
+	if q.pending == 0 {
+		q.done = true
+		q.cond.Broadcast()
+	}
+	q.mu.Unlock()
+}
+
+// abort ends the walk early, keeping the first error
+func (q *workQueue) abort(err error) {
+	q.mu.Lock()
+	if q.err == nil {
+		q.err = err
+	}
+	q.done = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+}
@@ -0,0 +1,271 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// wideTree writes dirs directories of files files each under root, every
+// other one holding a subdirectory with a file of its own, and returns the
+// number of entries below root
+func wideTree(t *testing.T, root string, dirs, files int) int {
+	t.Helper()
+	tree := map[string]string{}
+	for d := range dirs {
+		for f := range files {
+			tree[fmt.Sprintf("d%02d/f%02d", d, f)] = ""
+		}
+		if d%2 == 0 {
+			tree[fmt.Sprintf("d%02d/sub/leaf", d)] = ""
+		}
+	}
+	writeTree(t, root, tree)
+	return dirs*(files+1) + (dirs+1)/2*2
+}
+
+// walkPaths walks root and returns the slash paths visited below it, in
+// the order of the calls, failing on any path visited twice
+func walkPaths(t *testing.T, w *walker, root string, fn func(rel string, d fs.DirEntry) error) ([]string, error) {
+	t.Helper()
+	if w.visited == nil {
+		w.visited = make(map[fileID]bool)
+	}
+	if w.onError == nil {
+		w.onError = func(path string, err error) { t.Errorf("%s: %v", path, err) }
+	}
+	var mu sync.Mutex
+	var paths []string
+	err := w.Walk(root, nil, func(path string, d fs.DirEntry, ignores *ignoreStack, err error) (*ignoreStack, error) {
+		if err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		mu.Lock()
+		if slices.Contains(paths, rel) {
+			t.Errorf("%s visited twice", rel)
+		}
+		paths = append(paths, rel)
+		mu.Unlock()
+		if fn != nil && rel != "." {
+			return nil, fn(rel, d)
+		}
+		return nil, nil
+	})
+	return paths, err
+}
+
+func TestWalkVisitsEveryEntryOnce(t *testing.T) {
+	root := t.TempDir()
+	want := wideTree(t, root, 40, 5)
+	for _, jobs := range []int{1, 2, 8, 32} {
+		for _, bfs := range []bool{false, true} {
+			paths, err := walkPaths(t, &walker{jobs: jobs, breadthFirst: bfs}, root, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(paths) != want+1 {
+				t.Errorf("%d jobs, breadth first %v: visited %d paths, want %d", jobs, bfs, len(paths), want+1)
+			}
+		}
+	}
+}
+
+func TestWalkBreadthFirst(t *testing.T) {
+	// With one worker the FIFO queue reads directories level by level
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a/b/c/deep": "", "x/y": "", "top": ""})
+	paths, err := walkPaths(t, &walker{jobs: 1, breadthFirst: true}, root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	depth := 0
+	for _, p := range paths[1:] {
+		d := pathDepth(p)
+		if d < depth {
+			t.Fatalf("%s at depth %d visited after depth %d: %q", p, d, depth, paths)
+		}
+		depth = d
+	}
+}
+
+func TestWalkSkipDir(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"skipped/a":      "",
+		"skipped/deep/b": "",
+		"kept/a":         "",
+		"files/1":        "",
+		"files/2":        "",
+		"files/3":        "",
+	})
+	paths, err := walkPaths(t, &walker{jobs: 4}, root, func(rel string, d fs.DirEntry) error {
+		// SkipDir for a directory skips its contents, for a file the rest
+		// of its directory, read in name order
+		if rel == "skipped" || rel == "files/2" {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(paths)
+	want := []string{".", "files", "files/1", "files/2", "kept", "kept/a", "skipped"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("visited %q, want %q", paths, want)
+	}
+}
+
+func TestWalkSkipAllAndErrors(t *testing.T) {
+	root := t.TempDir()
+	wideTree(t, root, 10, 10)
+	errStop := errors.New("stop")
+	for _, stop := range []error{filepath.SkipAll, errStop} {
+		paths, err := walkPaths(t, &walker{jobs: 1}, root, func(rel string, d fs.DirEntry) error {
+			if rel == "d00/f03" {
+				return stop
+			}
+			return nil
+		})
+		// With a single worker nothing is visited after the entry stopping it
+		if got := paths[len(paths)-1]; got != "d00/f03" {
+			t.Errorf("%v: walk went on to %s", stop, got)
+		}
+		if stop == filepath.SkipAll && err != nil {
+			t.Errorf("SkipAll ended the walk with %v", err)
+		}
+		if stop == errStop && !errors.Is(err, errStop) {
+			t.Errorf("walk error = %v, want %v", err, errStop)
+		}
+	}
+
+	// Many workers stop too, returning the error
+	_, err := walkPaths(t, &walker{jobs: 8}, root, func(rel string, d fs.DirEntry) error {
+		if rel == "d05" {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("walk error with 8 workers = %v, want %v", err, errStop)
+	}
+}
+
+func TestWalkMissingRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "missing")
+	var got error
+	err := (&walker{jobs: 2}).Walk(root, nil, func(path string, d fs.DirEntry, ignores *ignoreStack, err error) (*ignoreStack, error) {
+		got = err
+		return nil, nil
+	})
+	if err != nil || !errors.Is(got, fs.ErrNotExist) {
+		t.Errorf("walk of a missing root = %v, reporting %v", err, got)
+	}
+}
+
+func TestWalkSymlinkCycles(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a/file": "", "a/deeper/": "", "b/file": ""})
+	// Links back to a directory being walked, which is always read before
+	// the directories below it
+	links := map[string]string{
+		"a/up":          "..",
+		"a/self":        ".",
+		"a/deeper/back": "../..",
+		"dangles":       "missing",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Skip("cannot create symbolic links:", err)
+		}
+	}
+
+	for _, jobs := range []int{1, 8} {
+		paths, err := walkPaths(t, &walker{jobs: jobs, follow: true, maxSymlinkDepth: DefaultMaxSymlinkDepth}, root, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The links are visited, but not entered
+		slices.Sort(paths)
+		want := []string{".", "a", "a/deeper", "a/deeper/back", "a/file", "a/self", "a/up", "b", "b/file", "dangles"}
+		if !slices.Equal(paths, want) {
+			t.Errorf("%d jobs: visited %q, want %q", jobs, paths, want)
+		}
+	}
+}
+
+func TestWalkSymlinkDepth(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeTree(t, outside, map[string]string{"one/file": "", "two/file": ""})
+	if err := os.Symlink(filepath.Join(outside, "one"), filepath.Join(root, "l1")); err != nil {
+		t.Skip("cannot create symbolic links:", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "two"), filepath.Join(outside, "one", "l2")); err != nil {
+		t.Fatal(err)
+	}
+
+	var reported []string
+	w := &walker{jobs: 1, follow: true, maxSymlinkDepth: 1, onError: func(path string, err error) {
+		if errors.Is(err, ErrSymlinkDepth) {
+			reported = append(reported, filepath.Base(path))
+		}
+	}}
+	paths, err := walkPaths(t, w, root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(paths)
+	if want := []string{".", "l1", "l1/file", "l1/l2"}; !slices.Equal(paths, want) {
+		t.Errorf("visited %q, want %q", paths, want)
+	}
+	if !slices.Equal(reported, []string{"l2"}) {
+		t.Errorf("links reported too deep: %q, want l2", reported)
+	}
+}
+
+func TestSearchCancelled(t *testing.T) {
+	root := t.TempDir()
+	total := wideTree(t, root, 20, 20)
+	s, err := New("*", WithJobs(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	matches, err := s.Search(ctx, root)
+	if !errors.Is(err, context.Canceled) || len(matches) != 0 {
+		t.Errorf("search cancelled beforehand = %d matches, %v", len(matches), err)
+	}
+
+	// Cancelling midway closes the channels, without a match left waiting
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	results, errc := s.Stream(ctx, root)
+	<-results
+	cancel()
+	for range results {
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("search cancelled midway ended with %v", err)
+	}
+
+	// Enough matches end the walk without an error
+	s, err = New("*", WithJobs(4), WithMaxResults(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err = s.Search(context.Background(), root)
+	if err != nil || len(matches) != 5 {
+		t.Errorf("search for 5 of %d entries = %d matches, %v", total, len(matches), err)
+	}
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// errXattrMissing is returned by getXattr for an attribute the file lacks
+var errXattrMissing = errors.New("no such attribute")
+
+// XattrFilter requires an extended attribute, with a given value or any,
+// such as "user.origin=mirror" or "com.apple.quarantine"
+type XattrFilter struct {
+	Name     string
+	Value    string
+	HasValue bool // only match the Value, not any value
+}
+
+// ParseXattrFilter parses name or name=value
+func ParseXattrFilter(expr string) (XattrFilter, error) {
+	name, value, hasValue := strings.Cut(expr, "=")
+	if name == "" {
+		return XattrFilter{}, fmt.Errorf("invalid extended attribute: %q", expr)
+	}
+	return XattrFilter{Name: name, Value: value, HasValue: hasValue}, nil
+}
+
+// XattrsSupported reports whether extended attributes can be read on this
+// system: Linux and macOS
+func XattrsSupported() bool {
+	return xattrSupported
+}
+
+// checkXattrSupport fails on systems whose extended attributes aren't read
+func checkXattrSupport() error {
+	if !xattrSupported {
+		return fmt.Errorf("extended attributes are not supported on %s", runtime.GOOS)
+	}
+	return nil
+}
+
+// passesXattrFilters reports whether a match has every attribute the
+// filters ask for
+func (s *Searcher) passesXattrFilters(match Match) bool {
+	for _, filter := range s.xattrFilters {
+		value, err := getXattr(match.fsPath(), filter.Name)
+		if errors.Is(err, errXattrMissing) {
+			return false
+		}
+		if err != nil {
+			s.reportError(match.Path, err)
+			return false
+		}
+		if filter.HasValue && string(value) != filter.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadXattrs returns the extended attributes of the file at path, not
+// following a symbolic link. Files on filesystems without extended
+// attributes have none.
+func ReadXattrs(path string) (map[string]string, error) {
+	if err := checkXattrSupport(); err != nil {
+		return nil, err
+	}
+	names, err := listXattrs(path)
+	if err != nil {
+		return nil, err
+	}
+	var attrs map[string]string
+	for _, name := range names {
+		value, err := getXattr(path, name)
+		if errors.Is(err, errXattrMissing) {
+			continue // removed since listed
+		}
+		if err != nil {
+			return nil, err
+		}
+		if attrs == nil {
+			attrs = make(map[string]string, len(names))
+		}
+		attrs[name] = string(value)
+	}
+	return attrs, nil
+}
+
+// splitXattrNames splits the NUL terminated names filled in by listxattr
+func splitXattrNames(buf []byte) []string {
+	var names []string
+	for _, name := range strings.Split(string(buf), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
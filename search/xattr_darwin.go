@@ -0,0 +1,24 @@
+package search
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Returned for an attribute the file lacks
+const errnoNoAttr = syscall.ENOATTR
+
+// XATTR_NOFOLLOW, for the attributes of a symbolic link itself
+const xattrNoFollow = 0x1
+
+func lgetxattr(path, name *byte, buf []byte) (uintptr, syscall.Errno) {
+	r, _, errno := syscall.Syscall6(syscall.SYS_GETXATTR, uintptr(unsafe.Pointer(path)), uintptr(unsafe.Pointer(name)),
+		uintptr(bufPtr(buf)), uintptr(len(buf)), 0, xattrNoFollow)
+	return r, errno
+}
+
+func llistxattr(path *byte, buf []byte) (uintptr, syscall.Errno) {
+	r, _, errno := syscall.Syscall6(syscall.SYS_LISTXATTR, uintptr(unsafe.Pointer(path)), uintptr(bufPtr(buf)), uintptr(len(buf)),
+		xattrNoFollow, 0, 0)
+	return r, errno
+}
@@ -0,0 +1,20 @@
+package search
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Returned for an attribute the file lacks
+const errnoNoAttr = syscall.ENODATA
+
+func lgetxattr(path, name *byte, buf []byte) (uintptr, syscall.Errno) {
+	r, _, errno := syscall.Syscall6(syscall.SYS_LGETXATTR, uintptr(unsafe.Pointer(path)), uintptr(unsafe.Pointer(name)),
+		uintptr(bufPtr(buf)), uintptr(len(buf)), 0, 0)
+	return r, errno
+}
+
+func llistxattr(path *byte, buf []byte) (uintptr, syscall.Errno) {
+	r, _, errno := syscall.Syscall(syscall.SYS_LLISTXATTR, uintptr(unsafe.Pointer(path)), uintptr(bufPtr(buf)), uintptr(len(buf)))
+	return r, errno
+}
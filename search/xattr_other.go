@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package search
+
+const xattrSupported = false
+
+func getXattr(path, name string) ([]byte, error) {
+	return nil, checkXattrSupport()
+}
+
+func listXattrs(path string) ([]string, error) {
+	return nil, checkXattrSupport()
+}
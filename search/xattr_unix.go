@@ -0,0 +1,72 @@
+//go:build linux || darwin
+
+package search
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const xattrSupported = true
+
+// getXattr returns the value of an extended attribute of the file at path,
+// not following a symbolic link
+func getXattr(path, name string) ([]byte, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	n, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	return readXattrBuf(path, func(buf []byte) (uintptr, syscall.Errno) { return lgetxattr(p, n, buf) })
+}
+
+// listXattrs returns the names of the extended attributes of the file at
+// path, not following a symbolic link
+func listXattrs(path string) ([]string, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := readXattrBuf(path, func(buf []byte) (uintptr, syscall.Errno) { return llistxattr(p, buf) })
+	if err == errXattrMissing {
+		return nil, nil // a filesystem without extended attributes
+	}
+	return splitXattrNames(buf), err
+}
+
+// readXattrBuf calls an xattr syscall first to size the buffer, then to
+// fill it, again if the value grew in between. Filesystems without
+// extended attributes are treated as attributes the file lacks.
+func readXattrBuf(path string, call func(buf []byte) (uintptr, syscall.Errno)) ([]byte, error) {
+	for {
+		size, errno := call(nil)
+		if errno == 0 && size > 0 {
+			buf := make([]byte, size)
+			if size, errno = call(buf); errno == 0 {
+				return buf[:size], nil
+			}
+			if errno == syscall.ERANGE {
+				continue
+			}
+		}
+		switch errno {
+		case 0:
+			return nil, nil
+		case errnoNoAttr, syscall.ENOTSUP:
+			return nil, errXattrMissing
+		}
+		return nil, &os.PathError{Op: "getxattr", Path: path, Err: errno}
+	}
+}
+
+// bufPtr returns the address of a buffer for a syscall, nil when empty
+func bufPtr(buf []byte) unsafe.Pointer {
+	if len(buf) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&buf[0])
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Primes of the XXH64 algorithm
+const (
+	xxhPrime1 uint64 = 11400714785074694791
+	xxhPrime2 uint64 = 14029467366897019727
+	xxhPrime3 uint64 = 1609587929392839161
+	xxhPrime4 uint64 = 9650029242287828579
+	xxhPrime5 uint64 = 2870177450012600261
+)
+
+// xxh64 computes the 64-bit xxHash of its input with a seed of 0. It is
+// much faster than the cryptographic hashes, for spotting changed or
+// duplicate files rather than tampered ones.
+type xxh64 struct {
+	v     [4]uint64 // accumulators of the 32 byte stripes
+	total uint64    // bytes written
+	buf   [32]byte  // pending bytes of an incomplete stripe
+	n     int       // bytes pending in buf
+}
+
+func newXXH64() hash.Hash64 {
+	h := &xxh64{}
+	h.Reset()
+	return h
+}
+
+func (h *xxh64) Reset() {
+	// The sums wrap around, which constant expressions aren't allowed to
+	prime1, prime2 := xxhPrime1, xxhPrime2
+	h.v = [4]uint64{prime1 + prime2, prime2, 0, -prime1}
+	h.total = 0
+	h.n = 0
+}
+
+func (h *xxh64) Size() int      { return 8 }
+func (h *xxh64) BlockSize() int { return 32 }
+
+func (h *xxh64) Write(p []byte) (int, error) {
+	written := len(p)
+	h.total += uint64(written)
+	if h.n > 0 {
+		copied := copy(h.buf[h.n:], p)
+		h.n += copied
+		p = p[copied:]
+		if h.n < len(h.buf) {
+			return written, nil
+		}
+		h.stripe(h.buf[:])
+		h.n = 0
+	}
+	for len(p) >= 32 {
+		h.stripe(p[:32])
+		p = p[32:]
+	}
+	h.n = copy(h.buf[:], p)
+	return written, nil
+}
+
+// stripe folds 32 bytes into the accumulators
+func (h *xxh64) stripe(p []byte) {
+	for i := range h.v {
+		h.v[i] = xxhRound(h.v[i], binary.LittleEndian.Uint64(p[i*8:]))
+	}
+}
+
+func (h *xxh64) Sum64() uint64 {
+	var sum uint64
+	if h.total >= 32 {
+		v := h.v
+		sum = bits.RotateLeft64(v[0], 1) + bits.RotateLeft64(v[1], 7) +
+			bits.RotateLeft64(v[2], 12) + bits.RotateLeft64(v[3], 18)
+		for _, acc := range v {
+			sum = (sum^xxhRound(0, acc))*xxhPrime1 + xxhPrime4
+		}
+	} else {
+		sum = xxhPrime5
+	}
+	sum += h.total
+
+	p := h.buf[:h.n]
+	for ; len(p) >= 8; p = p[8:] {
+		sum ^= xxhRound(0, binary.LittleEndian.Uint64(p))
+		sum = bits.RotateLeft64(sum, 27)*xxhPrime1 + xxhPrime4
+	}
+	if len(p) >= 4 {
+		sum ^= uint64(binary.LittleEndian.Uint32(p)) * xxhPrime1
+		sum = bits.RotateLeft64(sum, 23)*xxhPrime2 + xxhPrime3
+		p = p[4:]
+	}
+	for _, b := range p {
+		sum ^= uint64(b) * xxhPrime5
+		sum = bits.RotateLeft64(sum, 11) * xxhPrime1
+	}
+
+	sum ^= sum >> 33
+	sum *= xxhPrime2
+	sum ^= sum >> 29
+	sum *= xxhPrime3
+	sum ^= sum >> 32
+	return sum
+}
+
+// Sum appends the digest in its canonical big-endian form, as printed by
+// xxhsum
+func (h *xxh64) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, h.Sum64())
+}
+
+func xxhRound(acc, input uint64) uint64 {
+	acc += input * xxhPrime2
+	return bits.RotateLeft64(acc, 31) * xxhPrime1
+}